@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/peano88/medias/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// cli holds the state shared by the commands: the flags overriding the
+// configuration, then the configuration and the logger built from them
+type cli struct {
+	env        string
+	configPath string
+	sets       []string
+	// bindings maps the flags to the configuration keys they override
+	bindings []flagBinding
+
+	cfg    *applicationConfig
+	logger *slog.Logger
+	// logLevel is the level of logger, changed by the reloads of log.level
+	logLevel slog.LevelVar
+}
+
+type flagBinding struct {
+	flags *pflag.FlagSet
+	name  string
+	key   string
+}
+
+func newRootCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "media_managment_service",
+		Short: "Media management service",
+		Long: `Manages media files and their tags, stored in postgres and S3.
+
+Every configuration key can be overridden with --set key=value, which takes
+precedence over the environment variables and the config file.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.load()
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&c.env, "env", "", "Environment, selecting the config.<env>.yaml file (defaults to $ENV, then dev)")
+	cmd.PersistentFlags().StringVar(&c.configPath, "config-path", "", "Directory of the config files (defaults to $CONFIG_PATH)")
+	cmd.PersistentFlags().StringArrayVar(&c.sets, "set", nil, "Override a configuration key, as key=value (repeatable)")
+	cmd.PersistentFlags().String("db-host", "", "Database host (database.host)")
+	cmd.PersistentFlags().Int("db-port", 0, "Database port (database.port)")
+	cmd.PersistentFlags().String("db-name", "", "Database name (database.database)")
+	cmd.PersistentFlags().String("db-user", "", "Database user (database.user)")
+	c.bindFlag(cmd.PersistentFlags(), "db-host", "database.host")
+	c.bindFlag(cmd.PersistentFlags(), "db-port", "database.port")
+	c.bindFlag(cmd.PersistentFlags(), "db-name", "database.database")
+	c.bindFlag(cmd.PersistentFlags(), "db-user", "database.user")
+
+	cmd.AddCommand(serveCmd(c))
+	cmd.AddCommand(migrateCmd(c))
+	cmd.AddCommand(seedCmd(c))
+	cmd.AddCommand(configCmd(c))
+
+	return cmd
+}
+
+// bindFlag makes the flag name of flags override the configuration key, when
+// it is set on the command line
+func (c *cli) bindFlag(flags *pflag.FlagSet, name, key string) {
+	c.bindings = append(c.bindings, flagBinding{flags: flags, name: name, key: key})
+}
+
+// overrides collects the configuration keys set through the flags
+func (c *cli) overrides() (map[string]any, error) {
+	overrides := map[string]any{}
+	for _, binding := range c.bindings {
+		if flag := binding.flags.Lookup(binding.name); flag != nil && flag.Changed {
+			overrides[binding.key] = flag.Value.String()
+		}
+	}
+	// the explicit keys win over the dedicated flags
+	for _, set := range c.sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set %q, expecting key=value", set)
+		}
+		overrides[key] = value
+	}
+	return overrides, nil
+}
+
+// load loads the configuration and creates the logger
+func (c *cli) load() error {
+	overrides, err := c.overrides()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfig(overrides, config.WithEnv(c.env), config.WithConfigPath(c.configPath))
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	c.cfg = cfg
+	// an invalid level is reported by the validation of the commands
+	if level, err := cfg.Log.level(); err == nil {
+		c.logLevel.Set(level)
+	}
+	c.logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: &c.logLevel})).With(
+		slog.String("app", appName),
+		slog.String("version", appVersion),
+		slog.String("env", cfg.Env()),
+	)
+	return nil
+}
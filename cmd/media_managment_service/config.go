@@ -1,37 +1,328 @@
 package main
 
 import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+
 	"github.com/peano88/medias/config"
 	"github.com/peano88/medias/internal/adapters/filestorage/s3"
+	"github.com/peano88/medias/internal/adapters/jwtauth"
+	"github.com/peano88/medias/internal/adapters/metrics/prometheus"
+	"github.com/peano88/medias/internal/adapters/ratelimit"
 	"github.com/peano88/medias/internal/adapters/storage/postgres"
+	"github.com/peano88/medias/internal/adapters/tlscert"
+	"github.com/peano88/medias/internal/adapters/tracing"
+	"github.com/peano88/medias/internal/app/finalizemedia"
 )
 
 // application config holds all application configuration
 type applicationConfig struct {
 	*config.Config
-	Server   ServerConfig    `mapstructure:"server"`
-	Database postgres.Config `mapstructure:"database"`
-	S3       s3.Config       `mapstructure:"s3"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Admin       AdminConfig       `mapstructure:"admin"`
+	Log         LogConfig         `mapstructure:"log"`
+	Media       MediaConfig       `mapstructure:"media"`
+	Database    postgres.Config   `mapstructure:"database"`
+	S3          s3.Config         `mapstructure:"s3"`
+	Jobs        JobsConfig        `mapstructure:"jobs"`
+	Batch       BatchConfig       `mapstructure:"batch"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	RateLimit   ratelimit.Config  `mapstructure:"rate-limit"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	Tracing     tracing.Config    `mapstructure:"tracing"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Port                   int `mapstructure:"listen-port"`
 	ShutdownTimeoutSeconds int `mapstructure:"shutdown-timeout-seconds"`
+	// HealthCheckTimeoutSeconds bounds each check of the readiness probe
+	HealthCheckTimeoutSeconds int `mapstructure:"health-check-timeout-seconds"`
+	// ReadHeaderTimeoutSeconds bounds the reading of the request headers,
+	// the defense against slow clients holding connections
+	ReadHeaderTimeoutSeconds int `mapstructure:"read-header-timeout-seconds"`
+	// ReadTimeoutSeconds bounds the reading of the whole request
+	ReadTimeoutSeconds int `mapstructure:"read-timeout-seconds"`
+	// WriteTimeoutSeconds bounds the handling of the request and the writing
+	// of its response
+	WriteTimeoutSeconds int `mapstructure:"write-timeout-seconds"`
+	// IdleTimeoutSeconds is how long a keep-alive connection waits for the
+	// next request
+	IdleTimeoutSeconds int `mapstructure:"idle-timeout-seconds"`
+	// MaxHeaderBytes bounds the size of the request headers
+	MaxHeaderBytes int `mapstructure:"max-header-bytes"`
+	// TLS serves HTTPS instead of HTTP, when a certificate is configured
+	TLS tlscert.Config `mapstructure:"tls"`
+	// WatchConfig reloads the reloadable settings when the config file changes
+	WatchConfig bool `mapstructure:"watch-config"`
+	// TrustedProxies are the addresses or CIDR ranges of the reverse proxies
+	// whose X-Forwarded-For and X-Real-IP headers identify the clients
+	TrustedProxies []string `mapstructure:"trusted-proxies"`
+}
+
+// AdminConfig holds the configuration of the admin listener, serving the
+// health probes, the metrics, expvar and pprof apart from the API
+type AdminConfig struct {
+	// ListenAddress is the address the admin listener binds to, the loopback
+	// interface by default
+	ListenAddress string `mapstructure:"listen-address"`
+	Port          int    `mapstructure:"listen-port"`
+	// AuthToken, when set, is the bearer token required on every admin route
+	// but the health probes, read from ADMIN_AUTH_TOKEN or the file named by
+	// ADMIN_AUTH_TOKEN_FILE. It is required unless the listener is bound to
+	// the loopback interface.
+	AuthToken string `mapstructure:"auth-token" secret:"true"`
+}
+
+// LogConfig holds the configuration of the logs
+type LogConfig struct {
+	// Level is the minimum level logged: debug, info, warn or error (reloadable)
+	Level string `mapstructure:"level"`
+}
+
+// MediaConfig holds the configuration of the media
+type MediaConfig struct {
+	// AllowedMimeTypes are the MIME types accepted on creation, such as
+	// image/png or image/* (reloadable)
+	AllowedMimeTypes []string `mapstructure:"allowed-mime-types"`
+}
+
+// JobsConfig holds the configuration of the background jobs
+type JobsConfig struct {
+	// RelatedTagsRefreshSeconds is the refresh interval of the tag co-occurrences (0 disables it)
+	RelatedTagsRefreshSeconds int `mapstructure:"related-tags-refresh-seconds"`
+	// IdempotencyPurgeSeconds is the interval between purges of the expired idempotency keys (0 disables it)
+	IdempotencyPurgeSeconds int `mapstructure:"idempotency-purge-seconds"`
+	// RateLimitPurgeSeconds is the interval between purges of the idle rate limit buckets (0 disables it)
+	RateLimitPurgeSeconds int `mapstructure:"rate-limit-purge-seconds"`
+	// ReservedMediaAgeSeconds is the interval between measures of the age of the oldest reserved media (0 disables it)
+	ReservedMediaAgeSeconds int `mapstructure:"reserved-media-age-seconds"`
 }
 
-func LoadConfig() (*applicationConfig, error) {
-	baseConfig := config.NewConfig()
+// BatchConfig holds the configuration of the batch endpoints
+type BatchConfig struct {
+	// FinalizeConcurrency is the number of media verified in parallel when finalizing a batch
+	FinalizeConcurrency int `mapstructure:"finalize-concurrency"`
+}
+
+// IdempotencyConfig holds the configuration of the Idempotency-Key support
+type IdempotencyConfig struct {
+	// TTLSeconds is how long the response of a request is replayed for retries with the same key
+	TTLSeconds int `mapstructure:"ttl-seconds"`
+}
+
+// AuthConfig holds the configuration of the client authentication
+type AuthConfig struct {
+	// Enabled requires an API key or a bearer token with the relevant scope on every API route
+	Enabled bool `mapstructure:"enabled"`
+	// JWT configures the bearer tokens, accepted when a JWKS is configured
+	JWT jwtauth.Config `mapstructure:"jwt"`
+	// BootstrapKey is the admin API key to create on startup, if any, read
+	// from AUTH_BOOTSTRAP_KEY or the file named by AUTH_BOOTSTRAP_KEY_FILE
+	BootstrapKey string `mapstructure:"bootstrap-key" secret:"true"`
+}
+
+const (
+	metricsAdapterExpvar     = "expvar"
+	metricsAdapterPrometheus = "prometheus"
+)
+
+// minHeaderBytes is the lowest max-header-bytes, below which ordinary
+// requests with a bearer token are rejected
+const minHeaderBytes = 4096
+
+// Validate reports all the problems of the server configuration
+func (s *ServerConfig) Validate() error {
+	var errs []error
+	if s.Port < 1 || s.Port > 65535 {
+		errs = append(errs, config.Invalid("listen-port", "must be between 1 and 65535, got %d", s.Port))
+	}
+	if s.ShutdownTimeoutSeconds < 0 {
+		errs = append(errs, config.Invalid("shutdown-timeout-seconds", "must not be negative, got %d", s.ShutdownTimeoutSeconds))
+	}
+	if s.HealthCheckTimeoutSeconds < 0 {
+		errs = append(errs, config.Invalid("health-check-timeout-seconds", "must not be negative, got %d", s.HealthCheckTimeoutSeconds))
+	}
+	for _, timeout := range []struct {
+		key     string
+		seconds int
+	}{
+		{"read-header-timeout-seconds", s.ReadHeaderTimeoutSeconds},
+		{"read-timeout-seconds", s.ReadTimeoutSeconds},
+		{"write-timeout-seconds", s.WriteTimeoutSeconds},
+		{"idle-timeout-seconds", s.IdleTimeoutSeconds},
+	} {
+		if timeout.seconds < 1 {
+			errs = append(errs, config.Invalid(timeout.key, "must be at least 1, got %d", timeout.seconds))
+		}
+	}
+	if s.MaxHeaderBytes < minHeaderBytes {
+		errs = append(errs, config.Invalid("max-header-bytes", "must be at least %d, got %d", minHeaderBytes, s.MaxHeaderBytes))
+	}
+	for _, proxy := range s.TrustedProxies {
+		if _, err := parseTrustedProxy(proxy); err != nil {
+			errs = append(errs, config.Invalid("trusted-proxies", "must be IP addresses or CIDR ranges, got %q", proxy))
+		}
+	}
+	errs = append(errs, config.Collect("tls", s.TLS.Validate()))
+	return config.Collect("", errs...)
+}
+
+// trustedProxies returns the trusted proxies as ranges of addresses
+func (s *ServerConfig) trustedProxies() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(s.TrustedProxies))
+	for _, proxy := range s.TrustedProxies {
+		prefix, err := parseTrustedProxy(proxy)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, nil
+}
+
+// parseTrustedProxy parses a CIDR range, or a single address as the range
+// holding only it
+func parseTrustedProxy(proxy string) (netip.Prefix, error) {
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Validate reports all the problems of the admin configuration
+func (a *AdminConfig) Validate() error {
+	var errs []error
+	if a.Port < 1 || a.Port > 65535 {
+		errs = append(errs, config.Invalid("listen-port", "must be between 1 and 65535, got %d", a.Port))
+	}
+	if a.AuthToken == "" && !a.loopback() {
+		errs = append(errs, config.Invalid("auth-token", "must be set when listen-address (%q) is not a loopback address", a.ListenAddress))
+	}
+	return config.Collect("", errs...)
+}
+
+// loopback reports whether the admin listener is only reachable from the host
+func (a *AdminConfig) loopback() bool {
+	if a.ListenAddress == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(a.ListenAddress)
+	return ip != nil && ip.IsLoopback()
+}
+
+// level returns the slog level of the configuration
+func (l *LogConfig) level() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(l.Level))
+	return level, err
+}
+
+// Validate reports all the problems of the log configuration
+func (l *LogConfig) Validate() error {
+	if _, err := l.level(); err != nil {
+		return config.Collect("", config.Invalid("level", "must be debug, info, warn or error, got %q", l.Level))
+	}
+	return nil
+}
+
+// Validate reports all the problems of the media configuration
+func (m *MediaConfig) Validate() error {
+	var errs []error
+	for i, pattern := range m.AllowedMimeTypes {
+		mediaType, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(pattern)), "/")
+		if !ok || (mediaType != "image" && mediaType != "video") || subtype == "" {
+			errs = append(errs, config.Invalid(fmt.Sprintf("allowed-mime-types[%d]", i), "must be an image or video type such as image/png or video/*, got %q", pattern))
+		}
+	}
+	return config.Collect("", errs...)
+}
+
+// MetricsConfig holds the configuration of the metrics
+type MetricsConfig struct {
+	// Adapter records the metrics, expvar (served at /debug/vars) or
+	// prometheus (served at /metrics), both on the admin listener
+	Adapter    string            `mapstructure:"adapter"`
+	Prometheus prometheus.Config `mapstructure:"prometheus"`
+}
+
+// Validate reports all the problems of the metrics configuration
+func (m *MetricsConfig) Validate() error {
+	if m.Adapter != metricsAdapterExpvar && m.Adapter != metricsAdapterPrometheus {
+		return config.Collect("", config.Invalid("adapter", "must be %s or %s, got %q", metricsAdapterExpvar, metricsAdapterPrometheus, m.Adapter))
+	}
+	return nil
+}
+
+// Validate reports all the problems of the batch configuration
+func (b *BatchConfig) Validate() error {
+	if b.FinalizeConcurrency < 1 {
+		return config.Collect("", config.Invalid("finalize-concurrency", "must be at least 1, got %d", b.FinalizeConcurrency))
+	}
+	return nil
+}
+
+// Validate reports all the problems of the idempotency configuration
+func (i *IdempotencyConfig) Validate() error {
+	if i.TTLSeconds < 1 {
+		return config.Collect("", config.Invalid("ttl-seconds", "must be at least 1, got %d", i.TTLSeconds))
+	}
+	return nil
+}
+
+// LoadConfig loads the configuration of the environment; overrides take
+// precedence over every other source
+func LoadConfig(overrides map[string]any, opts ...config.Option) (*applicationConfig, error) {
+	baseConfig := config.NewConfig(opts...)
 	cfgLoader := baseConfig.ConfigLoader()
 
 	cfgLoader.SetDefault("server.shutdown-timeout-seconds", 20)
 	cfgLoader.SetDefault("server.listen-port", 8080)
+	cfgLoader.SetDefault("server.health-check-timeout-seconds", 2)
+	cfgLoader.SetDefault("server.watch-config", true)
+	cfgLoader.SetDefault("server.read-header-timeout-seconds", 5)
+	cfgLoader.SetDefault("server.read-timeout-seconds", 30)
+	cfgLoader.SetDefault("server.write-timeout-seconds", 60)
+	cfgLoader.SetDefault("server.idle-timeout-seconds", 120)
+	cfgLoader.SetDefault("server.max-header-bytes", 64<<10)
+	cfgLoader.SetDefault("server.trusted-proxies", []string{})
+	tlscert.SetDefaultConfig(cfgLoader, "server.tls")
+	cfgLoader.SetDefault("admin.listen-address", "127.0.0.1")
+	cfgLoader.SetDefault("admin.listen-port", 9090)
+	cfgLoader.BindEnv("admin.auth-token", "ADMIN_AUTH_TOKEN")
+	cfgLoader.SetDefault("log.level", "info")
+	cfgLoader.SetDefault("media.allowed-mime-types", []string{"image/*", "video/*"})
+	cfgLoader.SetDefault("jobs.related-tags-refresh-seconds", 300)
+	cfgLoader.SetDefault("jobs.idempotency-purge-seconds", 3600)
+	cfgLoader.SetDefault("jobs.rate-limit-purge-seconds", 600)
+	cfgLoader.SetDefault("jobs.reserved-media-age-seconds", 60)
+	cfgLoader.SetDefault("batch.finalize-concurrency", finalizemedia.DefaultBatchConcurrency)
+	cfgLoader.SetDefault("idempotency.ttl-seconds", 86400)
+	cfgLoader.SetDefault("auth.enabled", true)
+	cfgLoader.BindEnv("auth.bootstrap-key", "AUTH_BOOTSTRAP_KEY")
+	jwtauth.SetDefaultConfig(cfgLoader, "auth.jwt")
+	ratelimit.SetDefaultConfig(cfgLoader, "rate-limit")
+	cfgLoader.SetDefault("metrics.adapter", metricsAdapterExpvar)
+	prometheus.SetDefaultConfig(cfgLoader, "metrics.prometheus")
+	tracing.SetDefaultConfig(cfgLoader, "tracing")
 	postgres.SetDefaultConfig(cfgLoader, "database")
 	s3.SetDefaultConfig(cfgLoader, "s3")
 
 	if err := baseConfig.Load(); err != nil {
 		return nil, err
 	}
+	for key, value := range overrides {
+		baseConfig.Set(key, value)
+	}
 
 	ac := &applicationConfig{
 		Config: baseConfig,
@@ -43,3 +334,43 @@ func LoadConfig() (*applicationConfig, error) {
 
 	return ac, nil
 }
+
+// RedactedSettings returns the settings with the secrets redacted, fit for
+// dumps and logs
+func (ac *applicationConfig) RedactedSettings() map[string]any {
+	return config.Redact(ac.ConfigLoader().AllSettings(), ac)
+}
+
+// Validate reports all the problems of the configuration at once, each with
+// the key path of the offending setting
+func (ac *applicationConfig) Validate() error {
+	return config.Collect("",
+		config.Collect("server", ac.Server.Validate()),
+		config.Collect("admin", ac.Admin.Validate()),
+		ac.validateListeners(),
+		config.Collect("log", ac.Log.Validate()),
+		config.Collect("media", ac.Media.Validate()),
+		ac.validateDatabase(),
+		config.Collect("s3", ac.S3.Validate()),
+		config.Collect("batch", ac.Batch.Validate()),
+		config.Collect("idempotency", ac.Idempotency.Validate()),
+		config.Collect("auth.jwt", ac.Auth.JWT.Validate()),
+		config.Collect("rate-limit", ac.RateLimit.Validate()),
+		config.Collect("metrics", ac.Metrics.Validate()),
+		config.Collect("tracing", ac.Tracing.Validate()),
+	)
+}
+
+// validateListeners reports the public and admin listeners sharing a port
+func (ac *applicationConfig) validateListeners() error {
+	if ac.Admin.Port == ac.Server.Port {
+		return config.Collect("", config.Invalid("admin.listen-port", "must differ from server.listen-port (%d)", ac.Server.Port))
+	}
+	return nil
+}
+
+// validateDatabase reports the problems of the database configuration only,
+// the one needed by the migrate and seed commands
+func (ac *applicationConfig) validateDatabase() error {
+	return config.Collect("database", ac.Database.Validate())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/peano88/medias/config"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+func configCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the effective configuration",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "print",
+		Short: "Print the effective configuration, secrets redacted",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			encoder := yaml.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent(2)
+			if err := encoder.Encode(c.cfg.RedactedSettings()); err != nil {
+				return err
+			}
+			return encoder.Close()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration has valid values and no unknown keys",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var strict applicationConfig
+			if err := c.cfg.ConfigLoader().Unmarshal(&strict, config.Strict()); err != nil {
+				return fmt.Errorf("invalid configuration: %w", err)
+			}
+			var validationErrs config.ValidationErrors
+			if err := c.cfg.Validate(); errors.As(err, &validationErrs) {
+				for _, fieldErr := range validationErrs {
+					fmt.Fprintln(cmd.ErrOrStderr(), fieldErr)
+				}
+				return fmt.Errorf("configuration of env %s has %d problems", c.cfg.Env(), len(validationErrs))
+			}
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), "configuration of env %s is valid\n", c.cfg.Env())
+			return err
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// runPeriodically runs job every interval until ctx is done.
+// Failures are logged and do not stop the schedule.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, logger *slog.Logger, job func(context.Context) error) {
+	logger = logger.With(slog.String("job", name))
+	logger.Info("Starting periodic job", slog.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Periodic job stopped")
+			return
+		case <-ticker.C:
+			before := time.Now()
+			if err := runTraced(ctx, name, job); err != nil {
+				logger.Error("Periodic job failed", slog.String("error", err.Error()))
+				continue
+			}
+			logger.Debug("Periodic job completed", slog.Duration("duration", time.Since(before)))
+		}
+	}
+}
+
+// runTraced runs job once, within a span of its own
+func runTraced(ctx context.Context, name string, job func(context.Context) error) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "job "+name)
+	defer telemetry.EndSpan(span, &err)
+
+	return job(ctx)
+}
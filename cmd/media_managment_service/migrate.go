@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/peano88/medias/internal/adapters/storage/postgres"
+	"github.com/spf13/cobra"
+)
+
+func migrateCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply the database migrations embedded in the binary",
+		Long: `Apply the database migrations embedded in the binary. Each operation holds
+a postgres advisory lock: replicas migrating together wait for each other.`,
+	}
+
+	for _, sub := range []struct {
+		use   string
+		short string
+	}{
+		{"up", "Apply all the pending migrations"},
+		{"down", "Roll back the last applied migration"},
+		{"redo", "Roll back the last applied migration and apply it again"},
+		{"status", "List the migrations and when they were applied"},
+	} {
+		cmd.AddCommand(&cobra.Command{
+			Use:   sub.use,
+			Short: sub.short,
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := c.cfg.validateDatabase(); err != nil {
+					return err
+				}
+				return runMigrate(cmd.Context(), c.cfg, c.logger, cmd.OutOrStdout(), sub.use)
+			},
+		})
+	}
+
+	return cmd
+}
+
+// runMigrate runs the migrate subcommand named command
+func runMigrate(ctx context.Context, cfg *applicationConfig, logger *slog.Logger, out io.Writer, command string) error {
+	pool, err := postgres.NewPool(ctx, &cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to create database pool: %w", err)
+	}
+	defer pool.Close()
+
+	migrator, err := postgres.NewMigrator(pool)
+	if err != nil {
+		return fmt.Errorf("failed to create migrator: %w", err)
+	}
+
+	switch command {
+	case "up":
+		return migrateUp(ctx, migrator, logger)
+	case "down":
+		migration, found, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		logMigration(logger, "Migration rolled back", migration, found)
+	case "redo":
+		migration, found, err := migrator.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		logMigration(logger, "Migration redone", migration, found)
+	case "status":
+		migrations, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printMigrations(out, migrations)
+	}
+	return nil
+}
+
+// migrateUp applies the pending migrations, used as well on startup when
+// database.auto-migrate is set
+func migrateUp(ctx context.Context, migrator *postgres.Migrator, logger *slog.Logger) error {
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range applied {
+		logger.Info("Migration applied",
+			slog.Int64("version", migration.Version),
+			slog.String("name", migration.Name),
+		)
+	}
+	if len(applied) == 0 {
+		logger.Info("No pending migration")
+	}
+	return nil
+}
+
+func logMigration(logger *slog.Logger, msg string, migration postgres.Migration, found bool) {
+	if !found {
+		logger.Info("No applied migration")
+		return
+	}
+	logger.Info(msg,
+		slog.Int64("version", migration.Version),
+		slog.String("name", migration.Name),
+	)
+}
+
+func printMigrations(w io.Writer, migrations []postgres.Migration) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+	for _, migration := range migrations {
+		appliedAt := "pending"
+		if migration.Applied {
+			appliedAt = migration.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", migration.Version, migration.Name, appliedAt)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"log/slog"
+
+	"github.com/peano88/medias/config"
+	"github.com/peano88/medias/internal/adapters/filestorage/s3"
+	"github.com/peano88/medias/internal/adapters/ratelimit"
+	"github.com/peano88/medias/internal/app/createmedia"
+)
+
+// reloadTargets are the running components applying the reloadable settings
+type reloadTargets struct {
+	logLevel     *slog.LevelVar
+	mediaSaver   *s3.MediaSaver
+	mediaCreator *createmedia.UseCase
+	// limiter is nil when the rate limiting is disabled
+	limiter *ratelimit.Limiter
+}
+
+// watchConfig applies the changes of the reloadable settings of the config
+// file to targets; the other settings take effect on restart
+func watchConfig(cfg *applicationConfig, logger *slog.Logger, targets reloadTargets) {
+	cfg.Subscribe(func(loader config.ConfigLoader) {
+		var logCfg LogConfig
+		if err := loader.UnmarshalKey("log", &logCfg); err != nil {
+			logger.Error("Failed to apply the log level", slog.String("error", err.Error()))
+			return
+		}
+		level, _ := logCfg.level()
+		targets.logLevel.Set(level)
+	}, "log.level")
+
+	cfg.Subscribe(func(loader config.ConfigLoader) {
+		var s3Cfg s3.Config
+		if err := loader.UnmarshalKey("s3", &s3Cfg); err != nil {
+			logger.Error("Failed to apply the URL expiry", slog.String("error", err.Error()))
+			return
+		}
+		targets.mediaSaver.UpdateExpiry(s3Cfg)
+	}, "s3.upload-expiry", "s3.download-expiry")
+
+	cfg.Subscribe(func(loader config.ConfigLoader) {
+		var mediaCfg MediaConfig
+		if err := loader.UnmarshalKey("media", &mediaCfg); err != nil {
+			logger.Error("Failed to apply the allowed MIME types", slog.String("error", err.Error()))
+			return
+		}
+		targets.mediaCreator.SetAllowedMimeTypes(mediaCfg.AllowedMimeTypes)
+	}, "media.allowed-mime-types")
+
+	if targets.limiter != nil {
+		cfg.Subscribe(func(loader config.ConfigLoader) {
+			var rateLimitCfg ratelimit.Config
+			err := loader.UnmarshalKey("rate-limit", &rateLimitCfg)
+			if err == nil {
+				err = targets.limiter.Update(rateLimitCfg)
+			}
+			if err != nil {
+				logger.Error("Failed to apply the rate limits", slog.String("error", err.Error()))
+			}
+		}, "rate-limit.default", "rate-limit.routes", "rate-limit.ip")
+	}
+
+	cfg.Watch(logger, validateCandidate, &applicationConfig{})
+}
+
+// validateCandidate accepts a reloaded configuration passing the validation
+// of the startup
+func validateCandidate(candidate config.ConfigLoader) error {
+	var next applicationConfig
+	if err := candidate.Unmarshal(&next); err != nil {
+		return err
+	}
+	return next.Validate()
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/peano88/medias/internal/adapters/storage/postgres"
+	"github.com/spf13/cobra"
+)
+
+const prodEnv = "prod"
+
+func seedCmd(c *cli) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Load the development fixtures into the database",
+		Long: `Load the development fixtures into the database. The content of the tables
+having fixtures is replaced, for every tenant. Refused in the prod environment
+unless --force is given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if c.cfg.Env() == prodEnv && !force {
+				return fmt.Errorf("refusing to seed the %s environment without --force", prodEnv)
+			}
+			if err := c.cfg.validateDatabase(); err != nil {
+				return err
+			}
+			if err := postgres.Seed(&c.cfg.Database); err != nil {
+				return err
+			}
+			c.logger.Info("Database seeded", slog.String("database", c.cfg.Database.Database))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Seed even the prod environment")
+
+	return cmd
+}
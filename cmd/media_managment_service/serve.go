@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	nethttp "net/http"
+	"time"
+
+	"github.com/peano88/medias/internal/adapters/filestorage/s3"
+	"github.com/peano88/medias/internal/adapters/http"
+	"github.com/peano88/medias/internal/adapters/jwtauth"
+	"github.com/peano88/medias/internal/adapters/metrics/expvar"
+	"github.com/peano88/medias/internal/adapters/metrics/prometheus"
+	"github.com/peano88/medias/internal/adapters/ratelimit"
+	"github.com/peano88/medias/internal/adapters/storage/postgres"
+	"github.com/peano88/medias/internal/adapters/tracing"
+	"github.com/peano88/medias/internal/app/assignrole"
+	"github.com/peano88/medias/internal/app/authenticate"
+	"github.com/peano88/medias/internal/app/authenticatetoken"
+	"github.com/peano88/medias/internal/app/authorize"
+	"github.com/peano88/medias/internal/app/createapikey"
+	"github.com/peano88/medias/internal/app/createmedia"
+	"github.com/peano88/medias/internal/app/createtag"
+	"github.com/peano88/medias/internal/app/finalizemedia"
+	"github.com/peano88/medias/internal/app/getmedia"
+	"github.com/peano88/medias/internal/app/getrelatedtags"
+	"github.com/peano88/medias/internal/app/gettag"
+	"github.com/peano88/medias/internal/app/gettags"
+	"github.com/peano88/medias/internal/app/listapikeys"
+	"github.com/peano88/medias/internal/app/listmedia"
+	"github.com/peano88/medias/internal/app/listroles"
+	"github.com/peano88/medias/internal/app/revokeapikey"
+	"github.com/peano88/medias/internal/app/searchmedia"
+	"github.com/peano88/medias/internal/app/unassignrole"
+	"github.com/peano88/medias/internal/app/updatemedia"
+	"github.com/peano88/medias/internal/app/updatetag"
+	"github.com/spf13/cobra"
+)
+
+// mediaMetrics records the HTTP requests and the media lifecycle
+type mediaMetrics interface {
+	http.MetricsForwarder
+	createmedia.Metrics
+	finalizemedia.Metrics
+	OldestReservedMediaAge(age time.Duration)
+}
+
+func serveCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the media management API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.cfg.Validate(); err != nil {
+				return err
+			}
+			c.logger.Info("Configuration loaded", slog.Any("configuration", c.cfg.RedactedSettings()))
+			return serve(cmd.Context(), c.cfg, c.logger, &c.logLevel)
+		},
+	}
+
+	cmd.Flags().Int("listen-port", 0, "Port of the HTTP server (server.listen-port)")
+	cmd.Flags().Bool("auto-migrate", false, "Apply the pending migrations on startup (database.auto-migrate)")
+	c.bindFlag(cmd.Flags(), "listen-port", "server.listen-port")
+	c.bindFlag(cmd.Flags(), "auto-migrate", "database.auto-migrate")
+
+	return cmd
+}
+
+// serve runs the API until ctx is done, logLevel being the level of logger
+func serve(ctx context.Context, cfg *applicationConfig, logger *slog.Logger, logLevel *slog.LevelVar) error {
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, appName, appVersion)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		// the signal context is done by now
+		flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFlush()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Error("Failed to flush traces", slog.String("error", err.Error()))
+		}
+	}()
+
+	pool, err := postgres.NewPool(ctx, &cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to create database pool: %w", err)
+	}
+	defer pool.Close()
+	logger.Info("Database connection pool established")
+
+	if cfg.Database.AutoMigrate {
+		migrator, err := postgres.NewMigrator(pool)
+		if err == nil {
+			err = migrateUp(ctx, migrator, logger)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	if err := postgres.CheckTenantIsolation(ctx, pool); err != nil {
+		return fmt.Errorf("refusing to serve: %w", err)
+	}
+
+	// Create repositories
+	tagRepo := postgres.NewTagRepository(pool)
+	mediaRepo := postgres.NewMediaRepository(pool)
+	idempotencyRepo := postgres.NewIdempotencyRepository(pool)
+	apiKeyRepo := postgres.NewAPIKeyRepository(pool)
+	roleRepo := postgres.NewRoleRepository(pool)
+	auditRepo := postgres.NewAuditRepository(pool)
+
+	// Create file storage adapter
+	mediaSaver, err := s3.NewMediaSaver(ctx, cfg.S3, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create S3 media saver: %w", err)
+	}
+	logger.Info("S3 media saver initialized")
+
+	migrationsChecker, err := postgres.NewMigrationsHealthChecker(pool)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations health checker: %w", err)
+	}
+
+	// Create metrics adapter
+	var metrics mediaMetrics
+	var metricsHandler nethttp.Handler
+	switch cfg.Metrics.Adapter {
+	case metricsAdapterExpvar:
+		metrics = expvar.NewExpvarMetrics()
+	case metricsAdapterPrometheus:
+		promMetrics, err := prometheus.NewPrometheusMetrics(cfg.Metrics.Prometheus)
+		if err == nil {
+			err = promMetrics.Register(prometheus.NewPoolCollector(pool))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create Prometheus metrics: %w", err)
+		}
+		metrics = promMetrics
+		metricsHandler = promMetrics.Handler()
+	default:
+		return fmt.Errorf("unknown metrics adapter %q", cfg.Metrics.Adapter)
+	}
+
+	// Create use cases
+	createTagUseCase := createtag.New(tagRepo)
+	getTagsUseCase := gettags.New(tagRepo)
+	getTagUseCase := gettag.New(tagRepo)
+	updateTagUseCase := updatetag.New(tagRepo)
+	getRelatedTagsUseCase := getrelatedtags.New(tagRepo)
+	createMediaUseCase := createmedia.New(mediaRepo, mediaSaver,
+		createmedia.WithMetrics(metrics),
+		createmedia.WithAllowedMimeTypes(cfg.Media.AllowedMimeTypes),
+	)
+	finalizeMediaUseCase := finalizemedia.New(mediaRepo, mediaSaver,
+		finalizemedia.WithBatchConcurrency(cfg.Batch.FinalizeConcurrency),
+		finalizemedia.WithMetrics(metrics),
+	)
+	getMediaUseCase := getmedia.New(mediaRepo, mediaSaver)
+	searchMediaUseCase := searchmedia.New(mediaRepo, mediaSaver)
+	listMediaUseCase := listmedia.New(mediaRepo, mediaSaver)
+	updateMediaUseCase := updatemedia.New(mediaRepo, mediaSaver)
+	createAPIKeyUseCase := createapikey.New(apiKeyRepo)
+	listAPIKeysUseCase := listapikeys.New(apiKeyRepo)
+	revokeAPIKeyUseCase := revokeapikey.New(apiKeyRepo)
+	assignRoleUseCase := assignrole.New(roleRepo)
+	listRolesUseCase := listroles.New(roleRepo)
+	unassignRoleUseCase := unassignrole.New(roleRepo)
+
+	deps := http.Dependencies{
+		TagCreator:          createTagUseCase,
+		TagRetriever:        getTagsUseCase,
+		SingleTagRetriever:  getTagUseCase,
+		TagUpdater:          updateTagUseCase,
+		RelatedTagRetriever: getRelatedTagsUseCase,
+		MediaCreator:        createMediaUseCase,
+		MediaBatchCreator:   createMediaUseCase,
+		MediaFinalizer:      finalizeMediaUseCase,
+		MediaBatchFinalizer: finalizeMediaUseCase,
+		MediaRetriever:      getMediaUseCase,
+		MediaUpdater:        updateMediaUseCase,
+		MediaSearcher:       searchMediaUseCase,
+		MediaLister:         listMediaUseCase,
+		APIKeyCreator:       createAPIKeyUseCase,
+		APIKeyLister:        listAPIKeysUseCase,
+		APIKeyRevoker:       revokeAPIKeyUseCase,
+		RoleAssigner:        assignRoleUseCase,
+		RoleLister:          listRolesUseCase,
+		RoleUnassigner:      unassignRoleUseCase,
+		IdempotencyStore:    idempotencyRepo,
+		IdempotencyTTL:      time.Duration(cfg.Idempotency.TTLSeconds) * time.Second,
+		MetricForwarder:     metrics,
+		Logger:              logger,
+	}
+
+	adminDeps := http.AdminDependencies{
+		MetricsHandler: metricsHandler,
+		HealthCheckers: []http.HealthChecker{
+			postgres.NewHealthChecker(pool),
+			migrationsChecker,
+			mediaSaver,
+		},
+		HealthCheckTimeout: time.Duration(cfg.Server.HealthCheckTimeoutSeconds) * time.Second,
+		AuthToken:          cfg.Admin.AuthToken,
+	}
+	if cfg.Admin.AuthToken == "" {
+		logger.Warn("Admin listener authentication disabled, metrics and pprof are open to the local clients of its port")
+	}
+
+	if cfg.Auth.Enabled {
+		deps.Authenticator = authenticate.New(apiKeyRepo)
+		deps.Authorizer = authorize.New(roleRepo, auditRepo)
+		deps.AuditRecorder = auditRepo
+
+		if cfg.Auth.BootstrapKey != "" {
+			if err := createAPIKeyUseCase.Bootstrap(ctx, cfg.Auth.BootstrapKey); err != nil {
+				return fmt.Errorf("failed to create bootstrap API key: %w", err)
+			}
+			logger.Info("Bootstrap API key available")
+		}
+
+		if cfg.Auth.JWT.Enabled() {
+			verifier, err := jwtauth.NewVerifier(ctx, cfg.Auth.JWT, logger)
+			if err != nil {
+				return fmt.Errorf("failed to create JWT verifier: %w", err)
+			}
+			deps.TokenAuthenticator = authenticatetoken.New(verifier)
+		}
+	} else {
+		logger.Warn("Authentication disabled, the API is open to any client")
+	}
+
+	var limiter *ratelimit.Limiter
+	rateLimitPurge := time.Duration(cfg.Jobs.RateLimitPurgeSeconds) * time.Second
+	if cfg.RateLimit.Enabled {
+		var store ratelimit.Store
+		switch cfg.RateLimit.Store {
+		case ratelimit.StoreMemory:
+			store = ratelimit.NewMemoryStore(ctx, rateLimitPurge)
+		case ratelimit.StorePostgres:
+			rateLimitRepo := postgres.NewRateLimitRepository(pool)
+			store = rateLimitRepo
+			if rateLimitPurge > 0 {
+				go runPeriodically(ctx, "purge-rate-limit-buckets", rateLimitPurge, logger,
+					func(ctx context.Context) error {
+						_, err := rateLimitRepo.DeleteIdle(ctx, rateLimitPurge)
+						return err
+					})
+			}
+		default:
+			return fmt.Errorf("unknown rate limit store %q", cfg.RateLimit.Store)
+		}
+
+		limiter, err = ratelimit.New(cfg.RateLimit, store)
+		if err != nil {
+			return fmt.Errorf("failed to create rate limiter: %w", err)
+		}
+		deps.RateLimiter = limiter
+
+		deps.TrustedProxies, err = cfg.Server.trustedProxies()
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxies: %w", err)
+		}
+	}
+
+	// Start background jobs
+	if cfg.Jobs.RelatedTagsRefreshSeconds > 0 {
+		go runPeriodically(ctx, "refresh-related-tags",
+			time.Duration(cfg.Jobs.RelatedTagsRefreshSeconds)*time.Second, logger,
+			func(ctx context.Context) error {
+				_, err := tagRepo.RefreshTagCooccurrences(ctx)
+				return err
+			})
+	}
+
+	if cfg.Jobs.IdempotencyPurgeSeconds > 0 {
+		go runPeriodically(ctx, "purge-idempotency-keys",
+			time.Duration(cfg.Jobs.IdempotencyPurgeSeconds)*time.Second, logger,
+			func(ctx context.Context) error {
+				_, err := idempotencyRepo.DeleteExpired(ctx)
+				return err
+			})
+	}
+
+	if cfg.Jobs.ReservedMediaAgeSeconds > 0 {
+		go runPeriodically(ctx, "measure-reserved-media-age",
+			time.Duration(cfg.Jobs.ReservedMediaAgeSeconds)*time.Second, logger,
+			func(ctx context.Context) error {
+				oldest, found, err := mediaRepo.OldestReservedCreatedAt(ctx)
+				if err != nil {
+					return err
+				}
+				var age time.Duration
+				if found {
+					age = time.Since(oldest)
+				}
+				metrics.OldestReservedMediaAge(age)
+				return nil
+			})
+	}
+
+	if cfg.Server.WatchConfig {
+		watchConfig(cfg, logger, reloadTargets{
+			logLevel:     logLevel,
+			mediaSaver:   mediaSaver,
+			mediaCreator: createMediaUseCase,
+			limiter:      limiter,
+		})
+	}
+
+	// Create servers
+	server, err := newServer(ctx, &cfg.Server, deps)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+	adminServer := newAdminServer(ctx, &cfg.Server, &cfg.Admin, adminDeps)
+
+	// Run servers with graceful shutdown
+	return runServer(ctx, &cfg.Server, logger, server, adminServer)
+}
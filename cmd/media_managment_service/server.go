@@ -2,17 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	nethttp "net/http"
+	"strconv"
 	"time"
 
 	"github.com/peano88/medias/internal/adapters/http"
+	"github.com/peano88/medias/internal/adapters/tlscert"
+	"golang.org/x/sync/errgroup"
 )
 
-// newServer creates and configures a new HTTP server
-func newServer(ctx context.Context, cfg *ServerConfig, deps http.Dependencies) *nethttp.Server {
+// newServer creates and configures a new HTTP server, serving TLS when a
+// certificate is configured
+func newServer(ctx context.Context, cfg *ServerConfig, deps http.Dependencies) (*nethttp.Server, error) {
 	r := http.NewRouter(deps)
 
 	addr := fmt.Sprintf(":%d", cfg.Port)
@@ -20,51 +25,99 @@ func newServer(ctx context.Context, cfg *ServerConfig, deps http.Dependencies) *
 	server := &nethttp.Server{
 		Addr:              addr,
 		Handler:           r,
-		ReadTimeout:       0, // TODO
-		ReadHeaderTimeout: 0, // TODO
-		WriteTimeout:      0, // TODO
-		IdleTimeout:       0, // TODO
-		MaxHeaderBytes:    0, // TODO
+		ReadTimeout:       time.Duration(cfg.ReadTimeoutSeconds) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(cfg.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(cfg.IdleTimeoutSeconds) * time.Second,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
 		BaseContext: func(net.Listener) context.Context {
 			return ctx
 		},
 	}
 
-	return server
+	if cfg.TLS.Enabled() {
+		tlsConfig, err := tlscert.NewServerConfig(ctx, cfg.TLS, deps.Logger)
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = tlsConfig
+	}
+
+	return server, nil
 }
 
-// runServer starts the HTTP server and handles graceful shutdown
-func runServer(ctx context.Context, server *nethttp.Server, cfg *ServerConfig, logger *slog.Logger) error {
-	// Start server in goroutine
-	errChan := make(chan error, 1)
-	go func() {
-		logger.Info("Starting server", slog.String("address", server.Addr))
+// newAdminServer creates the server of the admin listener, with the limits
+// of the public server but the write timeout: profiles and traces stream for
+// the duration requested
+func newAdminServer(ctx context.Context, cfg *ServerConfig, adminCfg *AdminConfig, deps http.AdminDependencies) *nethttp.Server {
+	return &nethttp.Server{
+		Addr:              net.JoinHostPort(adminCfg.ListenAddress, strconv.Itoa(adminCfg.Port)),
+		Handler:           http.NewAdminRouter(deps),
+		ReadTimeout:       time.Duration(cfg.ReadTimeoutSeconds) * time.Second,
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(cfg.IdleTimeoutSeconds) * time.Second,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+}
 
-		if err := server.ListenAndServe(); err != nil && err != nethttp.ErrServerClosed {
-			logger.Error("Server failed", slog.String("error", err.Error()))
-			errChan <- err
-		}
-	}()
+// runServer starts the HTTP servers and shuts them all down gracefully once
+// ctx is done or one of them fails
+func runServer(ctx context.Context, cfg *ServerConfig, logger *slog.Logger, servers ...*nethttp.Server) error {
+	// Start servers in goroutines
+	errChan := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			logger.Info("Starting server",
+				slog.String("address", server.Addr),
+				slog.Bool("tls", server.TLSConfig != nil),
+				slog.Bool("client_certificates", server.TLSConfig != nil && server.TLSConfig.ClientCAs != nil),
+			)
+
+			var err error
+			if server.TLSConfig != nil {
+				// the certificate comes from TLSConfig, reloaded on change
+				err = server.ListenAndServeTLS("", "")
+			} else {
+				err = server.ListenAndServe()
+			}
+			if err != nil && err != nethttp.ErrServerClosed {
+				logger.Error("Server failed", slog.String("address", server.Addr), slog.String("error", err.Error()))
+				errChan <- err
+			}
+		}()
+	}
 
-	// Wait for context cancellation (signal)
+	// Wait for context cancellation (signal) or a failure
+	var serveErr error
 	select {
-	case err := <-errChan:
-		return err
+	case serveErr = <-errChan:
 	case <-ctx.Done():
 	}
 
-	logger.Info("Shutting down server")
+	logger.Info("Shutting down servers")
 
 	// Create shutdown context with timeout
 	shutDownCtx, cancelShutDownCtx := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeoutSeconds)*time.Second)
 	defer cancelShutDownCtx()
 
-	// Attempt graceful shutdown
-	if err := server.Shutdown(shutDownCtx); err != nil {
+	// Attempt graceful shutdown, the servers sharing the timeout
+	g := new(errgroup.Group)
+	for _, server := range servers {
+		g.Go(func() error {
+			return server.Shutdown(shutDownCtx)
+		})
+	}
+	if err := g.Wait(); err != nil {
 		logger.Error("Server shutdown failed", slog.String("error", err.Error()))
-		return err
+		return errors.Join(serveErr, err)
+	}
+	if serveErr != nil {
+		return serveErr
 	}
 
-	logger.Info("Server gracefully stopped")
+	logger.Info("Servers gracefully stopped")
 	return nil
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -10,6 +11,7 @@ import (
 func mediaCmd() *cobra.Command {
 	var (
 		apiURL      string
+		apiKey      string
 		filePath    string
 		description string
 		mimeType    string
@@ -48,7 +50,7 @@ func mediaCmd() *cobra.Command {
 			}
 
 			fmt.Printf("Creating media record for %s...\n", filepath.Base(filePath))
-			createdMedia, err := createMediaRecord(apiURL, reqBody)
+			createdMedia, err := createMediaRecord(apiURL, apiKey, reqBody)
 			if err != nil {
 				return fmt.Errorf("error creating media record: %w", err)
 			}
@@ -61,6 +63,7 @@ func mediaCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&apiURL, "api", "a", "http://localhost:8080/api/v1/media", "API URL")
+	cmd.Flags().StringVarP(&apiKey, "api-key", "k", os.Getenv("MEDIAS_API_KEY"), "API key with the media:write scope (defaults to $MEDIAS_API_KEY)")
 	cmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to file (required)")
 	cmd.Flags().StringVarP(&description, "desc", "d", "", "Media description")
 	cmd.Flags().StringVarP(&mimeType, "mime", "m", "", "MIME type (auto-detected if not provided)")
@@ -67,7 +67,7 @@ func parseTags(tags string) []string {
 	return tagList
 }
 
-func createMediaRecord(apiURL string, req createMediaRequest) (createMediaResponse, error) {
+func createMediaRecord(apiURL, apiKey string, req createMediaRequest) (createMediaResponse, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return createMediaResponse{}, fmt.Errorf("failed to marshal request: %w", err)
@@ -78,6 +78,9 @@ func createMediaRecord(apiURL string, req createMediaRequest) (createMediaRespon
 		return createMediaResponse{}, fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		httpReq.Header.Set("X-API-Key", apiKey)
+	}
 
 	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
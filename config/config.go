@@ -4,72 +4,238 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
 // Config is a base configuration allowing dynamic loading and alteration
 // based on environment variable and config files
 type Config struct {
-	viper *viper.Viper
-	env   string
+	// mu guards viper, replaced as a whole by a reload
+	mu         sync.RWMutex
+	viper      *viper.Viper
+	env        string
+	configPath string
+	// envBindings are the environment variables bound to the keys, instead
+	// of their automatic names
+	envBindings map[string][]string
+	// defaults and overrides are kept to rebuild the configuration on reload
+	defaults  map[string]any
+	overrides map[string]any
+
+	reloadMu    sync.Mutex
+	subscribers []subscription
+}
+
+// envKeyReplacer derives the environment variable of a key, e.g.
+// SERVER_LISTEN_PORT for server.listen-port
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// Option customizes a Config
+type Option func(*Config)
+
+// WithEnv sets the environment, overriding the ENV environment variable
+func WithEnv(env string) Option {
+	return func(c *Config) {
+		if env != "" {
+			c.env = env
+		}
+	}
+}
+
+// WithConfigPath sets the directory of the config files, overriding the
+// CONFIG_PATH environment variable
+func WithConfigPath(path string) Option {
+	return func(c *Config) {
+		if path != "" {
+			c.configPath = path
+		}
+	}
 }
 
 type DecoderConfigOption = viper.DecoderConfigOption
 
+// Strict makes Unmarshal fail on the keys matching no field, such as a
+// misspelled key of a config file
+func Strict() DecoderConfigOption {
+	return func(dc *mapstructure.DecoderConfig) {
+		dc.ErrorUnused = true
+	}
+}
+
 // ConfigLoader defines methods for dynamic alteration of configuration
-// (currently only used to set default values)
+// (setting default values and binding environment variables)
 // as well as unmarshalling and mapping configuration to structs
 type ConfigLoader interface {
 	SetDefault(string, any)
+	BindEnv(key string, envVars ...string)
 	Unmarshal(any, ...DecoderConfigOption) error
+	UnmarshalKey(key string, target any, opts ...DecoderConfigOption) error
 	AllSettings() map[string]any
 }
 
-// ConfigLoader returns the underlying ConfigLoader
+// ConfigLoader returns the ConfigLoader of the configuration
 func (c *Config) ConfigLoader() ConfigLoader {
-	return c.viper
+	return c
+}
+
+// SetDefault sets the value of key when no other source sets it
+func (c *Config) SetDefault(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaults[key] = value
+	c.viper.SetDefault(key, value)
+}
+
+// BindEnv reads key from the first of envVars set, instead of the variable
+// named after the key. Each variable has a _FILE variant as well.
+func (c *Config) BindEnv(key string, envVars ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.envBindings[key] = envVars
+	_ = c.viper.BindEnv(append([]string{key}, envVars...)...)
+}
+
+// Unmarshal decodes the configuration into target
+func (c *Config) Unmarshal(target any, opts ...DecoderConfigOption) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.viper.Unmarshal(target, opts...)
+}
+
+// UnmarshalKey decodes the configuration below key into target
+func (c *Config) UnmarshalKey(key string, target any, opts ...DecoderConfigOption) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.viper.UnmarshalKey(key, target, opts...)
 }
 
-func NewConfig() *Config {
-	return &Config{
-		viper: viper.New(),
-		env:   getEnv(),
+// AllSettings returns the configuration as nested maps. It holds the secrets
+// in clear, dumps and logs must go through Redact.
+func (c *Config) AllSettings() map[string]any {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.viper.AllSettings()
+}
+
+func NewConfig(opts ...Option) *Config {
+	c := &Config{
+		viper:       viper.New(),
+		env:         getEnv(),
+		configPath:  os.Getenv("CONFIG_PATH"),
+		envBindings: map[string][]string{},
+		defaults:    map[string]any{},
+		overrides:   map[string]any{},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
 
+// Set overrides the value of key, taking precedence over the config file,
+// the environment variables and the defaults
+func (c *Config) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides[key] = value
+	c.viper.Set(key, value)
 }
 
 // Load reads configuration from file based on ENV environment variable
 // Defaults to "dev" environment if ENV is not set
 // Config files should be named: config.{env}.yaml
 func (c *Config) Load() error {
+	v, err := c.build()
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.viper = v
+	return nil
+}
+
+// build reads a fresh configuration from the defaults, the config file, the
+// environment variables and the overrides
+func (c *Config) build() (*viper.Viper, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	v := viper.New()
+	for key, value := range c.defaults {
+		v.SetDefault(key, value)
+	}
+	for key, envVars := range c.envBindings {
+		_ = v.BindEnv(append([]string{key}, envVars...)...)
+	}
 
 	// Set config file name and path
-	c.viper.SetConfigName(fmt.Sprintf("config.%s", c.env))
-	c.viper.SetConfigType("yaml")
+	v.SetConfigName(fmt.Sprintf("config.%s", c.env))
+	v.SetConfigType("yaml")
 
-	// Check if CONFIG_PATH environment variable is set
-	if configPath := os.Getenv("CONFIG_PATH"); configPath != "" {
-		c.viper.AddConfigPath(configPath)
+	// Check if a config path is set, through CONFIG_PATH by default
+	if c.configPath != "" {
+		v.AddConfigPath(c.configPath)
 	} else {
 		// Default search paths
-		c.viper.AddConfigPath(".")
-		c.viper.AddConfigPath("./config")
+		v.AddConfigPath(".")
+		v.AddConfigPath("./config")
 	}
 
 	// Enable environment variable overrides
 	// e.g., SERVER_LISTEN_PORT will override server.listen-port
-	c.viper.SetEnvPrefix("") // No prefix
-	c.viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
-	c.viper.AutomaticEnv() // TODO
+	v.SetEnvPrefix("") // No prefix
+	v.SetEnvKeyReplacer(envKeyReplacer)
+	v.AutomaticEnv() // TODO
 
 	// Read config file
-	if err := c.viper.ReadInConfig(); err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := c.readFileVariables(v); err != nil {
+		return nil, err
 	}
 
+	for key, value := range c.overrides {
+		v.Set(key, value)
+	}
+	return v, nil
+}
+
+// readFileVariables sets the keys whose environment variable is unset, but
+// has a _FILE variant naming a file, to the content of the file: secrets such
+// as DB_PASSWORD_FILE are read from mounted files
+func (c *Config) readFileVariables(v *viper.Viper) error {
+	for _, key := range v.AllKeys() {
+		for _, name := range c.envNames(key) {
+			if _, ok := os.LookupEnv(name); ok {
+				break
+			}
+			path, ok := os.LookupEnv(name + "_FILE")
+			if !ok {
+				continue
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read %s_FILE: %w", name, err)
+			}
+			v.Set(key, strings.TrimRight(string(content), "\r\n"))
+			break
+		}
+	}
 	return nil
+}
 
+// envNames returns the environment variables key is read from
+func (c *Config) envNames(key string) []string {
+	if names, ok := c.envBindings[key]; ok {
+		return names
+	}
+	return []string{strings.ToUpper(envKeyReplacer.Replace(key))}
 }
 
 // getEnv returns the current environment from ENV variable
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Load_FileVariables(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.test.yaml"), []byte("database:\n  host: localhost\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte("hunter2\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "host"), []byte("db.internal"), 0o600))
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected map[string]any
+	}{
+		{
+			name: "bound variable read from its file",
+			env: map[string]string{
+				"DB_PASSWORD_FILE": filepath.Join(dir, "db_password"),
+			},
+			expected: map[string]any{"host": "localhost", "password": "hunter2"},
+		},
+		{
+			name: "variable wins over its file",
+			env: map[string]string{
+				"DB_PASSWORD":      "from-env",
+				"DB_PASSWORD_FILE": filepath.Join(dir, "db_password"),
+			},
+			expected: map[string]any{"host": "localhost", "password": "from-env"},
+		},
+		{
+			name: "automatic variable read from its file",
+			env: map[string]string{
+				"DATABASE_HOST_FILE": filepath.Join(dir, "host"),
+			},
+			expected: map[string]any{"host": "db.internal", "password": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			c := NewConfig(WithEnv("test"), WithConfigPath(dir))
+			c.SetDefault("database.password", "")
+			c.BindEnv("database.password", "DB_PASSWORD")
+			require.NoError(t, c.Load())
+
+			assert.Equal(t, tt.expected, c.AllSettings()["database"])
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD_FILE", filepath.Join(dir, "missing"))
+
+		c := NewConfig(WithEnv("test"), WithConfigPath(dir))
+		c.BindEnv("database.password", "DB_PASSWORD")
+		assert.ErrorContains(t, c.Load(), "DB_PASSWORD_FILE")
+	})
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Subscriber is notified of the reloads changing the keys it registered for,
+// the new values being available through loader
+type Subscriber func(loader ConfigLoader)
+
+type subscription struct {
+	keys []string
+	fn   Subscriber
+}
+
+// Validator checks a candidate configuration before it replaces the current one
+type Validator func(candidate ConfigLoader) error
+
+// Subscribe registers fn for the reloads changing any of keys, or a key below
+// them, e.g. rate-limit for rate-limit.default.burst. The keys nobody
+// subscribes to are structural: their changes only apply on restart.
+func (c *Config) Subscribe(fn Subscriber, keys ...string) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+	c.subscribers = append(c.subscribers, subscription{keys: keys, fn: fn})
+}
+
+// Watch reloads the configuration each time its file changes. A reload is
+// atomic: the candidate configuration, rebuilt from every source, replaces
+// the current one only when validate accepts it, then the subscribers of the
+// changed keys are notified. The changes are logged, the secrets of schema,
+// the struct the configuration is decoded into, redacted.
+func (c *Config) Watch(logger *slog.Logger, validate Validator, schema any) {
+	c.mu.RLock()
+	file := c.viper.ConfigFileUsed()
+	c.mu.RUnlock()
+
+	// a viper of its own watches the file, the reload building a new one
+	watcher := viper.New()
+	watcher.SetConfigFile(file)
+	watcher.OnConfigChange(func(event fsnotify.Event) {
+		c.reload(logger, validate, schema)
+	})
+	watcher.WatchConfig()
+	logger.Info("Watching the configuration file", slog.String("file", file))
+}
+
+// reload replaces the configuration with the one read from its sources, if
+// valid, and notifies the subscribers of the changed keys
+func (c *Config) reload(logger *slog.Logger, validate Validator, schema any) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	v, err := c.build()
+	if err == nil {
+		err = validate(&Config{viper: v, env: c.env})
+	}
+	if err != nil {
+		logger.Error("Configuration reload rejected, keeping the current values", slog.String("error", err.Error()))
+		return
+	}
+
+	c.mu.Lock()
+	previous := c.viper
+	c.viper = v
+	c.mu.Unlock()
+
+	oldSettings, newSettings := previous.AllSettings(), v.AllSettings()
+	changed := changedKeys(flatten(oldSettings), flatten(newSettings))
+	if len(changed) == 0 {
+		logger.Debug("Configuration file changed, without new values")
+		return
+	}
+
+	oldRedacted, newRedacted := flatten(Redact(oldSettings, schema)), flatten(Redact(newSettings, schema))
+	changes := make(map[string]any, len(changed))
+	for _, key := range changed {
+		changes[key] = map[string]any{"old": oldRedacted[key], "new": newRedacted[key]}
+	}
+	logger.Info("Configuration reloaded", slog.Any("changes", changes))
+
+	notified := map[string]bool{}
+	for _, sub := range c.subscribers {
+		matched := false
+		for _, key := range changed {
+			if matchesAny(key, sub.keys) {
+				notified[key] = true
+				matched = true
+			}
+		}
+		if matched {
+			sub.fn(c)
+		}
+	}
+
+	var structural []string
+	for _, key := range changed {
+		if !notified[key] {
+			structural = append(structural, key)
+		}
+	}
+	if len(structural) > 0 {
+		logger.Warn("Configuration changes taking effect on restart only", slog.Any("keys", structural))
+	}
+}
+
+// flatten maps the dotted path of each leaf of settings to its value; the
+// lists are leaves
+func flatten(settings map[string]any) map[string]any {
+	flat := map[string]any{}
+	var walk func(prefix string, settings map[string]any)
+	walk = func(prefix string, settings map[string]any) {
+		for key, value := range settings {
+			if nested, ok := value.(map[string]any); ok {
+				walk(prefix+key+".", nested)
+				continue
+			}
+			flat[prefix+key] = value
+		}
+	}
+	walk("", settings)
+	return flat
+}
+
+// changedKeys returns the sorted keys added, removed or changed
+func changedKeys(old, updated map[string]any) []string {
+	var changed []string
+	for key := range maps.Keys(old) {
+		if value, ok := updated[key]; !ok || !reflect.DeepEqual(old[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range maps.Keys(updated) {
+		if _, ok := old[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// matchesAny tells whether key is one of keys or below one of them
+func matchesAny(key string, keys []string) bool {
+	for _, k := range keys {
+		if key == k || strings.HasPrefix(key, k+".") {
+			return true
+		}
+	}
+	return false
+}
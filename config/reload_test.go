@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadSchema struct {
+	Log struct {
+		Level string `mapstructure:"level"`
+	} `mapstructure:"log"`
+	Server struct {
+		Port int `mapstructure:"listen-port"`
+	} `mapstructure:"server"`
+	Database struct {
+		Password string `mapstructure:"password" secret:"true"`
+	} `mapstructure:"database"`
+}
+
+func TestConfig_Reload(t *testing.T) {
+	const initial = "log:\n  level: info\nserver:\n  listen-port: 8080\ndatabase:\n  password: hunter2\n"
+
+	tests := []struct {
+		name          string
+		content       string
+		rejected      bool
+		wantLevel     string
+		wantNotified  bool
+		wantLogged    []string
+		wantNotLogged []string
+	}{
+		{
+			name:         "reloadable change",
+			content:      "log:\n  level: debug\nserver:\n  listen-port: 8080\ndatabase:\n  password: hunter2\n",
+			wantLevel:    "debug",
+			wantNotified: true,
+			wantLogged:   []string{"Configuration reloaded", `"log.level":{"new":"debug","old":"info"}`},
+		},
+		{
+			name:       "structural change",
+			content:    "log:\n  level: info\nserver:\n  listen-port: 9090\ndatabase:\n  password: hunter2\n",
+			wantLevel:  "info",
+			wantLogged: []string{"Configuration reloaded", "taking effect on restart only", "server.listen-port"},
+		},
+		{
+			name:          "secret change",
+			content:       "log:\n  level: info\nserver:\n  listen-port: 8080\ndatabase:\n  password: swordfish\n",
+			wantLevel:     "info",
+			wantLogged:    []string{`"database.password":{"new":"<redacted>","old":"<redacted>"}`},
+			wantNotLogged: []string{"hunter2", "swordfish"},
+		},
+		{
+			name:       "invalid change",
+			content:    "log:\n  level: verbose\nserver:\n  listen-port: 8080\ndatabase:\n  password: hunter2\n",
+			rejected:   true,
+			wantLevel:  "info",
+			wantLogged: []string{"Configuration reload rejected", "unknown level verbose"},
+		},
+		{
+			name:       "unreadable file",
+			content:    "log: [",
+			rejected:   true,
+			wantLevel:  "info",
+			wantLogged: []string{"Configuration reload rejected"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "config.test.yaml")
+			require.NoError(t, os.WriteFile(file, []byte(initial), 0o600))
+
+			c := NewConfig(WithEnv("test"), WithConfigPath(dir))
+			require.NoError(t, c.Load())
+
+			var notified bool
+			c.Subscribe(func(loader ConfigLoader) { notified = true }, "log")
+
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+			require.NoError(t, os.WriteFile(file, []byte(tt.content), 0o600))
+			c.reload(logger, validateLevel, reloadSchema{})
+
+			var level string
+			require.NoError(t, c.UnmarshalKey("log.level", &level))
+			assert.Equal(t, tt.wantLevel, level)
+			assert.Equal(t, tt.wantNotified, notified)
+			for _, s := range tt.wantLogged {
+				assert.Contains(t, logs.String(), s)
+			}
+			for _, s := range tt.wantNotLogged {
+				assert.NotContains(t, logs.String(), s)
+			}
+			if tt.rejected {
+				assert.NotContains(t, logs.String(), "Configuration reloaded")
+			}
+		})
+	}
+}
+
+func TestConfig_Reload_KeepsOverrides(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.test.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("log:\n  level: info\n"), 0o600))
+
+	c := NewConfig(WithEnv("test"), WithConfigPath(dir))
+	c.SetDefault("server.listen-port", 8080)
+	require.NoError(t, c.Load())
+	c.Set("log.level", "warn")
+
+	require.NoError(t, os.WriteFile(file, []byte("log:\n  level: debug\n"), 0o600))
+	c.reload(slog.New(slog.DiscardHandler), validateLevel, reloadSchema{})
+
+	var schema reloadSchema
+	require.NoError(t, c.Unmarshal(&schema))
+	assert.Equal(t, "warn", schema.Log.Level)
+	assert.Equal(t, 8080, schema.Server.Port)
+}
+
+func TestConfig_Watch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.test.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("log:\n  level: info\n"), 0o600))
+
+	c := NewConfig(WithEnv("test"), WithConfigPath(dir))
+	require.NoError(t, c.Load())
+
+	var level atomic.Value
+	c.Subscribe(func(loader ConfigLoader) {
+		var l string
+		_ = loader.UnmarshalKey("log.level", &l)
+		level.Store(l)
+	}, "log.level")
+	c.Watch(slog.New(slog.DiscardHandler), validateLevel, reloadSchema{})
+
+	require.NoError(t, os.WriteFile(file, []byte("log:\n  level: debug\n"), 0o600))
+
+	assert.Eventually(t, func() bool {
+		return level.Load() == "debug"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func validateLevel(candidate ConfigLoader) error {
+	var schema reloadSchema
+	if err := candidate.Unmarshal(&schema); err != nil {
+		return err
+	}
+	switch schema.Log.Level {
+	case "debug", "info", "warn", "error":
+		return nil
+	}
+	return errors.New("unknown level " + schema.Log.Level)
+}
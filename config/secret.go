@@ -0,0 +1,109 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces the values of the secrets in the dumps of the configuration
+const Redacted = "<redacted>"
+
+// secretTag marks the fields holding secrets, e.g.
+//
+//	Password string `mapstructure:"password" secret:"true"`
+const secretTag = "secret"
+
+// Redact returns a copy of settings, as returned by AllSettings, fit for dumps
+// and logs: the values of the fields of target tagged as secret are redacted,
+// and so are the keys matching no field, which may hold anything.
+// Empty secrets are left as is, telling they are not set.
+func Redact(settings map[string]any, target any) map[string]any {
+	redacted, _ := redactValue(settings, reflect.TypeOf(target)).(map[string]any)
+	return redacted
+}
+
+func redactValue(value any, t reflect.Type) any {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		settings, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		result := make(map[string]any, len(settings))
+		for key, v := range settings {
+			field, found := fieldByKey(t, key)
+			switch {
+			case !found, field.Tag.Get(secretTag) == "true" && !isEmpty(v):
+				result[key] = Redacted
+			default:
+				result[key] = redactValue(v, field.Type)
+			}
+		}
+		return result
+	case reflect.Map:
+		settings, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		result := make(map[string]any, len(settings))
+		for key, v := range settings {
+			result[key] = redactValue(v, t.Elem())
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		result := make([]any, len(items))
+		for i, item := range items {
+			result[i] = redactValue(item, t.Elem())
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// fieldByKey finds the field of struct type t decoded from key, looking into
+// the squashed fields
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if options == "squash" {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				if squashed, found := fieldByKey(fieldType, key); found {
+					return squashed, true
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		// the keys are case insensitive
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func isEmpty(value any) bool {
+	return value == nil || value == ""
+}
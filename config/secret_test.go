@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLimits struct {
+	Rate int `mapstructure:"rate"`
+}
+
+type testRoute struct {
+	Route      string `mapstructure:"route"`
+	testLimits `mapstructure:",squash"`
+	Token      string `mapstructure:"token" secret:"true"`
+}
+
+type testConfig struct {
+	Database struct {
+		Host     string `mapstructure:"host"`
+		Password string `mapstructure:"password" secret:"true"`
+		Unused   string `mapstructure:"user"`
+	} `mapstructure:"database"`
+	Routes []testRoute           `mapstructure:"routes"`
+	Peers  map[string]*testRoute `mapstructure:"peers"`
+}
+
+func TestRedact(t *testing.T) {
+	settings := map[string]any{
+		"database": map[string]any{
+			"host":     "localhost",
+			"password": "hunter2",
+			"user":     "",
+			"typo":     "anything",
+		},
+		"routes": []any{
+			map[string]any{"route": "POST /media", "rate": 5, "token": "s3cr3t"},
+			map[string]any{"route": "GET /media", "rate": 10, "token": ""},
+		},
+		"peers": map[string]any{
+			"gateway": map[string]any{"route": "/", "token": "t0k3n"},
+		},
+		"unknown": "value",
+	}
+
+	redacted := Redact(settings, &testConfig{})
+
+	assert.Equal(t, map[string]any{
+		"database": map[string]any{
+			"host":     "localhost",
+			"password": Redacted,
+			"user":     "",
+			"typo":     Redacted,
+		},
+		"routes": []any{
+			map[string]any{"route": "POST /media", "rate": 5, "token": Redacted},
+			map[string]any{"route": "GET /media", "rate": 10, "token": ""},
+		},
+		"peers": map[string]any{
+			"gateway": map[string]any{"route": "/", "token": Redacted},
+		},
+		"unknown": Redacted,
+	}, redacted)
+
+	// the settings are left untouched
+	assert.Equal(t, "hunter2", settings["database"].(map[string]any)["password"])
+}
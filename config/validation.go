@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError is a problem with the value of a configuration key
+type FieldError struct {
+	// Key is the path of the key, e.g. database.ssl-mode
+	Key     string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Key + ": " + e.Message
+}
+
+// Invalid reports a problem with the value of key
+func Invalid(key, format string, args ...any) FieldError {
+	return FieldError{Key: key, Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidationErrors are all the problems found in a configuration
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return "invalid configuration: " + strings.Join(messages, "; ")
+}
+
+// Collect gathers errs, as returned by Invalid and by the Validate methods of
+// the nested configurations, into ValidationErrors, their keys prefixed with
+// prefix. It returns nil when there is no error.
+func Collect(prefix string, errs ...error) error {
+	var result ValidationErrors
+	for _, err := range errs {
+		var validationErrs ValidationErrors
+		var fieldErr FieldError
+		switch {
+		case err == nil:
+		case errors.As(err, &validationErrs):
+			for _, nested := range validationErrs {
+				result = append(result, prefixed(prefix, nested))
+			}
+		case errors.As(err, &fieldErr):
+			result = append(result, prefixed(prefix, fieldErr))
+		default:
+			result = append(result, FieldError{Key: prefix, Message: err.Error()})
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func prefixed(prefix string, err FieldError) FieldError {
+	if prefix != "" {
+		err.Key = prefix + "." + err.Key
+	}
+	return err
+}
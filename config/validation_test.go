@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollect(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		errs     []error
+		expected error
+	}{
+		{
+			name:     "no error",
+			prefix:   "database",
+			errs:     []error{nil, nil},
+			expected: nil,
+		},
+		{
+			name:   "field errors prefixed",
+			prefix: "database",
+			errs: []error{
+				Invalid("port", "must be between 1 and %d", 65535),
+				nil,
+				Invalid("ssl-mode", "unknown mode %q", "sometimes"),
+			},
+			expected: ValidationErrors{
+				{Key: "database.port", Message: "must be between 1 and 65535"},
+				{Key: "database.ssl-mode", Message: `unknown mode "sometimes"`},
+			},
+		},
+		{
+			name: "nested validation errors flattened",
+			errs: []error{
+				Collect("server", Invalid("listen-port", "must be set")),
+				Collect("s3", Invalid("bucket-name", "must be set"), Invalid("region", "must be set")),
+				errors.New("unexpected"),
+			},
+			expected: ValidationErrors{
+				{Key: "server.listen-port", Message: "must be set"},
+				{Key: "s3.bucket-name", Message: "must be set"},
+				{Key: "s3.region", Message: "must be set"},
+				{Key: "", Message: "unexpected"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Collect(tt.prefix, tt.errs...)
+			if tt.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestValidationErrors_Error(t *testing.T) {
+	err := ValidationErrors{
+		{Key: "server.listen-port", Message: "must be between 1 and 65535"},
+		{Key: "s3.bucket-name", Message: "must be set"},
+	}
+
+	assert.Equal(t, "invalid configuration: server.listen-port: must be between 1 and 65535; s3.bucket-name: must be set", err.Error())
+}
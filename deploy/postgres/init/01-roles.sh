@@ -0,0 +1,18 @@
+#!/bin/sh
+# Provisions the roles of the service on the first start of the database.
+# medias_owner owns the schema and applies the migrations. medias_app is the
+# role the service connects as: it owns nothing and is neither a superuser
+# nor BYPASSRLS, so the row-level security policies isolating the tenants
+# apply to it.
+set -e
+
+psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<-EOSQL
+	CREATE ROLE medias_owner LOGIN PASSWORD '${MEDIAS_OWNER_PASSWORD}' NOSUPERUSER NOBYPASSRLS;
+	ALTER DATABASE "${POSTGRES_DB}" OWNER TO medias_owner;
+
+	CREATE ROLE medias_app LOGIN PASSWORD '${MEDIAS_APP_PASSWORD}' NOSUPERUSER NOBYPASSRLS;
+	ALTER DEFAULT PRIVILEGES FOR ROLE medias_owner IN SCHEMA public
+		GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO medias_app;
+	ALTER DEFAULT PRIVILEGES FOR ROLE medias_owner IN SCHEMA public
+		GRANT USAGE, SELECT ON SEQUENCES TO medias_app;
+EOSQL
@@ -1,7 +1,10 @@
 package s3
 
 import (
-	"os"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
 
 	"github.com/peano88/medias/config"
 )
@@ -13,17 +16,80 @@ type Config struct {
 	PublicEndpoint string `mapstructure:"public-endpoint"`
 	BucketName     string `mapstructure:"bucket-name"`
 	UploadExpiry   int    `mapstructure:"upload-expiry"` // in seconds
+	// DownloadExpiry is the validity of the download URLs in seconds, the
+	// upload one when 0
+	DownloadExpiry int `mapstructure:"download-expiry"`
+	// AccessKeyID and SecretAccessKey are read from the standard AWS
+	// environment variables, or the files named by their _FILE variants
+	AccessKeyID     string `mapstructure:"access-key-id" secret:"true"`
+	SecretAccessKey string `mapstructure:"secret-access-key" secret:"true"`
 }
 
 func SetDefaultConfig(loader config.ConfigLoader, prefix string) {
 	loader.SetDefault(prefix+".region", "eu-central-1")
 	loader.SetDefault(prefix+".upload-expiry", 3600)
+	loader.SetDefault(prefix+".download-expiry", 0)
+	loader.BindEnv(prefix+".access-key-id", "AWS_ACCESS_KEY_ID")
+	loader.BindEnv(prefix+".secret-access-key", "AWS_SECRET_ACCESS_KEY")
 }
 
-// Credentials returns AWS credentials from environment variables
-// Uses standard AWS environment variable names
+// Credentials returns the AWS credentials
 func (c *Config) Credentials() (accessKeyID, secretAccessKey string) {
-	accessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
-	secretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
-	return
+	return c.AccessKeyID, c.SecretAccessKey
+}
+
+func (c *Config) uploadExpiry() time.Duration {
+	return time.Duration(c.UploadExpiry) * time.Second
+}
+
+func (c *Config) downloadExpiry() time.Duration {
+	if c.DownloadExpiry == 0 {
+		return c.uploadExpiry()
+	}
+	return time.Duration(c.DownloadExpiry) * time.Second
+}
+
+// maxUploadExpiry is the longest validity of a presigned URL
+const maxUploadExpiry = 7 * 24 * 3600
+
+// bucketNamePattern matches the general purpose bucket names
+var bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// Validate reports all the problems of the configuration, with their keys
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Region == "" {
+		errs = append(errs, config.Invalid("region", "must be set"))
+	}
+	if c.BucketName == "" {
+		errs = append(errs, config.Invalid("bucket-name", "must be set"))
+	} else if !bucketNamePattern.MatchString(c.BucketName) {
+		errs = append(errs, config.Invalid("bucket-name", "must be 3 to 63 lowercase letters, digits, dots or hyphens, got %q", c.BucketName))
+	}
+	if err := validateEndpoint(c.Endpoint); err != nil {
+		errs = append(errs, config.Invalid("endpoint", "%s", err))
+	}
+	if err := validateEndpoint(c.PublicEndpoint); err != nil {
+		errs = append(errs, config.Invalid("public-endpoint", "%s", err))
+	}
+	if c.UploadExpiry < 1 || c.UploadExpiry > maxUploadExpiry {
+		errs = append(errs, config.Invalid("upload-expiry", "must be between 1 and %d seconds, got %d", maxUploadExpiry, c.UploadExpiry))
+	}
+	if c.DownloadExpiry < 0 || c.DownloadExpiry > maxUploadExpiry {
+		errs = append(errs, config.Invalid("download-expiry", "must be between 0 and %d seconds, got %d", maxUploadExpiry, c.DownloadExpiry))
+	}
+	return config.Collect("", errs...)
+}
+
+// validateEndpoint accepts an empty endpoint, the default of AWS, or an
+// absolute http(s) URL
+func validateEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an absolute http or https URL, got %q", endpoint)
+	}
+	return nil
 }
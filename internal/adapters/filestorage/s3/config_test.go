@@ -0,0 +1,91 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/peano88/medias/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() Config {
+		return Config{
+			Region:         "eu-central-1",
+			Endpoint:       "http://localhost:9000",
+			PublicEndpoint: "https://media.example.com",
+			BucketName:     "medias-dev",
+			UploadExpiry:   3600,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(cfg *Config)
+		wantKeys []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(cfg *Config) {},
+		},
+		{
+			name: "default endpoints",
+			mutate: func(cfg *Config) {
+				cfg.Endpoint = ""
+				cfg.PublicEndpoint = ""
+			},
+		},
+		{
+			name: "missing region and bucket",
+			mutate: func(cfg *Config) {
+				cfg.Region = ""
+				cfg.BucketName = ""
+			},
+			wantKeys: []string{"region", "bucket-name"},
+		},
+		{
+			name:     "bucket name with uppercase letters",
+			mutate:   func(cfg *Config) { cfg.BucketName = "Medias" },
+			wantKeys: []string{"bucket-name"},
+		},
+		{
+			name:     "bucket name too short",
+			mutate:   func(cfg *Config) { cfg.BucketName = "me" },
+			wantKeys: []string{"bucket-name"},
+		},
+		{
+			name: "relative endpoints",
+			mutate: func(cfg *Config) {
+				cfg.Endpoint = "localhost:9000"
+				cfg.PublicEndpoint = "ftp://media.example.com"
+			},
+			wantKeys: []string{"endpoint", "public-endpoint"},
+		},
+		{
+			name:     "upload expiry beyond a week",
+			mutate:   func(cfg *Config) { cfg.UploadExpiry = maxUploadExpiry + 1 },
+			wantKeys: []string{"upload-expiry"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.mutate(&cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantKeys) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErrs config.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+			keys := make([]string, len(validationErrs))
+			for i, fieldErr := range validationErrs {
+				keys[i] = fieldErr.Key
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package s3
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// Name identifies the check in the readiness report
+func (m *MediaSaver) Name() string {
+	return "s3"
+}
+
+// CheckHealth checks the bucket of the media is reachable
+func (m *MediaSaver) CheckHealth(ctx context.Context) error {
+	if _, err := m.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(m.bucketName),
+	}); err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("bucket unreachable"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package s3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMediaSaver_CheckHealth(t *testing.T) {
+	ctx := context.Background()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	assert.Equal(t, "s3", testMediaSaver.Name())
+	assert.NoError(t, testMediaSaver.CheckHealth(ctx))
+	assert.True(t, domain.HasCode(testMediaSaver.CheckHealth(cancelCtx), domain.InternalCode))
+
+	missingBucket := &MediaSaver{client: testMediaSaver.client, bucketName: "missing-bucket"}
+	assert.True(t, domain.HasCode(missingBucket.CheckHealth(ctx), domain.InternalCode))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -16,10 +17,12 @@ import (
 )
 
 type MediaSaver struct {
-	client         *s3.Client
-	presignClient  *s3.PresignClient
-	bucketName     string
-	uploadExpiry   time.Duration
+	client        *s3.Client
+	presignClient *s3.PresignClient
+	bucketName    string
+	// uploadExpiry and downloadExpiry are reloadable, see UpdateExpiry
+	uploadExpiry   atomic.Int64
+	downloadExpiry atomic.Int64
 	endpoint       string
 	publicEndpoint string
 }
@@ -39,6 +42,7 @@ func NewMediaSaver(ctx context.Context, cfg Config, logger *slog.Logger) (*Media
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
+	awsConfig.APIOptions = append(awsConfig.APIOptions, traceOperations)
 
 	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
 		if cfg.Endpoint != "" {
@@ -70,14 +74,22 @@ func NewMediaSaver(ctx context.Context, cfg Config, logger *slog.Logger) (*Media
 		})
 	}
 
-	return &MediaSaver{
+	saver := &MediaSaver{
 		client:         client,
 		presignClient:  s3.NewPresignClient(presignClient),
 		bucketName:     cfg.BucketName,
-		uploadExpiry:   time.Duration(cfg.UploadExpiry) * time.Second,
 		endpoint:       cfg.Endpoint,
 		publicEndpoint: publicEndpoint,
-	}, nil
+	}
+	saver.UpdateExpiry(cfg)
+	return saver, nil
+}
+
+// UpdateExpiry applies the validity of the presigned URLs of cfg to the URLs
+// generated from now on
+func (m *MediaSaver) UpdateExpiry(cfg Config) {
+	m.uploadExpiry.Store(int64(cfg.uploadExpiry()))
+	m.downloadExpiry.Store(int64(cfg.downloadExpiry()))
 }
 
 // ensureBucketExists checks if the bucket exists and creates it if it doesn't
@@ -108,9 +120,13 @@ func ensureBucketExists(ctx context.Context, client *s3.Client, bucketName, regi
 	return nil
 }
 
-// mediaKey generates the S3 key for a media file
+// mediaKey generates the S3 key for a media file. The files of the default
+// tenant keep the unprefixed keys they were stored under before multi-tenancy.
 func (m *MediaSaver) mediaKey(media domain.Media) string {
-	return fmt.Sprintf("%s/%s", media.SHA256, media.Filename)
+	if media.Tenant == "" || media.Tenant == domain.DefaultTenant {
+		return fmt.Sprintf("%s/%s", media.SHA256, media.Filename)
+	}
+	return fmt.Sprintf("tenants/%s/%s/%s", media.Tenant, media.SHA256, media.Filename)
 }
 
 // GenerateUploadURL generates a presigned URL for uploading a media file
@@ -123,7 +139,7 @@ func (m *MediaSaver) GenerateUploadURL(ctx context.Context, media domain.Media)
 		ChecksumSHA256: aws.String(media.SHA256),
 		//ContentLength:  aws.Int64(media.Size),
 	}, func(opts *s3.PresignOptions) {
-		opts.Expires = m.uploadExpiry
+		opts.Expires = time.Duration(m.uploadExpiry.Load())
 	})
 
 	if err != nil {
@@ -145,7 +161,7 @@ func (m *MediaSaver) GenerateDownloadURL(ctx context.Context, media domain.Media
 		Bucket: aws.String(m.bucketName),
 		Key:    aws.String(key),
 	}, func(opts *s3.PresignOptions) {
-		opts.Expires = m.uploadExpiry // reuse same expiry duration
+		opts.Expires = m.DownloadURLExpiry()
 	})
 
 	if err != nil {
@@ -159,6 +175,11 @@ func (m *MediaSaver) GenerateDownloadURL(ctx context.Context, media domain.Media
 	return request.URL, nil
 }
 
+// DownloadURLExpiry returns the validity of the URLs generated by GenerateDownloadURL
+func (m *MediaSaver) DownloadURLExpiry() time.Duration {
+	return time.Duration(m.downloadExpiry.Load())
+}
+
 // VerifyMediaExists checks if a media file exists in S3
 func (m *MediaSaver) VerifyMediaExists(ctx context.Context, media domain.Media) (bool, error) {
 	key := m.mediaKey(media)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -22,6 +23,7 @@ func TestMediaSaver_GenerateUploadURL(t *testing.T) {
 		filename string
 		sha256   string
 		size     int64
+		tenant   string
 		validate func(*testing.T, string, error)
 	}{
 		{
@@ -56,6 +58,18 @@ func TestMediaSaver_GenerateUploadURL(t *testing.T) {
 				assert.Contains(t, url, "X-Amz-Checksum-Sha256")
 			},
 		},
+		{
+			name:     "success - key prefixed by the tenant",
+			ctx:      ctx,
+			filename: "world-cup-goal.jpg",
+			sha256:   "w0rldcupg04l",
+			size:     2048000,
+			tenant:   "acme",
+			validate: func(t *testing.T, url string, err error) {
+				assert.NoError(t, err)
+				assert.Contains(t, url, "tenants/acme/w0rldcupg04l/world-cup-goal.jpg")
+			},
+		},
 		// TODO : this test case is not working.. really weird
 		/*
 			{
@@ -76,6 +90,7 @@ func TestMediaSaver_GenerateUploadURL(t *testing.T) {
 				Filename: tt.filename,
 				SHA256:   tt.sha256,
 				Size:     tt.size,
+				Tenant:   tt.tenant,
 			})
 			tt.validate(t, url, err)
 		})
@@ -140,3 +155,15 @@ func TestMediaSaver_VerifyMediaExists(t *testing.T) {
 		})
 	}
 }
+
+func TestMediaSaver_UpdateExpiry(t *testing.T) {
+	saver := &MediaSaver{}
+
+	saver.UpdateExpiry(Config{UploadExpiry: 600})
+	assert.Equal(t, 10*time.Minute, time.Duration(saver.uploadExpiry.Load()))
+	assert.Equal(t, 10*time.Minute, saver.DownloadURLExpiry())
+
+	saver.UpdateExpiry(Config{UploadExpiry: 600, DownloadExpiry: 3600})
+	assert.Equal(t, 10*time.Minute, time.Duration(saver.uploadExpiry.Load()))
+	assert.Equal(t, time.Hour, saver.DownloadURLExpiry())
+}
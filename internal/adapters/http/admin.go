@@ -0,0 +1,69 @@
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimdw "github.com/go-chi/chi/v5/middleware"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// AdminDependencies are the dependencies of the admin router, served on a
+// listener of its own
+type AdminDependencies struct {
+	// MetricsHandler exposes the metrics at /metrics
+	MetricsHandler http.Handler
+	// HealthCheckers are the dependencies checked by the readiness probe
+	HealthCheckers []HealthChecker
+	// HealthCheckTimeout bounds each readiness check
+	HealthCheckTimeout time.Duration
+	// AuthToken, when set, is the bearer token required on every route but
+	// the health probes
+	AuthToken string
+}
+
+// NewAdminRouter serves the health probes, the metrics, expvar at
+// /debug/vars and pprof at /debug/pprof/
+func NewAdminRouter(deps AdminDependencies) chi.Router {
+	r := chi.NewRouter()
+	r.Use(chimdw.Recoverer)
+
+	// the probes of the orchestrator carry no credentials
+	r.Get("/health", HandleHealthLive())
+	r.Get("/health/live", HandleHealthLive())
+	r.Get("/health/ready", HandleHealthReady(deps.HealthCheckers, deps.HealthCheckTimeout))
+
+	r.Group(func(r chi.Router) {
+		r.Use(adminAuthMiddleware(deps.AuthToken))
+		if deps.MetricsHandler != nil {
+			r.Method(http.MethodGet, "/metrics", deps.MetricsHandler)
+		}
+		r.Mount("/debug", chimdw.Profiler())
+	})
+
+	return r
+}
+
+// adminAuthMiddleware rejects the requests without token as bearer token.
+// Authentication is disabled when token is empty.
+func adminAuthMiddleware(token string) middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		if token == "" {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided, ok := bearerToken(r)
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				errDetails := "the request must carry the admin token as bearer token"
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				respondWithError(r.Context(), w, http.StatusUnauthorized, domain.UnauthenticatedCode,
+					"Authentication required", &errDetails, nil)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAdminRouter(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("medias_http_requests_total 1\n"))
+	})
+
+	tests := []struct {
+		name           string
+		authToken      string
+		path           string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "health without auth", path: "/health/live", expectedStatus: http.StatusOK},
+		{name: "readiness without auth", path: "/health/ready", expectedStatus: http.StatusOK},
+		{name: "expvar without auth", path: "/debug/vars", expectedStatus: http.StatusOK},
+		{name: "pprof without auth", path: "/debug/pprof/", expectedStatus: http.StatusOK},
+		{name: "metrics without auth", path: "/metrics", expectedStatus: http.StatusOK},
+		{name: "health with auth", authToken: "s3cret", path: "/health/ready", expectedStatus: http.StatusOK},
+		{name: "metrics without token", authToken: "s3cret", path: "/metrics", expectedStatus: http.StatusUnauthorized},
+		{name: "pprof with a wrong token", authToken: "s3cret", path: "/debug/pprof/", authorization: "Bearer guess", expectedStatus: http.StatusUnauthorized},
+		{name: "expvar with the token", authToken: "s3cret", path: "/debug/vars", authorization: "Bearer s3cret", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewAdminRouter(AdminDependencies{MetricsHandler: metrics, AuthToken: tt.authToken})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestNewRouter_NoAdminRoutes(t *testing.T) {
+	r := NewRouter(Dependencies{Logger: slog.New(slog.DiscardHandler)})
+
+	for _, path := range []string{"/health", "/health/ready", "/debug/vars", "/debug/pprof/", "/metrics"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/httplog/v3"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// APIKeyHeader is the header carrying the API key of the client
+const APIKeyHeader = "X-API-Key"
+
+type Authenticator interface {
+	Execute(ctx context.Context, key string) (domain.Principal, error)
+}
+
+type TokenAuthenticator interface {
+	Execute(ctx context.Context, token string) (domain.Principal, error)
+}
+
+type AuditRecorder interface {
+	RecordAuditEvent(ctx context.Context, event domain.AuditEvent) error
+}
+
+// authenticationEnabled reports whether any authenticator is configured
+func authenticationEnabled(deps Dependencies) bool {
+	return deps.Authenticator != nil || deps.TokenAuthenticator != nil
+}
+
+// authenticationMiddleware authenticates the client from its bearer token or
+// its API key. Requests without credentials go through unauthenticated:
+// requireScope rejects them on the routes needing a permission.
+// Authentication is disabled when there is no authenticator.
+func authenticationMiddleware(deps Dependencies) middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		if !authenticationEnabled(deps) {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, authenticated, err := authenticate(r, deps)
+			if err != nil {
+				if domain.HasCode(err, domain.UnauthenticatedCode) {
+					w.Header().Set("WWW-Authenticate", authenticateChallenge(deps))
+				}
+				handleExecutorError(r.Context(), w, err)
+				return
+			}
+			if !authenticated {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			httplog.SetAttrs(r.Context(), slog.String("principal", principal.Subject))
+			next.ServeHTTP(w, r.WithContext(domain.ContextWithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// authenticate authenticates the credentials of the request, if any. A bearer
+// token takes precedence over an API key.
+func authenticate(r *http.Request, deps Dependencies) (domain.Principal, bool, error) {
+	if token, ok := bearerToken(r); ok {
+		if deps.TokenAuthenticator == nil {
+			errDetails := "bearer tokens are not accepted"
+			return domain.Principal{}, false, domain.NewError(domain.UnauthenticatedCode,
+				domain.WithMessage("invalid token"),
+				domain.WithDetails(errDetails),
+			)
+		}
+		principal, err := deps.TokenAuthenticator.Execute(r.Context(), token)
+		return principal, err == nil, err
+	}
+
+	if key := r.Header.Get(APIKeyHeader); key != "" && deps.Authenticator != nil {
+		principal, err := deps.Authenticator.Execute(r.Context(), key)
+		return principal, err == nil, err
+	}
+
+	return domain.Principal{}, false, nil
+}
+
+// bearerToken returns the token of an Authorization header using the Bearer
+// scheme
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// authenticateChallenge tells unauthenticated clients how to authenticate
+func authenticateChallenge(deps Dependencies) string {
+	var challenges []string
+	if deps.TokenAuthenticator != nil {
+		challenges = append(challenges, "Bearer")
+	}
+	if deps.Authenticator != nil {
+		challenges = append(challenges, `APIKey header="`+APIKeyHeader+`"`)
+	}
+	return strings.Join(challenges, ", ")
+}
+
+// requireScope rejects the requests whose client was not granted the scope
+// needed for the action, with 401 when the client is not authenticated and 403
+// otherwise. The 403 are written to the audit trail.
+func requireScope(deps Dependencies, scope domain.Scope, action domain.Action) middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		if !authenticationEnabled(deps) {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := domain.PrincipalFromContext(r.Context())
+			if !ok {
+				errDetails := "the request must carry a bearer token or an API key in the " + APIKeyHeader + " header"
+				w.Header().Set("WWW-Authenticate", authenticateChallenge(deps))
+				respondWithError(r.Context(), w, http.StatusUnauthorized, domain.UnauthenticatedCode,
+					"Authentication required", &errDetails, nil)
+				return
+			}
+
+			if !principal.HasScope(scope) {
+				errDetails := "the scope " + string(scope) + " is required"
+				if err := recordDenial(r.Context(), deps, domain.TenantFromContext(r.Context()), action, errDetails); err != nil {
+					handleExecutorError(r.Context(), w, err)
+					return
+				}
+				respondWithError(r.Context(), w, http.StatusForbidden, domain.ForbiddenCode,
+					"Insufficient scope", &errDetails, nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// recordDenial writes the denial of the action to the principal carried by
+// ctx, in tenant, to deps.AuditRecorder if any. Like the denials of the
+// authorizer, a denial which cannot be recorded fails the request.
+func recordDenial(ctx context.Context, deps Dependencies, tenant string, action domain.Action, details string) error {
+	if deps.AuditRecorder == nil {
+		return nil
+	}
+
+	err := deps.AuditRecorder.RecordAuditEvent(ctx, domain.AuditEvent{
+		Tenant:  tenant,
+		Subject: domain.SubjectFromContext(ctx),
+		Action:  action,
+		Outcome: domain.AuditOutcomeDenied,
+		Details: details,
+	})
+	if err != nil {
+		return domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error recording denial: %s", err)))
+	}
+	return nil
+}
+
+// authorize rejects the requests whose principal's role does not allow the
+// action, as decided by deps.Authorizer. The authorizer is handed to the use
+// cases through the context, for the checks they perform themselves.
+// Authorization is disabled along with authentication, or when there is no
+// authorizer.
+func authorize(deps Dependencies, action domain.Action) middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		if !authenticationEnabled(deps) || deps.Authorizer == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := domain.ContextWithAuthorizer(r.Context(), deps.Authorizer)
+			if err := domain.Authorize(ctx, action); err != nil {
+				handleExecutorError(ctx, w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
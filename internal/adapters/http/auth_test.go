@@ -0,0 +1,363 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_authenticator.go -package=mocks github.com/peano88/medias/internal/adapters/http Authenticator
+//go:generate mockgen -destination=mocks/mock_token_authenticator.go -package=mocks github.com/peano88/medias/internal/adapters/http TokenAuthenticator
+//go:generate mockgen -destination=mocks/mock_authorizer.go -package=mocks github.com/peano88/medias/internal/domain Authorizer
+//go:generate mockgen -destination=mocks/mock_audit_recorder.go -package=mocks github.com/peano88/medias/internal/adapters/http AuditRecorder
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthentication(t *testing.T) {
+	reader := domain.Principal{Subject: "api-key:reader", Scopes: []domain.Scope{domain.ScopeMediaRead}}
+	user := domain.Principal{Subject: "user-42", Scopes: []domain.Scope{domain.ScopeMediaRead}}
+	challenge := `Bearer, APIKey header="X-API-Key"`
+
+	tests := []struct {
+		name          string
+		apiKey        string
+		authorization string
+		disabled      bool
+		apiKeysOnly   bool
+		setupMock     func(*mocks.MockAuthenticator)
+		setupToken    func(*mocks.MockTokenAuthenticator)
+		expectedCode  string
+		expectedCalls int
+		validate      func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "success - key granted the scope",
+			apiKey: "mk_reader",
+			setupMock: func(a *mocks.MockAuthenticator) {
+				a.EXPECT().Execute(gomock.Any(), "mk_reader").Return(reader, nil)
+			},
+			expectedCalls: 1,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name:      "missing key",
+			setupMock: func(a *mocks.MockAuthenticator) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+				assert.Equal(t, challenge, rec.Header().Get("WWW-Authenticate"))
+				assertErrorCode(t, rec, domain.UnauthenticatedCode)
+			},
+		},
+		{
+			name:   "invalid key",
+			apiKey: "mk_unknown",
+			setupMock: func(a *mocks.MockAuthenticator) {
+				a.EXPECT().Execute(gomock.Any(), "mk_unknown").
+					Return(domain.Principal{}, domain.NewError(domain.UnauthenticatedCode, domain.WithMessage("invalid API key")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+				assert.Equal(t, challenge, rec.Header().Get("WWW-Authenticate"))
+				assertErrorCode(t, rec, domain.UnauthenticatedCode)
+			},
+		},
+		{
+			name:   "authenticator failure",
+			apiKey: "mk_reader",
+			setupMock: func(a *mocks.MockAuthenticator) {
+				a.EXPECT().Execute(gomock.Any(), "mk_reader").
+					Return(domain.Principal{}, domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, rec.Code)
+				assert.Empty(t, rec.Header().Get("WWW-Authenticate"))
+			},
+		},
+		{
+			name:   "missing scope",
+			apiKey: "mk_writer",
+			setupMock: func(a *mocks.MockAuthenticator) {
+				a.EXPECT().Execute(gomock.Any(), "mk_writer").
+					Return(domain.Principal{Subject: "api-key:writer", Scopes: []domain.Scope{domain.ScopeMediaWrite}}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, rec.Code)
+				assertErrorCode(t, rec, domain.ForbiddenCode)
+			},
+		},
+		{
+			name:          "success - bearer token granted the scope",
+			authorization: "Bearer eyJ.token",
+			setupMock:     func(a *mocks.MockAuthenticator) {},
+			setupToken: func(ta *mocks.MockTokenAuthenticator) {
+				ta.EXPECT().Execute(gomock.Any(), "eyJ.token").Return(user, nil)
+			},
+			expectedCalls: 1,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name:          "bearer token takes precedence over API key",
+			apiKey:        "mk_reader",
+			authorization: "bearer eyJ.token",
+			setupMock:     func(a *mocks.MockAuthenticator) {},
+			setupToken: func(ta *mocks.MockTokenAuthenticator) {
+				ta.EXPECT().Execute(gomock.Any(), "eyJ.token").
+					Return(domain.Principal{}, domain.NewError(domain.UnauthenticatedCode, domain.WithMessage("invalid token")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+				assert.Equal(t, challenge, rec.Header().Get("WWW-Authenticate"))
+				assertErrorCode(t, rec, domain.UnauthenticatedCode)
+			},
+		},
+		{
+			name:          "other authorization scheme is ignored",
+			authorization: "Basic dXNlcjpwYXNz",
+			setupMock:     func(a *mocks.MockAuthenticator) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+				assertErrorCode(t, rec, domain.UnauthenticatedCode)
+			},
+		},
+		{
+			name:          "bearer token not accepted",
+			authorization: "Bearer eyJ.token",
+			apiKeysOnly:   true,
+			setupMock:     func(a *mocks.MockAuthenticator) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+				assert.Equal(t, `APIKey header="X-API-Key"`, rec.Header().Get("WWW-Authenticate"))
+				assertErrorCode(t, rec, domain.UnauthenticatedCode)
+			},
+		},
+		{
+			name:          "authentication disabled",
+			disabled:      true,
+			setupMock:     func(a *mocks.MockAuthenticator) {},
+			expectedCalls: 1,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authenticator := mocks.NewMockAuthenticator(ctrl)
+			tt.setupMock(authenticator)
+			tokenAuthenticator := mocks.NewMockTokenAuthenticator(ctrl)
+			if tt.setupToken != nil {
+				tt.setupToken(tokenAuthenticator)
+			}
+
+			deps := Dependencies{Authenticator: authenticator, TokenAuthenticator: tokenAuthenticator}
+			if tt.apiKeysOnly {
+				deps.TokenAuthenticator = nil
+			}
+			if tt.disabled {
+				deps = Dependencies{}
+			}
+
+			calls := 0
+			r := chi.NewRouter()
+			r.Use(authenticationMiddleware(deps))
+			r.With(requireScope(deps, domain.ScopeMediaRead, domain.ActionReadMedia)).Get("/media", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/media", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCalls, calls)
+			tt.validate(t, rec)
+		})
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	user := domain.Principal{Subject: "user-42", Scopes: []domain.Scope{domain.ScopeMediaWrite}}
+
+	tests := []struct {
+		name           string
+		disabled       bool
+		setupMock      func(*mocks.MockAuthorizer)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "allowed - the authorizer is available to the handler",
+			setupMock: func(a *mocks.MockAuthorizer) {
+				a.EXPECT().Authorize(gomock.Any(), domain.ActionWriteMedia).Return(nil)
+				// the check performed by the handler
+				a.EXPECT().Authorize(gomock.Any(), domain.ActionManageRoles).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "denied",
+			setupMock: func(a *mocks.MockAuthorizer) {
+				a.EXPECT().Authorize(gomock.Any(), domain.ActionWriteMedia).
+					Return(domain.NewError(domain.ForbiddenCode, domain.WithMessage("action not allowed")))
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   domain.ForbiddenCode,
+		},
+		{
+			name: "authorizer failure",
+			setupMock: func(a *mocks.MockAuthorizer) {
+				a.EXPECT().Authorize(gomock.Any(), domain.ActionWriteMedia).
+					Return(domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   domain.InternalCode,
+		},
+		{
+			name:           "authentication disabled",
+			disabled:       true,
+			setupMock:      func(a *mocks.MockAuthorizer) {},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authorizer := mocks.NewMockAuthorizer(ctrl)
+			tt.setupMock(authorizer)
+
+			deps := Dependencies{Authenticator: mocks.NewMockAuthenticator(ctrl), Authorizer: authorizer}
+			if tt.disabled {
+				deps = Dependencies{Authorizer: authorizer}
+			}
+
+			r := chi.NewRouter()
+			r.With(authorize(deps, domain.ActionWriteMedia)).Post("/media", func(w http.ResponseWriter, r *http.Request) {
+				if err := domain.Authorize(r.Context(), domain.ActionManageRoles); err != nil {
+					w.WriteHeader(http.StatusForbidden)
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/media", nil)
+			req = req.WithContext(domain.ContextWithPrincipal(req.Context(), user))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedCode != "" {
+				assertErrorCode(t, rec, tt.expectedCode)
+			}
+		})
+	}
+}
+
+func TestDenialAudit(t *testing.T) {
+	reader := domain.Principal{Subject: "api-key:reader", Scopes: []domain.Scope{domain.ScopeMediaRead}, Tenant: "acme"}
+
+	tests := []struct {
+		name           string
+		tenant         string
+		setupMock      func(*mocks.MockAuditRecorder)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "insufficient scope is recorded",
+			setupMock: func(a *mocks.MockAuditRecorder) {
+				a.EXPECT().RecordAuditEvent(gomock.Any(), domain.AuditEvent{
+					Tenant:  "acme",
+					Subject: "api-key:reader",
+					Action:  domain.ActionWriteMedia,
+					Outcome: domain.AuditOutcomeDenied,
+					Details: "the scope media:write is required",
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   domain.ForbiddenCode,
+		},
+		{
+			name:   "tenant not allowed is recorded",
+			tenant: "globex",
+			setupMock: func(a *mocks.MockAuditRecorder) {
+				a.EXPECT().RecordAuditEvent(gomock.Any(), domain.AuditEvent{
+					Tenant:  "globex",
+					Subject: "api-key:reader",
+					Action:  domain.ActionAccessTenant,
+					Outcome: domain.AuditOutcomeDenied,
+					Details: "the credentials are bound to another tenant",
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   domain.ForbiddenCode,
+		},
+		{
+			name: "unrecorded denial fails the request",
+			setupMock: func(a *mocks.MockAuditRecorder) {
+				a.EXPECT().RecordAuditEvent(gomock.Any(), gomock.Any()).
+					Return(domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   domain.InternalCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			audit := mocks.NewMockAuditRecorder(ctrl)
+			tt.setupMock(audit)
+
+			deps := Dependencies{Authenticator: mocks.NewMockAuthenticator(ctrl), AuditRecorder: audit}
+
+			r := chi.NewRouter()
+			r.Use(tenantMiddleware(deps))
+			r.With(requireScope(deps, domain.ScopeMediaWrite, domain.ActionWriteMedia)).Post("/media", func(w http.ResponseWriter, r *http.Request) {
+				t.Error("the handler should not be called")
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/media", nil)
+			req = req.WithContext(domain.ContextWithPrincipal(req.Context(), reader))
+			if tt.tenant != "" {
+				req.Header.Set(TenantHeader, tt.tenant)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assertErrorCode(t, rec, tt.expectedCode)
+		})
+	}
+}
+
+func assertErrorCode(t *testing.T, rec *httptest.ResponseRecorder, code string) {
+	t.Helper()
+	var response errorResponse
+	if assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response)) {
+		assert.Equal(t, code, response.Error.Code)
+	}
+}
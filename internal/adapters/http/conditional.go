@@ -0,0 +1,103 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+// ifNoneMatchTags returns the entity tags listed in If-None-Match. Weak tags
+// are returned as strong ones, since If-None-Match uses the weak comparison.
+func ifNoneMatchTags(r *http.Request) []string {
+	var tags []string
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ifModifiedSince returns the If-Modified-Since date. It is only considered
+// when If-None-Match is absent, as the latter is the more accurate validator.
+func ifModifiedSince(r *http.Request) (time.Time, bool) {
+	if r.Header.Get("If-None-Match") != "" {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// cachedMediaFromRequest returns the media representation the client holds
+// according to the conditional headers, or nil when it holds none we know of.
+// Among several ETags, the one with the most recent download URL is used.
+func cachedMediaFromRequest(r *http.Request) *domain.CachedMedia {
+	var cached *domain.CachedMedia
+	for _, tag := range ifNoneMatchTags(r) {
+		c, ok := parseMediaEntityTag(tag)
+		if !ok || c.URLIssuedAt.IsZero() {
+			continue
+		}
+		if cached == nil || c.URLIssuedAt.After(cached.URLIssuedAt) {
+			cached = &c
+		}
+	}
+	if cached != nil {
+		return cached
+	}
+
+	// Last-Modified is when the URL was issued: the representation includes
+	// every metadata change up to then
+	if since, ok := ifModifiedSince(r); ok {
+		return &domain.CachedMedia{UpdatedAt: since, URLIssuedAt: since}
+	}
+
+	return nil
+}
+
+// tagNotModified tells whether the client already holds the current
+// representation of an entity last modified at updatedAt
+func tagNotModified(r *http.Request, updatedAt time.Time) bool {
+	for _, tag := range ifNoneMatchTags(r) {
+		if t, ok := parseEntityTag(tag); ok && t.Equal(updatedAt) {
+			return true
+		}
+	}
+
+	// Last-Modified has a precision of one second
+	if since, ok := ifModifiedSince(r); ok {
+		return !updatedAt.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// setMediaCacheHeaders sets the validators of a media representation. It may
+// be cached as long as its download URL remains usable; it is private as the
+// URL grants access to the file.
+func setMediaCacheHeaders(rw http.ResponseWriter, media domain.Media) {
+	rw.Header().Set("ETag", mediaEntityTag(media))
+	if media.URLIssuedAt.IsZero() {
+		return
+	}
+
+	maxAge := time.Until(media.URLExpiresAt) - domain.MinDownloadURLLifetime
+	maxAge = max(maxAge, 0)
+	rw.Header().Set("Last-Modified", media.URLIssuedAt.UTC().Format(http.TimeFormat))
+	rw.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int64(maxAge/time.Second)))
+}
+
+// setTagCacheHeaders sets the validators of a tag representation, which
+// clients must revalidate before reuse
+func setTagCacheHeaders(rw http.ResponseWriter, tag domain.Tag) {
+	rw.Header().Set("ETag", entityTag(tag.UpdatedAt))
+	rw.Header().Set("Last-Modified", tag.UpdatedAt.UTC().Format(http.TimeFormat))
+	rw.Header().Set("Cache-Control", "no-cache")
+}
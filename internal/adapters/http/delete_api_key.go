@@ -0,0 +1,36 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+)
+
+type APIKeyRevoker interface {
+	Execute(ctx context.Context, id uuid.UUID) (domain.APIKey, error)
+}
+
+// HandleDeleteAPIKey revokes an API key. The key is kept, flagged as revoked,
+// so that it still shows up when listing the keys.
+func HandleDeleteAPIKey(kr APIKeyRevoker) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		keyID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			errDetails := "Invalid UUID format"
+			respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
+				"Invalid API key ID", &errDetails, nil)
+			return
+		}
+
+		// Execute business logic
+		if _, err := kr.Execute(r.Context(), keyID); err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	}
+}
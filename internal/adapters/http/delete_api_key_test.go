@@ -0,0 +1,72 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_api_key_revoker.go -package=mocks github.com/peano88/medias/internal/adapters/http APIKeyRevoker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleDeleteAPIKey(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name         string
+		keyID        string
+		setupMock    func(*mocks.MockAPIKeyRevoker)
+		expectedCode int
+	}{
+		{
+			name:  "success",
+			keyID: id.String(),
+			setupMock: func(kr *mocks.MockAPIKeyRevoker) {
+				kr.EXPECT().Execute(gomock.Any(), id).Return(domain.APIKey{ID: id}, nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:  "not found",
+			keyID: id.String(),
+			setupMock: func(kr *mocks.MockAPIKeyRevoker) {
+				kr.EXPECT().Execute(gomock.Any(), id).
+					Return(domain.APIKey{}, domain.NewError(domain.NotFoundCode, domain.WithMessage("API key not found")))
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid UUID",
+			keyID:        "reader",
+			setupMock:    func(kr *mocks.MockAPIKeyRevoker) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			revoker := mocks.NewMockAPIKeyRevoker(ctrl)
+			tt.setupMock(revoker)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/api-keys/"+tt.keyID, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.keyID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			HandleDeleteAPIKey(revoker)(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RoleUnassigner interface {
+	Execute(ctx context.Context, subject, tenant string) error
+}
+
+// HandleDeleteRole removes the role of a principal in the tenant of the tenant
+// query parameter, in every tenant when it is missing
+func HandleDeleteRole(ru RoleUnassigner) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		subject := chi.URLParam(r, "subject")
+		tenant := r.URL.Query().Get("tenant")
+
+		// Execute business logic
+		if err := ru.Execute(r.Context(), subject, tenant); err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	}
+}
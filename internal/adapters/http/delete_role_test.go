@@ -0,0 +1,70 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_role_unassigner.go -package=mocks github.com/peano88/medias/internal/adapters/http RoleUnassigner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleDeleteRole(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		setupMock    func(*mocks.MockRoleUnassigner)
+		expectedCode int
+	}{
+		{
+			name: "success - role in every tenant",
+			setupMock: func(ru *mocks.MockRoleUnassigner) {
+				ru.EXPECT().Execute(gomock.Any(), "user-42", "").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:  "success - role in a tenant",
+			query: "?tenant=acme",
+			setupMock: func(ru *mocks.MockRoleUnassigner) {
+				ru.EXPECT().Execute(gomock.Any(), "user-42", "acme").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:  "not found",
+			query: "?tenant=acme",
+			setupMock: func(ru *mocks.MockRoleUnassigner) {
+				ru.EXPECT().Execute(gomock.Any(), "user-42", "acme").
+					Return(domain.NewError(domain.NotFoundCode, domain.WithMessage("role assignment not found")))
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			unassigner := mocks.NewMockRoleUnassigner(ctrl)
+			tt.setupMock(unassigner)
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/roles/user-42"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("subject", "user-42")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			HandleDeleteRole(unassigner)(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
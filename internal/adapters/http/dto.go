@@ -3,6 +3,7 @@ package http
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/peano88/medias/internal/domain"
 )
 
@@ -15,6 +16,14 @@ type createTagResponse struct {
 	Data tagData `json:"data"`
 }
 
+type tagResponse struct {
+	Data tagData `json:"data"`
+}
+
+type updateDescriptionRequest struct {
+	Description *string `json:"description"`
+}
+
 type getTagsResponse struct {
 	Data       []tagData          `json:"data"`
 	Pagination paginationMetadata `json:"pagination"`
@@ -34,6 +43,16 @@ type tagData struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type relatedTagsResponse struct {
+	Data []relatedTagData `json:"data"`
+}
+
+type relatedTagData struct {
+	tagData
+	Cooccurrences int     `json:"cooccurrences"`
+	Lift          float64 `json:"lift"`
+}
+
 type errorResponse struct {
 	Error errorDetails `json:"error"`
 }
@@ -44,6 +63,19 @@ type errorDetails struct {
 	Details   *string    `json:"details,omitempty"`
 	Timestamp *time.Time `json:"timestamp,omitempty"`
 	RequestID *string    `json:"request_id,omitempty"`
+	TraceID   *string    `json:"trace_id,omitempty"`
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks []healthCheckData `json:"checks,omitempty"`
+}
+
+type healthCheckData struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	DurationMS int64   `json:"duration_ms"`
+	Error      *string `json:"error,omitempty"`
 }
 
 type createMediaRequest struct {
@@ -53,6 +85,7 @@ type createMediaRequest struct {
 	Size        int64    `json:"size"`
 	SHA256      string   `json:"sha256"`
 	Tags        []string `json:"tags,omitempty"`
+	Visibility  string   `json:"visibility,omitempty"`
 }
 
 type createMediaResponse struct {
@@ -72,36 +105,218 @@ type mediaData struct {
 	Type        string    `json:"type"`
 	MimeType    string    `json:"mime_type"`
 	Size        int64     `json:"size"`
+	Owner       string    `json:"owner,omitempty"`
+	Visibility  string    `json:"visibility"`
 	Tags        []tagData `json:"tags"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// Statuses of the items of a batch response
+const (
+	batchItemStatusCreated   = "created"
+	batchItemStatusReissued  = "reissued"
+	batchItemStatusError     = "error"
+	batchItemStatusFinalized = "finalized"
+	batchItemStatusFailed    = "failed"
+)
+
+type finalizeMediaBatchRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+type mediaBatchResponse struct {
+	Data []mediaBatchItemResult `json:"data"`
+}
+
+type mediaBatchItemResult struct {
+	Index  int           `json:"index"`
+	Status string        `json:"status"`
+	Data   *mediaData    `json:"data,omitempty"`
+	Error  *errorDetails `json:"error,omitempty"`
+}
+
+type mediaListResponse struct {
+	Data       []mediaData        `json:"data"`
+	Pagination paginationMetadata `json:"pagination"`
+}
+
+type createAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	Tenant string   `json:"tenant,omitempty"`
+}
+
+type assignRoleRequest struct {
+	Role   string `json:"role"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+type roleAssignmentResponse struct {
+	Data roleAssignmentData `json:"data"`
+}
+
+type getRolesResponse struct {
+	Data       []roleAssignmentData `json:"data"`
+	Pagination paginationMetadata   `json:"pagination"`
+}
+
+type roleAssignmentData struct {
+	Subject string `json:"subject"`
+	Role    string `json:"role"`
+	// Tenant is empty for the roles held in every tenant
+	Tenant    string    `json:"tenant,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type apiKeyResponse struct {
+	Data apiKeyData `json:"data"`
+}
+
+type getAPIKeysResponse struct {
+	Data       []apiKeyData       `json:"data"`
+	Pagination paginationMetadata `json:"pagination"`
+}
+
+type apiKeyData struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	Tenant string   `json:"tenant"`
+	// Key is only returned on creation
+	Key       string     `json:"key,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
 func buildMediaResponse(media domain.Media) mediaResponse {
+	return mediaResponse{
+		Data: buildMediaData(media),
+	}
+}
+
+func buildMediaListResponse(result *domain.PaginatedResult[domain.Media]) mediaListResponse {
+	mediaDataList := make([]mediaData, len(result.Items))
+	for i, media := range result.Items {
+		mediaDataList[i] = buildMediaData(media)
+	}
+
+	return mediaListResponse{
+		Data: mediaDataList,
+		Pagination: paginationMetadata{
+			Limit:  result.Limit,
+			Offset: result.Offset,
+			Total:  result.Total,
+		},
+	}
+}
+
+func buildMediaBatchResponse(results []domain.MediaBatchResult) mediaBatchResponse {
+	items := make([]mediaBatchItemResult, len(results))
+	for i, result := range results {
+		items[i] = mediaBatchItemResult{Index: i}
+
+		if result.Err != nil {
+			details := buildErrorDetails(result.Err)
+			items[i].Status = batchItemStatusError
+			items[i].Error = &details
+			continue
+		}
+
+		data := buildMediaData(result.Media)
+		items[i].Data = &data
+		items[i].Status = batchItemStatusCreated
+		if result.Media.Operation == domain.MediaOperationUpdate {
+			items[i].Status = batchItemStatusReissued
+		}
+	}
+
+	return mediaBatchResponse{Data: items}
+}
+
+func buildFinalizeMediaBatchResponse(results []domain.MediaBatchResult) mediaBatchResponse {
+	items := make([]mediaBatchItemResult, len(results))
+	for i, result := range results {
+		items[i] = mediaBatchItemResult{Index: i, Status: batchItemStatusFinalized}
+
+		// A media returned along with its error was marked as failed
+		if result.Media.ID != uuid.Nil {
+			data := buildMediaData(result.Media)
+			items[i].Data = &data
+		}
+
+		if result.Err != nil {
+			details := buildErrorDetails(result.Err)
+			items[i].Error = &details
+			items[i].Status = batchItemStatusError
+			if items[i].Data != nil {
+				items[i].Status = batchItemStatusFailed
+			}
+		}
+	}
+
+	return mediaBatchResponse{Data: items}
+}
+
+func buildTagData(tag domain.Tag) tagData {
+	return tagData{
+		ID:          tag.ID.String(),
+		Name:        tag.Name,
+		Description: tag.Description,
+		CreatedAt:   tag.CreatedAt,
+		UpdatedAt:   tag.UpdatedAt,
+	}
+}
+
+func buildMediaData(media domain.Media) mediaData {
 	tagDataList := make([]tagData, len(media.Tags))
 	for i, tag := range media.Tags {
-		tagDataList[i] = tagData{
-			ID:          tag.ID.String(),
-			Name:        tag.Name,
-			Description: tag.Description,
-			CreatedAt:   tag.CreatedAt,
-			UpdatedAt:   tag.UpdatedAt,
-		}
+		tagDataList[i] = buildTagData(tag)
 	}
 
-	return mediaResponse{
-		Data: mediaData{
-			ID:          media.ID.String(),
-			Filename:    media.Filename,
-			Description: media.Description,
-			Status:      string(media.Status),
-			URL:         media.URL,
-			Type:        string(media.Type),
-			MimeType:    media.MimeType,
-			Size:        media.Size,
-			Tags:        tagDataList,
-			CreatedAt:   media.CreatedAt,
-			UpdatedAt:   media.UpdatedAt,
-		},
+	return mediaData{
+		ID:          media.ID.String(),
+		Filename:    media.Filename,
+		Description: media.Description,
+		Status:      string(media.Status),
+		URL:         media.URL,
+		Type:        string(media.Type),
+		MimeType:    media.MimeType,
+		Size:        media.Size,
+		Owner:       media.Owner,
+		Visibility:  string(media.Visibility),
+		Tags:        tagDataList,
+		CreatedAt:   media.CreatedAt,
+		UpdatedAt:   media.UpdatedAt,
+	}
+}
+
+func buildAPIKeyData(key domain.APIKey) apiKeyData {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return apiKeyData{
+		ID:        key.ID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    scopes,
+		Tenant:    key.Tenant,
+		Key:       key.Key,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+func buildRoleAssignmentData(assignment domain.RoleAssignment) roleAssignmentData {
+	return roleAssignmentData{
+		Subject:   assignment.Subject,
+		Role:      string(assignment.Role),
+		Tenant:    assignment.Tenant,
+		CreatedAt: assignment.CreatedAt,
+		UpdatedAt: assignment.UpdatedAt,
 	}
 }
@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"time"
 
+	chimdw "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/httplog/v3"
 	"github.com/peano88/medias/internal/domain"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func errorCodeToHTTPCode(code string) int {
@@ -21,6 +23,16 @@ func errorCodeToHTTPCode(code string) int {
 		return http.StatusConflict
 	case domain.NotFoundCode:
 		return http.StatusNotFound
+	case domain.PreconditionFailedCode:
+		return http.StatusPreconditionFailed
+	case domain.PreconditionRequiredCode:
+		return http.StatusPreconditionRequired
+	case domain.UnauthenticatedCode:
+		return http.StatusUnauthorized
+	case domain.ForbiddenCode:
+		return http.StatusForbidden
+	case domain.RateLimitedCode:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
@@ -31,16 +43,37 @@ func handleExecutorError(ctx context.Context, rw http.ResponseWriter, err error)
 	_ = httplog.SetError(ctx, err)
 	var domainErr *domain.Error
 	if errors.As(err, &domainErr) {
-		respondWithError(rw, errorCodeToHTTPCode(domainErr.Code), domainErr.Code,
+		respondWithError(ctx, rw, errorCodeToHTTPCode(domainErr.Code), domainErr.Code,
 			domainErr.Message, &domainErr.Details, &domainErr.Timestamp)
 		return
 	}
 
-	respondWithError(rw, http.StatusInternalServerError, domain.InternalCode,
+	respondWithError(ctx, rw, http.StatusInternalServerError, domain.InternalCode,
 		"An unexpected error occurred", nil, nil)
 }
 
-func respondWithError(rw http.ResponseWriter, statusCode int, code, message string, details *string, ts *time.Time) {
+// buildErrorDetails converts an error into the details reported to clients,
+// for responses which carry several errors such as batch operations
+func buildErrorDetails(err error) errorDetails {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return errorDetails{
+			Code:      domainErr.Code,
+			Message:   domainErr.Message,
+			Details:   &domainErr.Details,
+			Timestamp: &domainErr.Timestamp,
+		}
+	}
+
+	return errorDetails{
+		Code:    domain.InternalCode,
+		Message: "An unexpected error occurred",
+	}
+}
+
+// respondWithError writes the error, along with the ids of the request and of
+// its trace, if any, for the clients to report
+func respondWithError(ctx context.Context, rw http.ResponseWriter, statusCode int, code, message string, details *string, ts *time.Time) {
 
 	resp := errorResponse{
 		Error: errorDetails{
@@ -50,6 +83,13 @@ func respondWithError(rw http.ResponseWriter, statusCode int, code, message stri
 			Timestamp: ts,
 		},
 	}
+	if requestID := chimdw.GetReqID(ctx); requestID != "" {
+		resp.Error.RequestID = &requestID
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		traceID := spanContext.TraceID().String()
+		resp.Error.TraceID = &traceID
+	}
 
 	rw.Header().Set("Content-Type", "application/json")
 	rw.WriteHeader(statusCode)
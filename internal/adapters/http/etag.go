@@ -0,0 +1,82 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+// entityTag derives a strong ETag from the last modification of an entity
+func entityTag(updatedAt time.Time) string {
+	return fmt.Sprintf(`"%d"`, updatedAt.UnixNano())
+}
+
+// mediaEntityTag derives a strong ETag from the last modification of a media
+// and from when its download URL was issued, as the URL is part of the
+// representation
+func mediaEntityTag(media domain.Media) string {
+	if media.URLIssuedAt.IsZero() {
+		return entityTag(media.UpdatedAt)
+	}
+	return fmt.Sprintf(`"%d.%d"`, media.UpdatedAt.UnixNano(), media.URLIssuedAt.Unix())
+}
+
+// parseEntityTag returns the modification time an ETag was derived from.
+// Weak and foreign tags are rejected, If-Match requires a strong comparison.
+func parseEntityTag(tag string) (time.Time, bool) {
+	cached, ok := parseMediaEntityTag(tag)
+	return cached.UpdatedAt, ok
+}
+
+// parseMediaEntityTag returns the modification time and, when present, the
+// URL issue time a strong ETag was derived from
+func parseMediaEntityTag(tag string) (domain.CachedMedia, bool) {
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return domain.CachedMedia{}, false
+	}
+
+	updatedAtPart, issuedAtPart, hasIssuedAt := strings.Cut(tag[1:len(tag)-1], ".")
+	nanos, err := strconv.ParseInt(updatedAtPart, 10, 64)
+	if err != nil {
+		return domain.CachedMedia{}, false
+	}
+
+	cached := domain.CachedMedia{UpdatedAt: time.Unix(0, nanos).UTC()}
+	if hasIssuedAt {
+		secs, err := strconv.ParseInt(issuedAtPart, 10, 64)
+		if err != nil {
+			return domain.CachedMedia{}, false
+		}
+		cached.URLIssuedAt = time.Unix(secs, 0).UTC()
+	}
+
+	return cached, true
+}
+
+// requireIfMatch extracts the version the client based its update on from the
+// If-Match header. It responds with 428 when the header is missing and with
+// 412 when it does not carry an ETag issued by this service, as it cannot match.
+func requireIfMatch(rw http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		errDetails := "If-Match must carry the ETag of the resource to update"
+		respondWithError(r.Context(), rw, http.StatusPreconditionRequired, domain.PreconditionRequiredCode,
+			"Missing If-Match header", &errDetails, nil)
+		return time.Time{}, false
+	}
+
+	expectedUpdatedAt, ok := parseEntityTag(ifMatch)
+	if !ok {
+		errDetails := "If-Match does not match the current ETag of the resource"
+		respondWithError(r.Context(), rw, http.StatusPreconditionFailed, domain.PreconditionFailedCode,
+			"Precondition failed", &errDetails, nil)
+		return time.Time{}, false
+	}
+
+	return expectedUpdatedAt, true
+}
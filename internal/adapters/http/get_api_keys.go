@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+type APIKeyLister interface {
+	Execute(context.Context, domain.PaginationParams) (*domain.PaginatedResult[domain.APIKey], error)
+}
+
+func HandleGetAPIKeys(kl APIKeyLister) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// Parse pagination parameters from query string
+		params := domain.PaginationParams{
+			Limit:  parseIntQueryParam(r, "limit", 0),
+			Offset: parseIntQueryParam(r, "offset", 0),
+		}
+
+		// Execute business logic
+		result, err := kl.Execute(r.Context(), params)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		keys := make([]apiKeyData, len(result.Items))
+		for i, key := range result.Items {
+			keys[i] = buildAPIKeyData(key)
+		}
+
+		JSONOut(rw, http.StatusOK, getAPIKeysResponse{
+			Data: keys,
+			Pagination: paginationMetadata{
+				Limit:  result.Limit,
+				Offset: result.Offset,
+				Total:  result.Total,
+			},
+		})
+	}
+}
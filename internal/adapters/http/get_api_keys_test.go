@@ -0,0 +1,56 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_api_key_lister.go -package=mocks github.com/peano88/medias/internal/adapters/http APIKeyLister
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleGetAPIKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revokedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	lister := mocks.NewMockAPIKeyLister(ctrl)
+	lister.EXPECT().
+		Execute(gomock.Any(), domain.PaginationParams{Limit: 10, Offset: 5}).
+		Return(&domain.PaginatedResult[domain.APIKey]{
+			Items: []domain.APIKey{{
+				ID:        uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+				Name:      "reader",
+				Prefix:    "mk_abcdefghi",
+				Hash:      "secret hash",
+				Scopes:    []domain.Scope{domain.ScopeMediaRead},
+				RevokedAt: &revokedAt,
+			}},
+			Total:  6,
+			Limit:  10,
+			Offset: 5,
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/api-keys?limit=10&offset=5", nil)
+	rec := httptest.NewRecorder()
+
+	HandleGetAPIKeys(lister)(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "secret hash")
+
+	var response getAPIKeysResponse
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, 6, response.Pagination.Total)
+	if assert.Len(t, response.Data, 1) {
+		assert.Empty(t, response.Data[0].Key)
+		assert.Equal(t, &revokedAt, response.Data[0].RevokedAt)
+	}
+}
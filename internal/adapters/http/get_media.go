@@ -10,7 +10,7 @@ import (
 )
 
 type MediaRetriever interface {
-	Execute(ctx context.Context, id uuid.UUID) (domain.Media, error)
+	Execute(ctx context.Context, id uuid.UUID, cached *domain.CachedMedia) (domain.Media, bool, error)
 }
 
 func HandleGetMedia(mr MediaRetriever) func(http.ResponseWriter, *http.Request) {
@@ -18,7 +18,7 @@ func HandleGetMedia(mr MediaRetriever) func(http.ResponseWriter, *http.Request)
 		// Extract media ID from URL path
 		mediaIDStr := chi.URLParam(r, "id")
 		if mediaIDStr == "" {
-			respondWithError(rw, http.StatusBadRequest, "INVALID_REQUEST",
+			respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
 				"Media ID is required", nil, nil)
 			return
 		}
@@ -27,18 +27,24 @@ func HandleGetMedia(mr MediaRetriever) func(http.ResponseWriter, *http.Request)
 		mediaID, err := uuid.Parse(mediaIDStr)
 		if err != nil {
 			errDetails := "Invalid UUID format"
-			respondWithError(rw, http.StatusBadRequest, "INVALID_REQUEST",
+			respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
 				"Invalid media ID", &errDetails, nil)
 			return
 		}
 
 		// Execute business logic
-		media, err := mr.Execute(r.Context(), mediaID)
+		media, notModified, err := mr.Execute(r.Context(), mediaID, cachedMediaFromRequest(r))
 		if err != nil {
 			handleExecutorError(r.Context(), rw, err)
 			return
 		}
 
+		setMediaCacheHeaders(rw, media)
+		if notModified {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
 		// Build response
 		resp := buildMediaResponse(media)
 		JSONOut(rw, http.StatusOK, resp)
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+type MediaLister interface {
+	Execute(context.Context, domain.MediaFilter, domain.PaginationParams) (*domain.PaginatedResult[domain.Media], error)
+}
+
+func HandleGetMediaList(ml MediaLister) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		filter, err := parseMediaFilter(r)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		// Parse pagination parameters from query string
+		params := domain.PaginationParams{
+			Limit:  parseIntQueryParam(r, "limit", 0),
+			Offset: parseIntQueryParam(r, "offset", 0),
+		}
+
+		// Execute business logic
+		result, err := ml.Execute(r.Context(), filter, params)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		JSONOut(rw, http.StatusOK, buildMediaListResponse(result))
+	}
+}
+
+// parseMediaFilter extracts the media filter from the query string.
+// Tags are provided as a comma separated list, while tags_query holds a
+// boolean tag expression whose syntax errors are reported as domain errors.
+func parseMediaFilter(r *http.Request) (domain.MediaFilter, error) {
+	filter := domain.MediaFilter{
+		Status: domain.MediaStatus(r.URL.Query().Get("status")),
+		Type:   domain.MediaType(r.URL.Query().Get("type")),
+	}
+
+	if tags := r.URL.Query().Get("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+
+	if tagsQuery := r.URL.Query().Get("tags_query"); tagsQuery != "" {
+		expr, err := domain.ParseTagQuery(tagsQuery)
+		if err != nil {
+			return domain.MediaFilter{}, err
+		}
+		filter.TagsQuery = expr
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,137 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_media_lister.go -package=mocks github.com/peano88/medias/internal/adapters/http MediaLister
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleGetMediaList(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		setupMock func(*mocks.MockMediaLister)
+		validate  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success with default pagination",
+			url:  "/api/v1/media",
+			setupMock: func(ml *mocks.MockMediaLister) {
+				result := &domain.PaginatedResult[domain.Media]{
+					Items: []domain.Media{
+						{
+							ID:        uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+							Filename:  "world-cup-final.jpg",
+							Status:    domain.MediaStatusFinalized,
+							Type:      domain.MediaTypeImage,
+							MimeType:  "image/jpeg",
+							Size:      2048000,
+							Tags:      []domain.Tag{},
+							CreatedAt: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC),
+							UpdatedAt: time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC),
+						},
+					},
+					Total:  1,
+					Limit:  domain.DefaultLimit,
+					Offset: 0,
+				}
+				ml.EXPECT().
+					Execute(gomock.Any(), domain.MediaFilter{}, domain.PaginationParams{}).
+					Return(result, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+
+				var response mediaListResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Len(t, response.Data, 1)
+				assert.Equal(t, 1, response.Pagination.Total)
+			},
+		},
+		{
+			name: "success - parses tags query",
+			url:  "/api/v1/media?status=finalized&tags_query=" + url.QueryEscape("(beach OR sea) AND NOT night"),
+			setupMock: func(ml *mocks.MockMediaLister) {
+				expectedFilter := domain.MediaFilter{
+					Status: domain.MediaStatusFinalized,
+					TagsQuery: domain.TagExprAnd{
+						Left: domain.TagExprOr{
+							Left:  domain.TagExprName{Name: "beach"},
+							Right: domain.TagExprName{Name: "sea"},
+						},
+						Right: domain.TagExprNot{Expr: domain.TagExprName{Name: "night"}},
+					},
+				}
+				ml.EXPECT().
+					Execute(gomock.Any(), expectedFilter, domain.PaginationParams{}).
+					Return(&domain.PaginatedResult[domain.Media]{Items: []domain.Media{}, Limit: domain.DefaultLimit}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name: "error - tags query syntax error",
+			url:  "/api/v1/media?tags_query=" + url.QueryEscape("beach AND (sea"),
+			setupMock: func(ml *mocks.MockMediaLister) {
+				// No mock setup - should fail before calling use case
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.InvalidEntityCode, response.Error.Code)
+				if assert.NotNil(t, response.Error.Details) {
+					assert.Contains(t, *response.Error.Details, "position 15")
+				}
+			},
+		},
+		{
+			name: "error - invalid filter",
+			url:  "/api/v1/media?type=audio",
+			setupMock: func(ml *mocks.MockMediaLister) {
+				ml.EXPECT().
+					Execute(gomock.Any(), domain.MediaFilter{Type: "audio"}, domain.PaginationParams{}).
+					Return(nil, domain.NewError(domain.InvalidEntityCode,
+						domain.WithMessage("invalid type filter"),
+					))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockML := mocks.NewMockMediaLister(ctrl)
+			tt.setupMock(mockML)
+
+			handler := HandleGetMediaList(mockML)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+type MediaSearcher interface {
+	Execute(context.Context, string, domain.MediaFilter, domain.PaginationParams) (*domain.PaginatedResult[domain.Media], error)
+}
+
+func HandleSearchMedia(ms MediaSearcher) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+
+		filter, err := parseMediaFilter(r)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		// Parse pagination parameters from query string
+		params := domain.PaginationParams{
+			Limit:  parseIntQueryParam(r, "limit", 0),
+			Offset: parseIntQueryParam(r, "offset", 0),
+		}
+
+		// Execute business logic
+		result, err := ms.Execute(r.Context(), query, filter, params)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		JSONOut(rw, http.StatusOK, buildMediaListResponse(result))
+	}
+}
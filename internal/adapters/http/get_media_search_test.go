@@ -0,0 +1,150 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_media_searcher.go -package=mocks github.com/peano88/medias/internal/adapters/http MediaSearcher
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleSearchMedia(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		setupMock func(*mocks.MockMediaSearcher)
+		validate  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success - returns ranked media",
+			url:  "/api/v1/media/search?q=goal",
+			setupMock: func(ms *mocks.MockMediaSearcher) {
+				result := &domain.PaginatedResult[domain.Media]{
+					Items: []domain.Media{
+						{
+							ID:        uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+							Filename:  "world-cup-final.jpg",
+							Status:    domain.MediaStatusFinalized,
+							URL:       "https://s3.example.com/bucket/w0rldcup2023/world-cup-final.jpg?X-Amz-Signature=...",
+							Type:      domain.MediaTypeImage,
+							MimeType:  "image/jpeg",
+							Size:      2048000,
+							Tags:      []domain.Tag{},
+							CreatedAt: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC),
+							UpdatedAt: time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC),
+						},
+					},
+					Total:  1,
+					Limit:  domain.DefaultLimit,
+					Offset: 0,
+				}
+				ms.EXPECT().
+					Execute(gomock.Any(), "goal", domain.MediaFilter{}, domain.PaginationParams{}).
+					Return(result, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+				var response mediaListResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Len(t, response.Data, 1)
+				assert.Equal(t, "world-cup-final.jpg", response.Data[0].Filename)
+				assert.Equal(t, 1, response.Pagination.Total)
+				assert.Equal(t, domain.DefaultLimit, response.Pagination.Limit)
+			},
+		},
+		{
+			name: "success - forwards filters and pagination",
+			url:  "/api/v1/media/search?q=final+match&status=finalized&type=image&tags=soccer,football&limit=10&offset=5",
+			setupMock: func(ms *mocks.MockMediaSearcher) {
+				expectedFilter := domain.MediaFilter{
+					Status: domain.MediaStatusFinalized,
+					Type:   domain.MediaTypeImage,
+					Tags:   []string{"soccer", "football"},
+				}
+				ms.EXPECT().
+					Execute(gomock.Any(), "final match", expectedFilter, domain.PaginationParams{Limit: 10, Offset: 5}).
+					Return(&domain.PaginatedResult[domain.Media]{Items: []domain.Media{}, Limit: 10, Offset: 5}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+
+				var response mediaListResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.NotNil(t, response.Data)
+				assert.Len(t, response.Data, 0)
+				assert.Equal(t, 10, response.Pagination.Limit)
+				assert.Equal(t, 5, response.Pagination.Offset)
+			},
+		},
+		{
+			name: "error - invalid query",
+			url:  "/api/v1/media/search",
+			setupMock: func(ms *mocks.MockMediaSearcher) {
+				ms.EXPECT().
+					Execute(gomock.Any(), "", domain.MediaFilter{}, domain.PaginationParams{}).
+					Return(nil, domain.NewError(domain.InvalidEntityCode,
+						domain.WithMessage("invalid search query"),
+						domain.WithDetails("query cannot be empty"),
+					))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.InvalidEntityCode, response.Error.Code)
+			},
+		},
+		{
+			name: "error - internal error",
+			url:  "/api/v1/media/search?q=goal",
+			setupMock: func(ms *mocks.MockMediaSearcher) {
+				ms.EXPECT().
+					Execute(gomock.Any(), "goal", domain.MediaFilter{}, domain.PaginationParams{}).
+					Return(nil, domain.NewError(domain.InternalCode,
+						domain.WithMessage("failed to search media"),
+					))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.InternalCode, response.Error.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMS := mocks.NewMockMediaSearcher(ctrl)
+			tt.setupMock(mockMS)
+
+			handler := HandleSearchMedia(mockMS)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
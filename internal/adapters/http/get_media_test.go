@@ -5,6 +5,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,9 +20,21 @@ import (
 )
 
 func TestHandleGetMedia(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC)
+	issuedAt := time.Now().UTC().Truncate(time.Second).Add(-10 * time.Minute)
+	cachedMedia := domain.Media{
+		ID:           uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Filename:     "world-cup-final.jpg",
+		Status:       domain.MediaStatusFinalized,
+		UpdatedAt:    updatedAt,
+		URLIssuedAt:  issuedAt,
+		URLExpiresAt: issuedAt.Add(time.Hour),
+	}
+
 	tests := []struct {
 		name      string
 		mediaID   string
+		headers   map[string]string
 		setupMock func(*mocks.MockMediaRetriever)
 		validate  func(*testing.T, *httptest.ResponseRecorder)
 	}{
@@ -46,16 +59,21 @@ func TestHandleGetMedia(t *testing.T) {
 							UpdatedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
 						},
 					},
-					CreatedAt: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC),
-					UpdatedAt: time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC),
+					CreatedAt:    time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC),
+					UpdatedAt:    updatedAt,
+					URLIssuedAt:  issuedAt,
+					URLExpiresAt: issuedAt.Add(time.Hour),
 				}
 				mr.EXPECT().
-					Execute(gomock.Any(), uuid.MustParse("11111111-1111-1111-1111-111111111111")).
-					Return(media, nil)
+					Execute(gomock.Any(), uuid.MustParse("11111111-1111-1111-1111-111111111111"), nil).
+					Return(media, false, nil)
 			},
 			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, rec.Code)
 				assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+				assert.Equal(t, fmt.Sprintf(`"%d.%d"`, updatedAt.UnixNano(), issuedAt.Unix()), rec.Header().Get("ETag"))
+				assert.Equal(t, issuedAt.Format(http.TimeFormat), rec.Header().Get("Last-Modified"))
+				assertMaxAge(t, rec, 50*time.Minute-domain.MinDownloadURLLifetime)
 
 				var response mediaResponse
 				err := json.NewDecoder(rec.Body).Decode(&response)
@@ -89,8 +107,8 @@ func TestHandleGetMedia(t *testing.T) {
 			mediaID: "22222222-2222-2222-2222-222222222222",
 			setupMock: func(mr *mocks.MockMediaRetriever) {
 				mr.EXPECT().
-					Execute(gomock.Any(), uuid.MustParse("22222222-2222-2222-2222-222222222222")).
-					Return(domain.Media{}, domain.NewError(domain.NotFoundCode,
+					Execute(gomock.Any(), uuid.MustParse("22222222-2222-2222-2222-222222222222"), nil).
+					Return(domain.Media{}, false, domain.NewError(domain.NotFoundCode,
 						domain.WithMessage("media not found"),
 					))
 			},
@@ -105,6 +123,54 @@ func TestHandleGetMedia(t *testing.T) {
 				assert.Contains(t, response.Error.Message, "media not found")
 			},
 		},
+		{
+			name:    "not modified - If-None-Match",
+			mediaID: "11111111-1111-1111-1111-111111111111",
+			headers: map[string]string{
+				"If-None-Match": fmt.Sprintf(`W/"foreign", "%d.%d"`, updatedAt.UnixNano(), issuedAt.Unix()),
+			},
+			setupMock: func(mr *mocks.MockMediaRetriever) {
+				mr.EXPECT().
+					Execute(gomock.Any(), cachedMedia.ID, &domain.CachedMedia{UpdatedAt: updatedAt, URLIssuedAt: issuedAt}).
+					Return(cachedMedia, true, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotModified, rec.Code)
+				assert.Empty(t, rec.Body.String())
+				assert.Equal(t, fmt.Sprintf(`"%d.%d"`, updatedAt.UnixNano(), issuedAt.Unix()), rec.Header().Get("ETag"))
+				assertMaxAge(t, rec, 50*time.Minute-domain.MinDownloadURLLifetime)
+			},
+		},
+		{
+			name:    "not modified - If-Modified-Since",
+			mediaID: "11111111-1111-1111-1111-111111111111",
+			headers: map[string]string{"If-Modified-Since": issuedAt.Format(http.TimeFormat)},
+			setupMock: func(mr *mocks.MockMediaRetriever) {
+				mr.EXPECT().
+					Execute(gomock.Any(), cachedMedia.ID, &domain.CachedMedia{UpdatedAt: issuedAt, URLIssuedAt: issuedAt}).
+					Return(cachedMedia, true, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotModified, rec.Code)
+				assert.Equal(t, issuedAt.Format(http.TimeFormat), rec.Header().Get("Last-Modified"))
+			},
+		},
+		{
+			name:    "If-Modified-Since ignored along with If-None-Match",
+			mediaID: "11111111-1111-1111-1111-111111111111",
+			headers: map[string]string{
+				"If-None-Match":     fmt.Sprintf(`"%d"`, updatedAt.UnixNano()),
+				"If-Modified-Since": issuedAt.Format(http.TimeFormat),
+			},
+			setupMock: func(mr *mocks.MockMediaRetriever) {
+				mr.EXPECT().
+					Execute(gomock.Any(), cachedMedia.ID, nil).
+					Return(cachedMedia, false, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -118,6 +184,9 @@ func TestHandleGetMedia(t *testing.T) {
 			handler := HandleGetMedia(mockRetriever)
 
 			req := httptest.NewRequest(http.MethodGet, "/media/"+tt.mediaID, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
 			rec := httptest.NewRecorder()
 
 			// Setup chi URL params
@@ -131,3 +200,13 @@ func TestHandleGetMedia(t *testing.T) {
 		})
 	}
 }
+
+// assertMaxAge checks the Cache-Control max-age, allowing for the time the test takes
+func assertMaxAge(t *testing.T, rec *httptest.ResponseRecorder, expected time.Duration) {
+	t.Helper()
+	var maxAge int64
+	_, err := fmt.Sscanf(rec.Header().Get("Cache-Control"), "private, max-age=%d", &maxAge)
+	if assert.NoError(t, err) {
+		assert.InDelta(t, expected.Seconds(), float64(maxAge), 5)
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/peano88/medias/internal/domain"
+)
+
+type RelatedTagRetriever interface {
+	Execute(context.Context, string, int) ([]domain.RelatedTag, error)
+}
+
+func HandleGetRelatedTags(rtr RelatedTagRetriever) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		if name == "" {
+			respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
+				"Tag name is required", nil, nil)
+			return
+		}
+
+		// Execute business logic
+		related, err := rtr.Execute(r.Context(), name, parseIntQueryParam(r, "limit", 0))
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		relatedDataList := make([]relatedTagData, len(related))
+		for i, rt := range related {
+			relatedDataList[i] = relatedTagData{
+				tagData: tagData{
+					ID:          rt.Tag.ID.String(),
+					Name:        rt.Tag.Name,
+					Description: rt.Tag.Description,
+					CreatedAt:   rt.Tag.CreatedAt,
+					UpdatedAt:   rt.Tag.UpdatedAt,
+				},
+				Cooccurrences: rt.Cooccurrences,
+				Lift:          rt.Lift,
+			}
+		}
+
+		JSONOut(rw, http.StatusOK, relatedTagsResponse{Data: relatedDataList})
+	}
+}
@@ -0,0 +1,124 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_related_tag_retriever.go -package=mocks github.com/peano88/medias/internal/adapters/http RelatedTagRetriever
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleGetRelatedTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		tagName   string
+		url       string
+		setupMock func(*mocks.MockRelatedTagRetriever)
+		validate  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "success - returns related tags with scores",
+			tagName: "soccer",
+			url:     "/api/v1/tags/soccer/related?limit=5",
+			setupMock: func(rtr *mocks.MockRelatedTagRetriever) {
+				related := []domain.RelatedTag{
+					{
+						Tag: domain.Tag{
+							ID:        uuid.MustParse("323e4567-e89b-12d3-a456-426614174000"),
+							Name:      "football",
+							CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+							UpdatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+						},
+						Cooccurrences: 42,
+						Lift:          3.5,
+					},
+				}
+				rtr.EXPECT().
+					Execute(gomock.Any(), "soccer", 5).
+					Return(related, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+				var response relatedTagsResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				if assert.Len(t, response.Data, 1) {
+					assert.Equal(t, "football", response.Data[0].Name)
+					assert.Equal(t, 42, response.Data[0].Cooccurrences)
+					assert.Equal(t, 3.5, response.Data[0].Lift)
+				}
+			},
+		},
+		{
+			name:    "error - tag not found",
+			tagName: "curling",
+			url:     "/api/v1/tags/curling/related",
+			setupMock: func(rtr *mocks.MockRelatedTagRetriever) {
+				rtr.EXPECT().
+					Execute(gomock.Any(), "curling", 0).
+					Return(nil, domain.NewError(domain.NotFoundCode,
+						domain.WithMessage("tag not found"),
+					))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.NotFoundCode, response.Error.Code)
+			},
+		},
+		{
+			name:    "error - invalid limit",
+			tagName: "soccer",
+			url:     "/api/v1/tags/soccer/related?limit=1000",
+			setupMock: func(rtr *mocks.MockRelatedTagRetriever) {
+				rtr.EXPECT().
+					Execute(gomock.Any(), "soccer", 1000).
+					Return(nil, domain.NewError(domain.InvalidEntityCode,
+						domain.WithMessage("invalid limit"),
+					))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRTR := mocks.NewMockRelatedTagRetriever(ctrl)
+			tt.setupMock(mockRTR)
+
+			handler := HandleGetRelatedTags(mockRTR)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rec := httptest.NewRecorder()
+
+			// Setup chi URL params
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("name", tt.tagName)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			handler(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+type RoleLister interface {
+	Execute(context.Context, domain.PaginationParams) (*domain.PaginatedResult[domain.RoleAssignment], error)
+}
+
+func HandleGetRoles(rl RoleLister) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// Parse pagination parameters from query string
+		params := domain.PaginationParams{
+			Limit:  parseIntQueryParam(r, "limit", 0),
+			Offset: parseIntQueryParam(r, "offset", 0),
+		}
+
+		// Execute business logic
+		result, err := rl.Execute(r.Context(), params)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		assignments := make([]roleAssignmentData, len(result.Items))
+		for i, assignment := range result.Items {
+			assignments[i] = buildRoleAssignmentData(assignment)
+		}
+
+		JSONOut(rw, http.StatusOK, getRolesResponse{
+			Data: assignments,
+			Pagination: paginationMetadata{
+				Limit:  result.Limit,
+				Offset: result.Offset,
+				Total:  result.Total,
+			},
+		})
+	}
+}
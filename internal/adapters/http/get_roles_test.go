@@ -0,0 +1,48 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_role_lister.go -package=mocks github.com/peano88/medias/internal/adapters/http RoleLister
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleGetRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lister := mocks.NewMockRoleLister(ctrl)
+	lister.EXPECT().
+		Execute(gomock.Any(), domain.PaginationParams{Limit: 10, Offset: 5}).
+		Return(&domain.PaginatedResult[domain.RoleAssignment]{
+			Items: []domain.RoleAssignment{
+				{Subject: "user-42", Role: domain.RoleEditor},
+				{Subject: "user-42", Role: domain.RoleViewer, Tenant: "acme"},
+			},
+			Total:  7,
+			Limit:  10,
+			Offset: 5,
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/roles?limit=10&offset=5", nil)
+	rec := httptest.NewRecorder()
+
+	HandleGetRoles(lister)(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response getRolesResponse
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, 7, response.Pagination.Total)
+	if assert.Len(t, response.Data, 2) {
+		assert.Empty(t, response.Data[0].Tenant)
+		assert.Equal(t, "acme", response.Data[1].Tenant)
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+)
+
+type SingleTagRetriever interface {
+	Execute(ctx context.Context, id uuid.UUID) (domain.Tag, error)
+}
+
+func HandleGetTag(tr SingleTagRetriever) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// Parse UUID from URL path
+		tagID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			errDetails := "Invalid UUID format"
+			respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
+				"Invalid tag ID", &errDetails, nil)
+			return
+		}
+
+		// Execute business logic
+		tag, err := tr.Execute(r.Context(), tagID)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		setTagCacheHeaders(rw, tag)
+		if tagNotModified(r, tag.UpdatedAt) {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		JSONOut(rw, http.StatusOK, tagResponse{Data: buildTagData(tag)})
+	}
+}
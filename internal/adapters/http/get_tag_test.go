@@ -0,0 +1,157 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_single_tag_retriever.go -package=mocks github.com/peano88/medias/internal/adapters/http SingleTagRetriever
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleGetTag(t *testing.T) {
+	updatedAt := time.Date(2023, 1, 1, 12, 0, 0, 123456000, time.UTC)
+
+	tests := []struct {
+		name      string
+		tagID     string
+		headers   map[string]string
+		setupMock func(*mocks.MockSingleTagRetriever)
+		validate  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success - returns tag with ETag",
+			tagID: "123e4567-e89b-12d3-a456-426614174000",
+			setupMock: func(tr *mocks.MockSingleTagRetriever) {
+				tr.EXPECT().
+					Execute(gomock.Any(), uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")).
+					Return(domain.Tag{
+						ID:        uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+						Name:      "soccer",
+						CreatedAt: updatedAt,
+						UpdatedAt: updatedAt,
+					}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, entityTag(updatedAt), rec.Header().Get("ETag"))
+				assert.Equal(t, "Sun, 01 Jan 2023 12:00:00 GMT", rec.Header().Get("Last-Modified"))
+				assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+
+				var response tagResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, "soccer", response.Data.Name)
+			},
+		},
+		{
+			name:    "not modified - If-None-Match",
+			tagID:   "123e4567-e89b-12d3-a456-426614174000",
+			headers: map[string]string{"If-None-Match": "W/" + entityTag(updatedAt)},
+			setupMock: func(tr *mocks.MockSingleTagRetriever) {
+				tr.EXPECT().
+					Execute(gomock.Any(), gomock.Any()).
+					Return(domain.Tag{Name: "soccer", UpdatedAt: updatedAt}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotModified, rec.Code)
+				assert.Empty(t, rec.Body.String())
+				assert.Equal(t, entityTag(updatedAt), rec.Header().Get("ETag"))
+			},
+		},
+		{
+			name:    "modified - If-None-Match with an older ETag",
+			tagID:   "123e4567-e89b-12d3-a456-426614174000",
+			headers: map[string]string{"If-None-Match": entityTag(updatedAt.Add(-time.Second))},
+			setupMock: func(tr *mocks.MockSingleTagRetriever) {
+				tr.EXPECT().
+					Execute(gomock.Any(), gomock.Any()).
+					Return(domain.Tag{Name: "soccer", UpdatedAt: updatedAt}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name:    "not modified - If-Modified-Since",
+			tagID:   "123e4567-e89b-12d3-a456-426614174000",
+			headers: map[string]string{"If-Modified-Since": "Sun, 01 Jan 2023 12:00:00 GMT"},
+			setupMock: func(tr *mocks.MockSingleTagRetriever) {
+				tr.EXPECT().
+					Execute(gomock.Any(), gomock.Any()).
+					Return(domain.Tag{Name: "soccer", UpdatedAt: updatedAt}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotModified, rec.Code)
+			},
+		},
+		{
+			name:    "modified - If-Modified-Since before the last update",
+			tagID:   "123e4567-e89b-12d3-a456-426614174000",
+			headers: map[string]string{"If-Modified-Since": "Sun, 01 Jan 2023 11:59:59 GMT"},
+			setupMock: func(tr *mocks.MockSingleTagRetriever) {
+				tr.EXPECT().
+					Execute(gomock.Any(), gomock.Any()).
+					Return(domain.Tag{Name: "soccer", UpdatedAt: updatedAt}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name:      "invalid UUID",
+			tagID:     "soccer",
+			setupMock: func(tr *mocks.MockSingleTagRetriever) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:  "not found",
+			tagID: "123e4567-e89b-12d3-a456-426614174000",
+			setupMock: func(tr *mocks.MockSingleTagRetriever) {
+				tr.EXPECT().
+					Execute(gomock.Any(), gomock.Any()).
+					Return(domain.Tag{}, domain.NewError(domain.NotFoundCode, domain.WithMessage("tag not found")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+				assert.Empty(t, rec.Header().Get("ETag"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTR := mocks.NewMockSingleTagRetriever(ctrl)
+			tt.setupMock(mockTR)
+
+			handler := HandleGetTag(mockTR)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tags/"+tt.tagID, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.tagID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	healthStatusUp   = "up"
+	healthStatusDown = "down"
+)
+
+// DefaultHealthCheckTimeout bounds each readiness check when no timeout is
+// configured
+const DefaultHealthCheckTimeout = 2 * time.Second
+
+// HealthChecker checks a dependency the service needs to serve requests.
+// CheckHealth returns nil when the dependency is usable; the message of the
+// error is reported to the unauthenticated callers of the readiness probe, it
+// must not expose internal details.
+type HealthChecker interface {
+	Name() string
+	CheckHealth(ctx context.Context) error
+}
+
+// HandleHealthLive reports the process is up, without checking any dependency:
+// restarting the service would not fix an unreachable database
+func HandleHealthLive() func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		JSONOut(rw, http.StatusOK, healthResponse{Status: healthStatusUp})
+	}
+}
+
+// HandleHealthReady runs the checkers in parallel, each bounded by timeout,
+// and reports 503 unless they all succeed
+func HandleHealthReady(checkers []HealthChecker, timeout time.Duration) func(http.ResponseWriter, *http.Request) {
+	if timeout <= 0 {
+		timeout = DefaultHealthCheckTimeout
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request) {
+		checks := make([]healthCheckData, len(checkers))
+
+		var wg sync.WaitGroup
+		for i, checker := range checkers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				checks[i] = runHealthCheck(r.Context(), checker, timeout)
+			}()
+		}
+		wg.Wait()
+
+		resp := healthResponse{Status: healthStatusUp, Checks: checks}
+		statusCode := http.StatusOK
+		for _, check := range checks {
+			if check.Status != healthStatusUp {
+				resp.Status = healthStatusDown
+				statusCode = http.StatusServiceUnavailable
+			}
+		}
+
+		JSONOut(rw, statusCode, resp)
+	}
+}
+
+func runHealthCheck(ctx context.Context, checker HealthChecker, timeout time.Duration) healthCheckData {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	before := time.Now()
+	err := checker.CheckHealth(ctx)
+	check := healthCheckData{
+		Name:       checker.Name(),
+		Status:     healthStatusUp,
+		DurationMS: time.Since(before).Milliseconds(),
+	}
+	if err != nil {
+		message := err.Error()
+		if ctx.Err() == context.DeadlineExceeded {
+			message = "check timed out"
+		}
+		check.Status = healthStatusDown
+		check.Error = &message
+	}
+	return check
+}
@@ -0,0 +1,107 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_health_checker.go -package=mocks github.com/peano88/medias/internal/adapters/http HealthChecker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandleHealthLive(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HandleHealthLive()(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"up"}`, rec.Body.String())
+}
+
+func TestHandleHealthReady(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.MockHealthChecker, *mocks.MockHealthChecker)
+		expectedStatus int
+		validate       func(*testing.T, healthResponse)
+	}{
+		{
+			name: "all checks up",
+			setupMocks: func(db, storage *mocks.MockHealthChecker) {
+				db.EXPECT().CheckHealth(gomock.Any()).Return(nil)
+				storage.EXPECT().CheckHealth(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			validate: func(t *testing.T, resp healthResponse) {
+				assert.Equal(t, healthStatusUp, resp.Status)
+				require.Len(t, resp.Checks, 2)
+				assert.Equal(t, "postgres", resp.Checks[0].Name)
+				assert.Equal(t, healthStatusUp, resp.Checks[0].Status)
+				assert.Nil(t, resp.Checks[0].Error)
+				assert.Equal(t, "s3", resp.Checks[1].Name)
+				assert.Equal(t, healthStatusUp, resp.Checks[1].Status)
+			},
+		},
+		{
+			name: "one check down",
+			setupMocks: func(db, storage *mocks.MockHealthChecker) {
+				db.EXPECT().CheckHealth(gomock.Any()).Return(nil)
+				storage.EXPECT().CheckHealth(gomock.Any()).Return(errors.New("bucket unreachable"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			validate: func(t *testing.T, resp healthResponse) {
+				assert.Equal(t, healthStatusDown, resp.Status)
+				require.Len(t, resp.Checks, 2)
+				assert.Equal(t, healthStatusUp, resp.Checks[0].Status)
+				assert.Equal(t, healthStatusDown, resp.Checks[1].Status)
+				require.NotNil(t, resp.Checks[1].Error)
+				assert.Equal(t, "bucket unreachable", *resp.Checks[1].Error)
+			},
+		},
+		{
+			name: "check timed out",
+			setupMocks: func(db, storage *mocks.MockHealthChecker) {
+				db.EXPECT().CheckHealth(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				})
+				storage.EXPECT().CheckHealth(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			validate: func(t *testing.T, resp healthResponse) {
+				assert.Equal(t, healthStatusDown, resp.Status)
+				require.NotNil(t, resp.Checks[0].Error)
+				assert.Equal(t, "check timed out", *resp.Checks[0].Error)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			db := mocks.NewMockHealthChecker(ctrl)
+			db.EXPECT().Name().Return("postgres").AnyTimes()
+			storage := mocks.NewMockHealthChecker(ctrl)
+			storage.EXPECT().Name().Return("s3").AnyTimes()
+			tt.setupMocks(db, storage)
+
+			rec := httptest.NewRecorder()
+			handler := HandleHealthReady([]HealthChecker{db, storage}, 50*time.Millisecond)
+			handler(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			var resp healthResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			tt.validate(t, resp)
+		})
+	}
+}
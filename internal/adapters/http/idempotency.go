@@ -0,0 +1,232 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/httplog/v3"
+	"github.com/peano88/medias/internal/domain"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	defaultIdempotencyKeyTTL  = 24 * time.Hour
+	idempotencyReleaseTimeout = 5 * time.Second
+	// maxIdempotentRequestBytes bounds the body read to fingerprint a request
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// replayedHeaders are the response headers stored and replayed along with the
+// body of an idempotent request, besides Content-Type
+var replayedHeaders = []string{"Location", "ETag", "Cache-Control", "Last-Modified"}
+
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key domain.IdempotencyKey, fingerprint string, ttl time.Duration) (domain.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, record domain.IdempotencyRecord) error
+	Release(ctx context.Context, key domain.IdempotencyKey) error
+}
+
+// recordingResponseWriter keeps a copy of the response sent to the client
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (rw *recordingResponseWriter) WriteHeader(code int) {
+	if rw.statusCode == 0 {
+		rw.statusCode = code
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingResponseWriter) Write(b []byte) (int, error) {
+	if rw.statusCode == 0 {
+		rw.statusCode = http.StatusOK
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// idempotencyMiddleware makes requests carrying an Idempotency-Key header safe
+// to retry: the first response is stored along with a fingerprint of the
+// request and replayed for later requests with the same key. Reusing a key for
+// a different request is rejected with 422, and a retry arriving while the
+// original request is still processed gets a 409. Server errors are not stored
+// so that the request can be retried. Keys are scoped to the tenant and the
+// subject of the request: clients do not share them.
+func idempotencyMiddleware(deps Dependencies) middlewarehandler {
+	ttl := deps.IdempotencyTTL
+	if ttl <= 0 {
+		ttl = defaultIdempotencyKeyTTL
+	}
+
+	return func(next http.Handler) http.Handler {
+		if deps.IdempotencyStore == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := r.Header[http.CanonicalHeaderKey(IdempotencyKeyHeader)]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) != 1 || strings.TrimSpace(key[0]) == "" || len(key[0]) > domain.MaxIdempotencyKeyLength {
+				errDetails := fmt.Sprintf("%s must be a single non empty value of at most %d characters",
+					IdempotencyKeyHeader, domain.MaxIdempotencyKeyLength)
+				respondWithError(r.Context(), w, http.StatusBadRequest, "INVALID_REQUEST",
+					"Invalid idempotency key", &errDetails, nil)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+			if err != nil {
+				errDetails := err.Error()
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					errDetails = fmt.Sprintf("the body of a request with an %s must be at most %d bytes",
+						IdempotencyKeyHeader, tooLarge.Limit)
+					respondWithError(r.Context(), w, http.StatusRequestEntityTooLarge, "INVALID_REQUEST",
+						"Request body too large", &errDetails, nil)
+					return
+				}
+				respondWithError(r.Context(), w, http.StatusBadRequest, "INVALID_REQUEST",
+					"Failed to read request body", &errDetails, nil)
+				return
+			}
+			_ = r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scoped := idempotencyKey(r, key[0])
+			fingerprint := requestFingerprint(r, body)
+			record, reserved, err := deps.IdempotencyStore.Reserve(r.Context(), scoped, fingerprint, ttl)
+			if err != nil {
+				handleExecutorError(r.Context(), w, err)
+				return
+			}
+
+			if !reserved {
+				replayIdempotentResponse(w, r, record, fingerprint)
+				return
+			}
+
+			rec := &recordingResponseWriter{ResponseWriter: w}
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				// The handler failed or panicked: free the key for a retry.
+				// The request context may be done already.
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), idempotencyReleaseTimeout)
+				defer cancel()
+				if err := deps.IdempotencyStore.Release(ctx, scoped); err != nil {
+					_ = httplog.SetError(r.Context(), err)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.statusCode == 0 || rec.statusCode >= http.StatusInternalServerError {
+				return
+			}
+
+			record.StatusCode = rec.statusCode
+			record.ContentType = rec.Header().Get("Content-Type")
+			record.Headers = storedHeaders(rec.Header())
+			record.Body = rec.body.Bytes()
+			if err := deps.IdempotencyStore.Complete(context.WithoutCancel(r.Context()), record); err != nil {
+				// The response was sent already, the key is released for a retry
+				_ = httplog.SetError(r.Context(), err)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, r *http.Request, record domain.IdempotencyRecord, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		errDetails := "the idempotency key was already used for a different request"
+		respondWithError(r.Context(), w, http.StatusUnprocessableEntity, domain.InvalidEntityCode,
+			"Idempotency key reused", &errDetails, nil)
+		return
+	}
+
+	if !record.Completed() {
+		errDetails := "the original request with this idempotency key is still being processed"
+		respondWithError(r.Context(), w, http.StatusConflict, domain.ConflictCode,
+			"Request in progress", &errDetails, nil)
+		return
+	}
+
+	httplog.SetAttrs(r.Context(), slog.Bool("idempotent_replay", true))
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+	for name, value := range record.Headers {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// storedHeaders picks the replayed headers set on a response
+func storedHeaders(header http.Header) map[string]string {
+	var stored map[string]string
+	for _, name := range replayedHeaders {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+		if stored == nil {
+			stored = make(map[string]string, len(replayedHeaders))
+		}
+		stored[name] = value
+	}
+	return stored
+}
+
+// idempotencyKey scopes key to the tenant and the subject of the request
+func idempotencyKey(r *http.Request, key string) domain.IdempotencyKey {
+	scoped := domain.IdempotencyKey{
+		Tenant: domain.TenantFromContext(r.Context()),
+		Key:    key,
+	}
+	if principal, ok := domain.PrincipalFromContext(r.Context()); ok {
+		scoped.Subject = principal.Subject
+	}
+	return scoped
+}
+
+// requestFingerprint identifies a request by its tenant, client, method, path
+// and body. Including the tenant and the client keeps a key from replaying
+// another client's response.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(domain.TenantFromContext(r.Context())))
+	h.Write([]byte{0})
+	if principal, ok := domain.PrincipalFromContext(r.Context()); ok {
+		h.Write([]byte(principal.Subject))
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,259 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_idempotency_store.go -package=mocks github.com/peano88/medias/internal/adapters/http IdempotencyStore
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	const (
+		key  = "retry-me-123"
+		body = `{"name":"soccer"}`
+	)
+	fingerprint := requestFingerprint(httptest.NewRequest(http.MethodPost, "/api/v1/tags", nil), []byte(body))
+	scoped := domain.IdempotencyKey{Tenant: domain.DefaultTenant, Key: key}
+	ttl := time.Hour
+
+	createdHandler := func(t *testing.T, called *bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*called = true
+			received, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, body, string(received))
+			w.Header().Set("Location", "/api/v1/tags/1")
+			JSONOut(w, http.StatusCreated, map[string]string{"id": "1"})
+		}
+	}
+
+	tests := []struct {
+		name          string
+		headers       map[string][]string
+		requestBody   string
+		setupMock     func(*mocks.MockIdempotencyStore)
+		handler       func(*testing.T, *bool) http.HandlerFunc
+		expectHandler bool
+		validate      func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:          "no key - passes through",
+			setupMock:     func(s *mocks.MockIdempotencyStore) {},
+			handler:       createdHandler,
+			expectHandler: true,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+				assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+			},
+		},
+		{
+			name:    "first request - response is stored",
+			headers: map[string][]string{IdempotencyKeyHeader: {key}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {
+				s.EXPECT().
+					Reserve(gomock.Any(), scoped, fingerprint, ttl).
+					Return(domain.IdempotencyRecord{IdempotencyKey: scoped, Fingerprint: fingerprint}, true, nil)
+				s.EXPECT().
+					Complete(gomock.Any(), domain.IdempotencyRecord{
+						IdempotencyKey: scoped,
+						Fingerprint:    fingerprint,
+						StatusCode:     http.StatusCreated,
+						ContentType:    "application/json",
+						Headers:        map[string]string{"Location": "/api/v1/tags/1"},
+						Body:           []byte("{\"id\":\"1\"}\n"),
+					}).
+					Return(nil)
+			},
+			handler:       createdHandler,
+			expectHandler: true,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+				assert.JSONEq(t, `{"id":"1"}`, rec.Body.String())
+			},
+		},
+		{
+			name:    "retry - stored response is replayed",
+			headers: map[string][]string{IdempotencyKeyHeader: {key}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {
+				s.EXPECT().
+					Reserve(gomock.Any(), scoped, fingerprint, ttl).
+					Return(domain.IdempotencyRecord{
+						IdempotencyKey: scoped,
+						Fingerprint:    fingerprint,
+						StatusCode:     http.StatusCreated,
+						ContentType:    "application/json",
+						Headers:        map[string]string{"Location": "/api/v1/tags/1", "ETag": `"1"`},
+						Body:           []byte(`{"id":"1"}`),
+					}, false, nil)
+			},
+			handler: createdHandler,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+				assert.Equal(t, "/api/v1/tags/1", rec.Header().Get("Location"))
+				assert.Equal(t, `"1"`, rec.Header().Get("ETag"))
+				assert.Equal(t, "true", rec.Header().Get(IdempotentReplayedHeader))
+				assert.JSONEq(t, `{"id":"1"}`, rec.Body.String())
+			},
+		},
+		{
+			name:    "key reused with a different request",
+			headers: map[string][]string{IdempotencyKeyHeader: {key}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {
+				s.EXPECT().
+					Reserve(gomock.Any(), scoped, fingerprint, ttl).
+					Return(domain.IdempotencyRecord{IdempotencyKey: scoped, Fingerprint: "other", StatusCode: http.StatusCreated}, false, nil)
+			},
+			handler: createdHandler,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+				assert.Contains(t, rec.Body.String(), domain.InvalidEntityCode)
+			},
+		},
+		{
+			name:    "original request still in progress",
+			headers: map[string][]string{IdempotencyKeyHeader: {key}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {
+				s.EXPECT().
+					Reserve(gomock.Any(), scoped, fingerprint, ttl).
+					Return(domain.IdempotencyRecord{IdempotencyKey: scoped, Fingerprint: fingerprint}, false, nil)
+			},
+			handler: createdHandler,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, rec.Code)
+				assert.Contains(t, rec.Body.String(), domain.ConflictCode)
+			},
+		},
+		{
+			name:    "server error - key is released",
+			headers: map[string][]string{IdempotencyKeyHeader: {key}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {
+				s.EXPECT().
+					Reserve(gomock.Any(), scoped, fingerprint, ttl).
+					Return(domain.IdempotencyRecord{IdempotencyKey: scoped, Fingerprint: fingerprint}, true, nil)
+				s.EXPECT().Release(gomock.Any(), scoped).Return(nil)
+			},
+			handler: func(t *testing.T, called *bool) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					*called = true
+					respondWithError(r.Context(), w, http.StatusInternalServerError, domain.InternalCode, "boom", nil, nil)
+				}
+			},
+			expectHandler: true,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			},
+		},
+		{
+			name:    "store error",
+			headers: map[string][]string{IdempotencyKeyHeader: {key}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {
+				s.EXPECT().
+					Reserve(gomock.Any(), scoped, fingerprint, ttl).
+					Return(domain.IdempotencyRecord{}, false, domain.NewError(domain.InternalCode, domain.WithMessage("database error")))
+			},
+			handler: createdHandler,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			},
+		},
+		{
+			name:      "invalid key - empty",
+			headers:   map[string][]string{IdempotencyKeyHeader: {" "}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {},
+			handler:   createdHandler,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:      "invalid key - several values",
+			headers:   map[string][]string{IdempotencyKeyHeader: {"a", "b"}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {},
+			handler:   createdHandler,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:      "invalid key - too long",
+			headers:   map[string][]string{IdempotencyKeyHeader: {strings.Repeat("k", domain.MaxIdempotencyKeyLength+1)}},
+			setupMock: func(s *mocks.MockIdempotencyStore) {},
+			handler:   createdHandler,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name:        "body too large",
+			headers:     map[string][]string{IdempotencyKeyHeader: {key}},
+			requestBody: strings.Repeat("x", maxIdempotentRequestBytes+1),
+			setupMock:   func(s *mocks.MockIdempotencyStore) {},
+			handler:     createdHandler,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mocks.NewMockIdempotencyStore(ctrl)
+			tt.setupMock(store)
+
+			called := false
+			mw := idempotencyMiddleware(Dependencies{IdempotencyStore: store, IdempotencyTTL: ttl})
+			handler := mw(tt.handler(t, &called))
+
+			requestBody := body
+			if tt.requestBody != "" {
+				requestBody = tt.requestBody
+			}
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tags", strings.NewReader(requestBody))
+			for name, values := range tt.headers {
+				for _, value := range values {
+					req.Header.Add(name, value)
+				}
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectHandler, called)
+			tt.validate(t, rec)
+		})
+	}
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	body := []byte(`{"name":"soccer"}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tags", nil)
+	acmeReq := req.WithContext(domain.ContextWithTenant(req.Context(), "acme"))
+	userReq := req.WithContext(domain.ContextWithPrincipal(req.Context(), domain.Principal{Subject: "user-42"}))
+
+	assert.Equal(t, requestFingerprint(req, body), requestFingerprint(req, body))
+	assert.NotEqual(t, requestFingerprint(req, body), requestFingerprint(acmeReq, body))
+	assert.NotEqual(t, requestFingerprint(req, body), requestFingerprint(userReq, body))
+	assert.NotEqual(t, requestFingerprint(req, body), requestFingerprint(req, []byte(`{"name":"tennis"}`)))
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tags", nil)
+	ctx := domain.ContextWithTenant(req.Context(), "acme")
+	ctx = domain.ContextWithPrincipal(ctx, domain.Principal{Subject: "user-42"})
+
+	assert.Equal(t, domain.IdempotencyKey{Tenant: domain.DefaultTenant, Key: "k"}, idempotencyKey(req, "k"))
+	assert.Equal(t, domain.IdempotencyKey{Tenant: "acme", Subject: "user-42", Key: "k"}, idempotencyKey(req.WithContext(ctx), "k"))
+}
@@ -10,7 +10,7 @@ func JSONIn[T any](rw http.ResponseWriter, r *http.Request) (T, error) {
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		errDetails := err.Error()
-		respondWithError(rw, http.StatusBadRequest, "INVALID_REQUEST",
+		respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
 			"Failed to parse request body", &errDetails, nil)
 		return req, err
 	}
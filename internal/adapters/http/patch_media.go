@@ -0,0 +1,48 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+)
+
+type MediaUpdater interface {
+	Execute(ctx context.Context, id uuid.UUID, description *string, expectedUpdatedAt time.Time) (domain.Media, error)
+}
+
+func HandlePatchMedia(mu MediaUpdater) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// Parse UUID from URL path
+		mediaID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			errDetails := "Invalid UUID format"
+			respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
+				"Invalid media ID", &errDetails, nil)
+			return
+		}
+
+		expectedUpdatedAt, ok := requireIfMatch(rw, r)
+		if !ok {
+			return
+		}
+
+		req, err := JSONIn[updateDescriptionRequest](rw, r)
+		if err != nil {
+			return
+		}
+
+		// Execute business logic
+		media, err := mu.Execute(r.Context(), mediaID, req.Description, expectedUpdatedAt)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		rw.Header().Set("ETag", entityTag(media.UpdatedAt))
+		JSONOut(rw, http.StatusOK, buildMediaResponse(media))
+	}
+}
@@ -0,0 +1,137 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_media_updater.go -package=mocks github.com/peano88/medias/internal/adapters/http MediaUpdater
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandlePatchMedia(t *testing.T) {
+	mediaID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	currentUpdatedAt := time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC)
+	newUpdatedAt := time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		mediaID   string
+		ifMatch   string
+		body      string
+		setupMock func(*mocks.MockMediaUpdater)
+		validate  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "success - returns the new ETag",
+			mediaID: mediaID.String(),
+			ifMatch: entityTag(currentUpdatedAt),
+			body:    `{"description": "Winning goal"}`,
+			setupMock: func(mu *mocks.MockMediaUpdater) {
+				mu.EXPECT().
+					Execute(gomock.Any(), mediaID, stringPtr("Winning goal"), currentUpdatedAt).
+					Return(domain.Media{
+						ID:          mediaID,
+						Filename:    "world-cup-final.jpg",
+						Description: stringPtr("Winning goal"),
+						Status:      domain.MediaStatusFinalized,
+						Tags:        []domain.Tag{},
+						UpdatedAt:   newUpdatedAt,
+					}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, entityTag(newUpdatedAt), rec.Header().Get("ETag"))
+
+				var response mediaResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Winning goal", *response.Data.Description)
+			},
+		},
+		{
+			name:    "success - null clears the description",
+			mediaID: mediaID.String(),
+			ifMatch: entityTag(currentUpdatedAt),
+			body:    `{"description": null}`,
+			setupMock: func(mu *mocks.MockMediaUpdater) {
+				mu.EXPECT().
+					Execute(gomock.Any(), mediaID, nil, currentUpdatedAt).
+					Return(domain.Media{ID: mediaID, Tags: []domain.Tag{}, UpdatedAt: newUpdatedAt}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name:    "stale ETag",
+			mediaID: mediaID.String(),
+			ifMatch: entityTag(currentUpdatedAt),
+			body:    `{"description": "Winning goal"}`,
+			setupMock: func(mu *mocks.MockMediaUpdater) {
+				mu.EXPECT().
+					Execute(gomock.Any(), mediaID, gomock.Any(), currentUpdatedAt).
+					Return(domain.Media{}, domain.NewError(domain.PreconditionFailedCode, domain.WithMessage("media was modified")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+				assert.Empty(t, rec.Header().Get("ETag"))
+			},
+		},
+		{
+			name:      "missing If-Match",
+			mediaID:   mediaID.String(),
+			body:      `{"description": "Winning goal"}`,
+			setupMock: func(mu *mocks.MockMediaUpdater) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+				assertErrorCode(t, rec, domain.PreconditionRequiredCode)
+			},
+		},
+		{
+			name:      "invalid UUID",
+			mediaID:   "invalid-uuid",
+			ifMatch:   entityTag(currentUpdatedAt),
+			body:      `{"description": "Winning goal"}`,
+			setupMock: func(mu *mocks.MockMediaUpdater) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMU := mocks.NewMockMediaUpdater(ctrl)
+			tt.setupMock(mockMU)
+
+			handler := HandlePatchMedia(mockMU)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/media/"+tt.mediaID, strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.mediaID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
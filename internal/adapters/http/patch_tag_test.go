@@ -0,0 +1,137 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_tag_updater.go -package=mocks github.com/peano88/medias/internal/adapters/http TagUpdater
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandlePatchTag(t *testing.T) {
+	tagID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	currentUpdatedAt := time.Date(2023, 1, 1, 12, 0, 0, 123456000, time.UTC)
+	newUpdatedAt := time.Date(2024, 2, 1, 8, 30, 0, 654321000, time.UTC)
+
+	tests := []struct {
+		name      string
+		ifMatch   string
+		body      string
+		setupMock func(*mocks.MockTagUpdater)
+		validate  func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "success - returns the new ETag",
+			ifMatch: entityTag(currentUpdatedAt),
+			body:    `{"description": "Association football"}`,
+			setupMock: func(tu *mocks.MockTagUpdater) {
+				tu.EXPECT().
+					Execute(gomock.Any(), tagID, stringPtr("Association football"), currentUpdatedAt).
+					Return(domain.Tag{
+						ID:          tagID,
+						Name:        "soccer",
+						Description: stringPtr("Association football"),
+						UpdatedAt:   newUpdatedAt,
+					}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, entityTag(newUpdatedAt), rec.Header().Get("ETag"))
+
+				var response tagResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Association football", *response.Data.Description)
+			},
+		},
+		{
+			name:    "stale ETag",
+			ifMatch: entityTag(currentUpdatedAt),
+			body:    `{"description": "Association football"}`,
+			setupMock: func(tu *mocks.MockTagUpdater) {
+				tu.EXPECT().
+					Execute(gomock.Any(), tagID, gomock.Any(), currentUpdatedAt).
+					Return(domain.Tag{}, domain.NewError(domain.PreconditionFailedCode, domain.WithMessage("tag was modified")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.PreconditionFailedCode, response.Error.Code)
+			},
+		},
+		{
+			name:      "missing If-Match",
+			body:      `{"description": "Association football"}`,
+			setupMock: func(tu *mocks.MockTagUpdater) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+			},
+		},
+		{
+			name:      "wildcard If-Match",
+			ifMatch:   "*",
+			body:      `{"description": "Association football"}`,
+			setupMock: func(tu *mocks.MockTagUpdater) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+			},
+		},
+		{
+			name:      "weak ETag never matches",
+			ifMatch:   "W/" + entityTag(currentUpdatedAt),
+			body:      `{"description": "Association football"}`,
+			setupMock: func(tu *mocks.MockTagUpdater) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+			},
+		},
+		{
+			name:      "invalid json",
+			ifMatch:   entityTag(currentUpdatedAt),
+			body:      `{"description": }`,
+			setupMock: func(tu *mocks.MockTagUpdater) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTU := mocks.NewMockTagUpdater(ctrl)
+			tt.setupMock(mockTU)
+
+			handler := HandlePatchTag(mockTU)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/tags/"+tagID.String(), strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tagID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
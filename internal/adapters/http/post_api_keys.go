@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+type APIKeyCreator interface {
+	Execute(context.Context, domain.APIKey) (domain.APIKey, error)
+}
+
+func HandlePostAPIKeys(kc APIKeyCreator) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+
+		req, err := JSONIn[createAPIKeyRequest](rw, r)
+		if err != nil {
+			return
+		}
+
+		key := domain.APIKey{
+			Name:   req.Name,
+			Scopes: make([]domain.Scope, len(req.Scopes)),
+			Tenant: req.Tenant,
+		}
+		for i, scope := range req.Scopes {
+			key.Scopes[i] = domain.Scope(scope)
+		}
+
+		// Execute business logic
+		created, err := kc.Execute(r.Context(), key)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		rw.Header().Set("Location", BasePath+"/admin/api-keys/"+created.ID.String())
+		// the key is in clear in the response
+		rw.Header().Set("Cache-Control", "no-store")
+		JSONOut(rw, http.StatusCreated, apiKeyResponse{Data: buildAPIKeyData(created)})
+	}
+}
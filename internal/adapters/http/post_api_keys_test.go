@@ -0,0 +1,125 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_api_key_creator.go -package=mocks github.com/peano88/medias/internal/adapters/http APIKeyCreator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandlePostAPIKeys(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name        string
+		requestBody any
+		setupMock   func(*mocks.MockAPIKeyCreator)
+		validate    func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "success - returns the key once",
+			requestBody: createAPIKeyRequest{Name: "uploader", Scopes: []string{"media:write"}},
+			setupMock: func(kc *mocks.MockAPIKeyCreator) {
+				kc.EXPECT().
+					Execute(gomock.Any(), domain.APIKey{Name: "uploader", Scopes: []domain.Scope{domain.ScopeMediaWrite}}).
+					Return(domain.APIKey{
+						ID:        id,
+						Name:      "uploader",
+						Prefix:    "mk_abcdefghi",
+						Key:       "mk_abcdefghijklmnop",
+						Scopes:    []domain.Scope{domain.ScopeMediaWrite},
+						CreatedAt: time.Now(),
+					}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+				assert.Equal(t, BasePath+"/admin/api-keys/"+id.String(), rec.Header().Get("Location"))
+				assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+				var response apiKeyResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "mk_abcdefghijklmnop", response.Data.Key)
+				assert.Equal(t, []string{"media:write"}, response.Data.Scopes)
+			},
+		},
+		{
+			name:        "success - key bound to a tenant",
+			requestBody: createAPIKeyRequest{Name: "acme uploader", Scopes: []string{"media:write"}, Tenant: "acme"},
+			setupMock: func(kc *mocks.MockAPIKeyCreator) {
+				kc.EXPECT().
+					Execute(gomock.Any(), domain.APIKey{Name: "acme uploader", Scopes: []domain.Scope{domain.ScopeMediaWrite}, Tenant: "acme"}).
+					Return(domain.APIKey{
+						ID:        id,
+						Name:      "acme uploader",
+						Prefix:    "mk_abcdefghi",
+						Key:       "mk_abcdefghijklmnop",
+						Scopes:    []domain.Scope{domain.ScopeMediaWrite},
+						Tenant:    "acme",
+						CreatedAt: time.Now(),
+					}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+
+				var response apiKeyResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "acme", response.Data.Tenant)
+			},
+		},
+		{
+			name:        "error - invalid scope",
+			requestBody: createAPIKeyRequest{Name: "uploader", Scopes: []string{"media:delete"}},
+			setupMock: func(kc *mocks.MockAPIKeyCreator) {
+				kc.EXPECT().
+					Execute(gomock.Any(), gomock.Any()).
+					Return(domain.APIKey{}, domain.NewError(domain.InvalidEntityCode, domain.WithMessage("invalid scopes")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+		{
+			name:        "error - invalid JSON",
+			requestBody: "{",
+			setupMock:   func(kc *mocks.MockAPIKeyCreator) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			creator := mocks.NewMockAPIKeyCreator(ctrl)
+			tt.setupMock(creator)
+
+			var body []byte
+			if s, ok := tt.requestBody.(string); ok {
+				body = []byte(s)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/api-keys", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			HandlePostAPIKeys(creator)(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
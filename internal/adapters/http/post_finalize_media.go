@@ -19,7 +19,7 @@ func HandlePostFinalizeMedia(mf MediaFinalizer) func(http.ResponseWriter, *http.
 		// Extract media ID from URL path
 		mediaIDStr := chi.URLParam(r, "id")
 		if mediaIDStr == "" {
-			respondWithError(rw, http.StatusBadRequest, "INVALID_REQUEST",
+			respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
 				"Media ID is required", nil, nil)
 			return
 		}
@@ -28,7 +28,7 @@ func HandlePostFinalizeMedia(mf MediaFinalizer) func(http.ResponseWriter, *http.
 		mediaID, err := uuid.Parse(mediaIDStr)
 		if err != nil {
 			errDetails := "Invalid UUID format"
-			respondWithError(rw, http.StatusBadRequest, "INVALID_REQUEST",
+			respondWithError(r.Context(), rw, http.StatusBadRequest, "INVALID_REQUEST",
 				"Invalid media ID", &errDetails, nil)
 			return
 		}
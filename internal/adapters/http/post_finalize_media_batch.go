@@ -0,0 +1,33 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+)
+
+type MediaBatchFinalizer interface {
+	ExecuteBatch(ctx context.Context, ids []uuid.UUID) ([]domain.MediaBatchResult, error)
+}
+
+func HandlePostFinalizeMediaBatch(mbf MediaBatchFinalizer) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+
+		// Malformed ids are rejected with the whole request, like for a single media
+		req, err := JSONIn[finalizeMediaBatchRequest](rw, r)
+		if err != nil {
+			return
+		}
+
+		results, err := mbf.ExecuteBatch(r.Context(), req.IDs)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		// Item failures are reported in the body, the batch itself succeeded
+		JSONOut(rw, http.StatusOK, buildFinalizeMediaBatchResponse(results))
+	}
+}
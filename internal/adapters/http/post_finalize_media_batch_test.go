@@ -0,0 +1,126 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_media_batch_finalizer.go -package=mocks github.com/peano88/medias/internal/adapters/http MediaBatchFinalizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandlePostFinalizeMediaBatch(t *testing.T) {
+	finalizedID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	failedID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	unknownID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	tests := []struct {
+		name        string
+		requestBody string
+		setupMock   func(*mocks.MockMediaBatchFinalizer)
+		validate    func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "success - per id outcomes",
+			requestBody: `{"ids": ["11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222", "33333333-3333-3333-3333-333333333333"]}`,
+			setupMock: func(mbf *mocks.MockMediaBatchFinalizer) {
+				results := []domain.MediaBatchResult{
+					{Media: domain.Media{ID: finalizedID, Filename: "world-cup-final.jpg", Status: domain.MediaStatusFinalized}},
+					{
+						Media: domain.Media{ID: failedID, Filename: "tennis-serve.mp4", Status: domain.MediaStatusFailed},
+						Err: domain.NewError(domain.InvalidEntityCode,
+							domain.WithMessage("media file not found in file storage"),
+						),
+					},
+					{Err: domain.NewError(domain.NotFoundCode, domain.WithMessage("media not found"))},
+				}
+				mbf.EXPECT().
+					ExecuteBatch(gomock.Any(), []uuid.UUID{finalizedID, failedID, unknownID}).
+					Return(results, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+
+				var response mediaBatchResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				if assert.Len(t, response.Data, 3) {
+					assert.Equal(t, "finalized", response.Data[0].Status)
+					assert.Equal(t, "finalized", response.Data[0].Data.Status)
+					assert.Nil(t, response.Data[0].Error)
+
+					assert.Equal(t, 1, response.Data[1].Index)
+					assert.Equal(t, "failed", response.Data[1].Status)
+					assert.Equal(t, "failed", response.Data[1].Data.Status)
+					assert.Equal(t, domain.InvalidEntityCode, response.Data[1].Error.Code)
+
+					assert.Equal(t, "error", response.Data[2].Status)
+					assert.Nil(t, response.Data[2].Data)
+					assert.Equal(t, domain.NotFoundCode, response.Data[2].Error.Code)
+				}
+			},
+		},
+		{
+			name:        "invalid media id",
+			requestBody: `{"ids": ["not-a-uuid"]}`,
+			setupMock:   func(mbf *mocks.MockMediaBatchFinalizer) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, "INVALID_REQUEST", response.Error.Code)
+			},
+		},
+		{
+			name:        "validation error - empty batch",
+			requestBody: `{"ids": []}`,
+			setupMock: func(mbf *mocks.MockMediaBatchFinalizer) {
+				validationErr := domain.NewError(domain.InvalidEntityCode,
+					domain.WithMessage("invalid batch"),
+					domain.WithDetails("batch cannot be empty"),
+				)
+				mbf.EXPECT().
+					ExecuteBatch(gomock.Any(), []uuid.UUID{}).
+					Return(nil, validationErr)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.InvalidEntityCode, response.Error.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMBF := mocks.NewMockMediaBatchFinalizer(ctrl)
+			tt.setupMock(mockMBF)
+
+			handler := HandlePostFinalizeMediaBatch(mockMBF)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/media/finalize:batch", bytes.NewReader([]byte(tt.requestBody)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
@@ -26,6 +26,7 @@ func HandlePostMedia(mc MediaCreator) func(http.ResponseWriter, *http.Request) {
 			MimeType:    req.MimeType,
 			Size:        req.Size,
 			SHA256:      req.SHA256,
+			Visibility:  domain.Visibility(req.Visibility),
 		}
 
 		// Execute business logic
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+type MediaBatchCreator interface {
+	ExecuteBatch(context.Context, []domain.MediaBatchItem) ([]domain.MediaBatchResult, error)
+}
+
+func HandlePostMediaBatch(mbc MediaBatchCreator) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+
+		req, err := JSONIn[[]createMediaRequest](rw, r)
+		if err != nil {
+			return
+		}
+
+		// Map requests to domain, same as for a single media
+		items := make([]domain.MediaBatchItem, len(req))
+		for i, item := range req {
+			items[i] = domain.MediaBatchItem{
+				Media: domain.Media{
+					Filename:    item.Title,
+					Description: item.Description,
+					MimeType:    item.MimeType,
+					Size:        item.Size,
+					SHA256:      item.SHA256,
+					Visibility:  domain.Visibility(item.Visibility),
+				},
+				TagNames: item.Tags,
+			}
+		}
+
+		results, err := mbc.ExecuteBatch(r.Context(), items)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		// Item failures are reported in the body, the batch itself succeeded
+		JSONOut(rw, http.StatusOK, buildMediaBatchResponse(results))
+	}
+}
@@ -0,0 +1,183 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_media_batch_creator.go -package=mocks github.com/peano88/medias/internal/adapters/http MediaBatchCreator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandlePostMediaBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestBody any
+		setupMock   func(*mocks.MockMediaBatchCreator)
+		validate    func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success - per item results",
+			requestBody: []createMediaRequest{
+				{Title: "penalty-kick.jpg", MimeType: "image/jpeg", Size: 3500000, SHA256: "p3n4lty", Tags: []string{"soccer"}},
+				{Title: "slam-dunk.mp4", MimeType: "video/mp4", Size: 18000000, SHA256: "sl4md"},
+				{Title: "final-score.jpg", MimeType: "image/jpeg", Size: 2000000, SHA256: "f1n4l"},
+			},
+			setupMock: func(mbc *mocks.MockMediaBatchCreator) {
+				items := []domain.MediaBatchItem{
+					{Media: domain.Media{Filename: "penalty-kick.jpg", MimeType: "image/jpeg", Size: 3500000, SHA256: "p3n4lty"}, TagNames: []string{"soccer"}},
+					{Media: domain.Media{Filename: "slam-dunk.mp4", MimeType: "video/mp4", Size: 18000000, SHA256: "sl4md"}},
+					{Media: domain.Media{Filename: "final-score.jpg", MimeType: "image/jpeg", Size: 2000000, SHA256: "f1n4l"}},
+				}
+				results := []domain.MediaBatchResult{
+					{Media: domain.Media{
+						ID:        uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"),
+						Operation: domain.MediaOperationCreate,
+						Filename:  "penalty-kick.jpg",
+						Status:    domain.MediaStatusReserved,
+						URL:       "http://localhost:8080/upload/p3n4lty/penalty-kick.jpg",
+						Type:      domain.MediaTypeImage,
+						Tags:      []domain.Tag{{ID: uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"), Name: "soccer"}},
+						CreatedAt: time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC),
+						UpdatedAt: time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC),
+					}},
+					{Media: domain.Media{
+						ID:        uuid.MustParse("dddddddd-dddd-dddd-dddd-dddddddddddd"),
+						Operation: domain.MediaOperationUpdate,
+						Filename:  "slam-dunk.mp4",
+						Status:    domain.MediaStatusReserved,
+						Type:      domain.MediaTypeVideo,
+						Tags:      []domain.Tag{},
+					}},
+					{Err: domain.NewError(domain.ConflictCode,
+						domain.WithMessage("media already exists"),
+						domain.WithDetails("a finalized media file with this filename and sha256 already exists"),
+					)},
+				}
+				mbc.EXPECT().
+					ExecuteBatch(gomock.Any(), items).
+					Return(results, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+
+				var response mediaBatchResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				if assert.Len(t, response.Data, 3) {
+					assert.Equal(t, 0, response.Data[0].Index)
+					assert.Equal(t, "created", response.Data[0].Status)
+					assert.Equal(t, "penalty-kick.jpg", response.Data[0].Data.Filename)
+					assert.Len(t, response.Data[0].Data.Tags, 1)
+					assert.Nil(t, response.Data[0].Error)
+
+					assert.Equal(t, 1, response.Data[1].Index)
+					assert.Equal(t, "reissued", response.Data[1].Status)
+					assert.Equal(t, "slam-dunk.mp4", response.Data[1].Data.Filename)
+
+					assert.Equal(t, 2, response.Data[2].Index)
+					assert.Equal(t, "error", response.Data[2].Status)
+					assert.Nil(t, response.Data[2].Data)
+					assert.Equal(t, domain.ConflictCode, response.Data[2].Error.Code)
+				}
+			},
+		},
+		{
+			name:        "invalid json",
+			requestBody: `{"title": "not-an-array.jpg"}`,
+			setupMock:   func(mbc *mocks.MockMediaBatchCreator) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, response.Error.Code)
+			},
+		},
+		{
+			name:        "validation error - empty batch",
+			requestBody: []createMediaRequest{},
+			setupMock: func(mbc *mocks.MockMediaBatchCreator) {
+				validationErr := domain.NewError(domain.InvalidEntityCode,
+					domain.WithMessage("invalid batch"),
+					domain.WithDetails("batch cannot be empty"),
+				)
+				mbc.EXPECT().
+					ExecuteBatch(gomock.Any(), []domain.MediaBatchItem{}).
+					Return(nil, validationErr)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.InvalidEntityCode, response.Error.Code)
+			},
+		},
+		{
+			name: "internal error",
+			requestBody: []createMediaRequest{
+				{Title: "training-session.mp4", MimeType: "video/mp4", Size: 25000000, SHA256: "tr41n"},
+			},
+			setupMock: func(mbc *mocks.MockMediaBatchCreator) {
+				internalErr := domain.NewError(domain.InternalCode,
+					domain.WithMessage("database error"),
+					domain.WithDetails("connection timeout"),
+				)
+				mbc.EXPECT().
+					ExecuteBatch(gomock.Any(), gomock.Any()).
+					Return(nil, internalErr)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+				var response errorResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				assert.NoError(t, err)
+				assert.Equal(t, domain.InternalCode, response.Error.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMBC := mocks.NewMockMediaBatchCreator(ctrl)
+			tt.setupMock(mockMBC)
+
+			handler := HandlePostMediaBatch(mockMBC)
+
+			var body []byte
+			var err error
+
+			// Handle both struct and string request bodies
+			if str, ok := tt.requestBody.(string); ok {
+				body = []byte(str)
+			} else {
+				body, err = json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/media:batch", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
@@ -33,6 +33,7 @@ func TestHandlePostMedia(t *testing.T) {
 				Size:        3500000,
 				SHA256:      "p3n4lty",
 				Tags:        []string{"soccer", "penalty"},
+				Visibility:  "shared",
 			},
 			setupMock: func(mc *mocks.MockMediaCreator) {
 				inputMedia := domain.Media{
@@ -41,6 +42,7 @@ func TestHandlePostMedia(t *testing.T) {
 					MimeType:    "image/jpeg",
 					Size:        3500000,
 					SHA256:      "p3n4lty",
+					Visibility:  domain.VisibilityShared,
 				}
 				createdMedia := domain.Media{
 					ID:          uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"),
@@ -52,6 +54,8 @@ func TestHandlePostMedia(t *testing.T) {
 					Type:        domain.MediaTypeImage,
 					MimeType:    "image/jpeg",
 					Size:        3500000,
+					Owner:       "user-42",
+					Visibility:  domain.VisibilityShared,
 					Tags: []domain.Tag{
 						{
 							ID:          uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"),
@@ -86,6 +90,8 @@ func TestHandlePostMedia(t *testing.T) {
 				assert.Equal(t, "reserved", response.Data.Status)
 				assert.Equal(t, "image", response.Data.Type)
 				assert.Equal(t, "http://localhost:8080/upload/p3n4lty/penalty-kick.jpg", response.Data.URL)
+				assert.Equal(t, "user-42", response.Data.Owner)
+				assert.Equal(t, "shared", response.Data.Visibility)
 				assert.Len(t, response.Data.Tags, 2)
 			},
 		},
@@ -33,7 +33,7 @@ func HandlePostTags(tc TagCreator) func(http.ResponseWriter, *http.Request) {
 			return
 		}
 
-		rw.Header().Set("Location", BasePath+"/"+createdTag.ID.String())
+		rw.Header().Set("Location", BasePath+"/tags/"+createdTag.ID.String())
 
 		resp := createTagResponse{
 			Data: tagData{
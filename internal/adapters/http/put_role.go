@@ -0,0 +1,40 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/peano88/medias/internal/domain"
+)
+
+type RoleAssigner interface {
+	Execute(context.Context, domain.RoleAssignment) (domain.RoleAssignment, error)
+}
+
+// HandlePutRole assigns a role to a principal, replacing the role it held in
+// the same tenant
+func HandlePutRole(ra RoleAssigner) func(http.ResponseWriter, *http.Request) {
+	return func(rw http.ResponseWriter, r *http.Request) {
+
+		req, err := JSONIn[assignRoleRequest](rw, r)
+		if err != nil {
+			return
+		}
+
+		assignment := domain.RoleAssignment{
+			Subject: chi.URLParam(r, "subject"),
+			Role:    domain.Role(req.Role),
+			Tenant:  req.Tenant,
+		}
+
+		// Execute business logic
+		assigned, err := ra.Execute(r.Context(), assignment)
+		if err != nil {
+			handleExecutorError(r.Context(), rw, err)
+			return
+		}
+
+		JSONOut(rw, http.StatusOK, roleAssignmentResponse{Data: buildRoleAssignmentData(assigned)})
+	}
+}
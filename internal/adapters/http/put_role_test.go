@@ -0,0 +1,113 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_role_assigner.go -package=mocks github.com/peano88/medias/internal/adapters/http RoleAssigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandlePutRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestBody any
+		setupMock   func(*mocks.MockRoleAssigner)
+		validate    func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "success",
+			requestBody: assignRoleRequest{Role: "editor", Tenant: "acme"},
+			setupMock: func(ra *mocks.MockRoleAssigner) {
+				ra.EXPECT().
+					Execute(gomock.Any(), domain.RoleAssignment{Subject: "api-key:uploader", Role: domain.RoleEditor, Tenant: "acme"}).
+					Return(domain.RoleAssignment{
+						Subject:   "api-key:uploader",
+						Role:      domain.RoleEditor,
+						Tenant:    "acme",
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+
+				var response roleAssignmentResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "api-key:uploader", response.Data.Subject)
+				assert.Equal(t, "editor", response.Data.Role)
+				assert.Equal(t, "acme", response.Data.Tenant)
+			},
+		},
+		{
+			name:        "error - unknown role",
+			requestBody: assignRoleRequest{Role: "owner"},
+			setupMock: func(ra *mocks.MockRoleAssigner) {
+				ra.EXPECT().
+					Execute(gomock.Any(), gomock.Any()).
+					Return(domain.RoleAssignment{}, domain.NewError(domain.InvalidEntityCode, domain.WithMessage("invalid role")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+			},
+		},
+		{
+			name:        "error - another tenant",
+			requestBody: assignRoleRequest{Role: "admin", Tenant: "globex"},
+			setupMock: func(ra *mocks.MockRoleAssigner) {
+				ra.EXPECT().
+					Execute(gomock.Any(), gomock.Any()).
+					Return(domain.RoleAssignment{}, domain.NewError(domain.ForbiddenCode, domain.WithMessage("tenant not allowed")))
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, rec.Code)
+			},
+		},
+		{
+			name:        "error - invalid JSON",
+			requestBody: "{",
+			setupMock:   func(ra *mocks.MockRoleAssigner) {},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			assigner := mocks.NewMockRoleAssigner(ctrl)
+			tt.setupMock(assigner)
+
+			var body []byte
+			if s, ok := tt.requestBody.(string); ok {
+				body = []byte(s)
+			} else {
+				body, _ = json.Marshal(tt.requestBody)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/api-key:uploader", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("subject", "api-key:uploader")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rec := httptest.NewRecorder()
+
+			HandlePutRole(assigner)(rec, req)
+
+			tt.validate(t, rec)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/peano88/medias/internal/domain"
+)
+
+type RateLimiter interface {
+	// Allow takes a token from the bucket of the client for the route, a
+	// method and a pattern relative to BasePath such as "POST /media"
+	Allow(ctx context.Context, route, client string) (domain.RateLimitDecision, error)
+	// AllowIP takes a token from the bucket of the IP address, shared by
+	// every route
+	AllowIP(ctx context.Context, ip string) (domain.RateLimitDecision, error)
+}
+
+// ipRateLimitMiddleware limits the requests of each IP address to the whole
+// API, rejecting the excess with 429. Applied before the authentication, it
+// keeps a flood of invalid credentials from reaching the authenticators. Like
+// rateLimitMiddleware, it fails open and is disabled when there is no rate
+// limiter.
+func ipRateLimitMiddleware(deps Dependencies) middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		if deps.RateLimiter == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			decision, err := deps.RateLimiter.AllowIP(r.Context(), clientIP(r, deps.TrustedProxies))
+			if limited(w, r, deps, decision, err) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitMiddleware limits the requests of each client to each route,
+// rejecting the excess with 429. Clients are identified by their principal
+// when authenticated, by their IP address otherwise. The rate limiting fails
+// open: requests go through when the limiter fails. It is disabled when there
+// is no rate limiter.
+//
+// The middleware must be applied to routes, i.e. within a group, for the
+// route pattern to be resolved.
+func rateLimitMiddleware(deps Dependencies) middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		if deps.RateLimiter == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			decision, err := deps.RateLimiter.Allow(r.Context(), rateLimitRoute(r), rateLimitClient(r, deps.TrustedProxies))
+			if limited(w, r, deps, decision, err) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limited sets the rate limit headers from the decision and rejects the
+// request when it is not allowed, reporting whether it did. A failure of the
+// limiter lets the request through.
+func limited(w http.ResponseWriter, r *http.Request, deps Dependencies, decision domain.RateLimitDecision, err error) bool {
+	if err != nil {
+		deps.Logger.Error("rate limiting failed, letting the request through",
+			slog.String("error", err.Error()))
+		return false
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(decision.Reset)))
+
+	if !decision.Allowed {
+		retryAfter := seconds(decision.RetryAfter)
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		errDetails := "retry in " + strconv.Itoa(retryAfter) + " seconds"
+		respondWithError(r.Context(), w, http.StatusTooManyRequests, domain.RateLimitedCode,
+			"Too many requests", &errDetails, nil)
+		return true
+	}
+	return false
+}
+
+// rateLimitRoute is the method and the pattern of the route of the request,
+// relative to BasePath
+func rateLimitRoute(r *http.Request) string {
+	pattern := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		pattern = rctx.RoutePattern()
+	}
+	return r.Method + " " + strings.TrimPrefix(pattern, BasePath)
+}
+
+// rateLimitClient identifies the client of the request
+func rateLimitClient(r *http.Request, trustedProxies []netip.Prefix) string {
+	if principal, ok := domain.PrincipalFromContext(r.Context()); ok {
+		return "principal:" + principal.Subject
+	}
+	return "ip:" + clientIP(r, trustedProxies)
+}
+
+// clientIP is the IP address of the client of the request. The headers set by
+// proxies are only believed when the peer is a trusted proxy: the client is
+// then the right-most address of X-Forwarded-For which is not a trusted proxy,
+// or X-Real-IP without X-Forwarded-For. Any other client could forge them to
+// pick its bucket.
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer, err := netip.ParseAddr(host)
+	if err != nil || !trusted(peer, trustedProxies) {
+		return host
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				// A malformed hop ends the chain, the last proxy read is the client
+				break
+			}
+			client = hop
+			if !trusted(hop, trustedProxies) {
+				break
+			}
+		}
+		return client.Unmap().String()
+	}
+
+	if realIP, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return realIP.Unmap().String()
+	}
+	return host
+}
+
+// trusted reports whether addr belongs to one of the trusted proxies
+func trusted(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// seconds rounds d up to whole seconds, as the headers require
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
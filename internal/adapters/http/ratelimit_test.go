@@ -0,0 +1,262 @@
+package http
+
+//go:generate mockgen -destination=mocks/mock_rate_limiter.go -package=mocks github.com/peano88/medias/internal/adapters/http RateLimiter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/peano88/medias/internal/adapters/http/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		principal     *domain.Principal
+		setupMock     func(*mocks.MockRateLimiter)
+		expectHandler bool
+		validate      func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "allowed - client identified by its address",
+			setupMock: func(l *mocks.MockRateLimiter) {
+				l.EXPECT().
+					Allow(gomock.Any(), "GET /media/{id}", "ip:192.0.2.1").
+					Return(domain.RateLimitDecision{Allowed: true, Limit: 10, Remaining: 9, Reset: 500 * time.Millisecond}, nil)
+			},
+			expectHandler: true,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "10", rec.Header().Get("RateLimit-Limit"))
+				assert.Equal(t, "9", rec.Header().Get("RateLimit-Remaining"))
+				assert.Equal(t, "1", rec.Header().Get("RateLimit-Reset"))
+				assert.Empty(t, rec.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name:      "allowed - client identified by its principal",
+			principal: &domain.Principal{Subject: "uploader"},
+			setupMock: func(l *mocks.MockRateLimiter) {
+				l.EXPECT().
+					Allow(gomock.Any(), "GET /media/{id}", "principal:uploader").
+					Return(domain.RateLimitDecision{Allowed: true, Limit: 10, Remaining: 3}, nil)
+			},
+			expectHandler: true,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "3", rec.Header().Get("RateLimit-Remaining"))
+			},
+		},
+		{
+			name: "limited",
+			setupMock: func(l *mocks.MockRateLimiter) {
+				l.EXPECT().
+					Allow(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.RateLimitDecision{
+						Limit:      10,
+						RetryAfter: 1200 * time.Millisecond,
+						Reset:      5 * time.Second,
+					}, nil)
+			},
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+				assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+				assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+				assert.Equal(t, "5", rec.Header().Get("RateLimit-Reset"))
+				assertErrorCode(t, rec, domain.RateLimitedCode)
+			},
+		},
+		{
+			name: "limiter failure - fails open",
+			setupMock: func(l *mocks.MockRateLimiter) {
+				l.EXPECT().
+					Allow(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(domain.RateLimitDecision{}, errors.New("connection refused"))
+			},
+			expectHandler: true,
+			validate: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			limiter := mocks.NewMockRateLimiter(ctrl)
+			tt.setupMock(limiter)
+
+			deps := Dependencies{
+				RateLimiter: limiter,
+				Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+
+			called := false
+			api := chi.NewRouter()
+			api.Group(func(r chi.Router) {
+				r.Use(rateLimitMiddleware(deps))
+				r.Get("/media/{id}", func(w http.ResponseWriter, r *http.Request) {
+					called = true
+				})
+			})
+			r := chi.NewRouter()
+			r.Mount(BasePath, api)
+
+			req := httptest.NewRequest(http.MethodGet, BasePath+"/media/42", nil)
+			req.RemoteAddr = "192.0.2.1:51234"
+			if tt.principal != nil {
+				req = req.WithContext(domain.ContextWithPrincipal(req.Context(), *tt.principal))
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectHandler, called)
+			tt.validate(t, rec)
+		})
+	}
+}
+
+func TestNewRouter_InvalidCredentialsRateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const burst = 2
+	taken := 0
+	limiter := mocks.NewMockRateLimiter(ctrl)
+	limiter.EXPECT().
+		AllowIP(gomock.Any(), "192.0.2.1").
+		DoAndReturn(func(context.Context, string) (domain.RateLimitDecision, error) {
+			taken++
+			return domain.RateLimitDecision{
+				Allowed:    taken <= burst,
+				Limit:      burst,
+				Remaining:  max(burst-taken, 0),
+				RetryAfter: time.Second,
+			}, nil
+		}).
+		Times(burst + 2)
+
+	// the requests over the limit never reach the authenticator
+	authenticator := mocks.NewMockAuthenticator(ctrl)
+	authenticator.EXPECT().
+		Execute(gomock.Any(), "bogus").
+		Return(domain.Principal{}, domain.NewError(domain.UnauthenticatedCode, domain.WithMessage("invalid API key"))).
+		Times(burst)
+
+	metrics := mocks.NewMockMetricsForwarder(ctrl)
+	metrics.EXPECT().AddRequestHit(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	r := NewRouter(Dependencies{
+		Authenticator:   authenticator,
+		RateLimiter:     limiter,
+		Logger:          slog.New(slog.DiscardHandler),
+		MetricForwarder: metrics,
+	})
+
+	for i := range burst + 2 {
+		req := httptest.NewRequest(http.MethodGet, BasePath+"/tags", nil)
+		req.RemoteAddr = "192.0.2.1:51234"
+		req.Header.Set(APIKeyHeader, "bogus")
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+
+		if i < burst {
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			continue
+		}
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+		assertErrorCode(t, rec, domain.RateLimitedCode)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trustedProxies := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string][]string
+		want       string
+	}{
+		{
+			name:       "direct client",
+			remoteAddr: "192.0.2.1:51234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "direct client - forwarded headers ignored",
+			remoteAddr: "192.0.2.1:51234",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.7"}, "X-Real-IP": {"198.51.100.8"}},
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "trusted proxy - X-Forwarded-For",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.7"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "trusted proxies - right-most untrusted address",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"203.0.113.9, 198.51.100.7", "10.1.2.3"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "trusted proxies only - left-most address",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"10.1.2.3, 10.4.5.6"}},
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "trusted proxy - malformed hop",
+			remoteAddr: "10.0.0.2:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.7, bogus, 10.1.2.3"}},
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "trusted proxy - X-Real-IP",
+			remoteAddr: "[2001:db8::1]:443",
+			headers:    map[string][]string{"X-Real-IP": {"198.51.100.8"}},
+			want:       "198.51.100.8",
+		},
+		{
+			name:       "trusted proxy - no forwarded headers",
+			remoteAddr: "10.0.0.2:443",
+			want:       "10.0.0.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, BasePath+"/tags", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, values := range tt.headers {
+				for _, value := range values {
+					req.Header.Add(name, value)
+				}
+			}
+
+			assert.Equal(t, tt.want, clientIP(req, trustedProxies))
+		})
+	}
+}
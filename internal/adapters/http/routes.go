@@ -1,31 +1,54 @@
 package http
 
 import (
-	"expvar"
 	"log/slog"
-	"net/http"
+	"net/netip"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimdw "github.com/go-chi/chi/v5/middleware"
+	"github.com/peano88/medias/internal/domain"
 )
 
 const BasePath = "/api/v1"
 
 type Dependencies struct {
-	TagCreator      TagCreator
-	TagRetriever    TagRetriever
-	MediaCreator    MediaCreator
-	MediaFinalizer  MediaFinalizer
-	MediaRetriever  MediaRetriever
+	TagCreator          TagCreator
+	TagRetriever        TagRetriever
+	SingleTagRetriever  SingleTagRetriever
+	TagUpdater          TagUpdater
+	RelatedTagRetriever RelatedTagRetriever
+	MediaCreator        MediaCreator
+	MediaBatchCreator   MediaBatchCreator
+	MediaFinalizer      MediaFinalizer
+	MediaBatchFinalizer MediaBatchFinalizer
+	MediaRetriever      MediaRetriever
+	MediaUpdater        MediaUpdater
+	MediaSearcher       MediaSearcher
+	MediaLister         MediaLister
+	APIKeyCreator       APIKeyCreator
+	APIKeyLister        APIKeyLister
+	APIKeyRevoker       APIKeyRevoker
+	Authenticator       Authenticator
+	TokenAuthenticator  TokenAuthenticator
+	Authorizer          domain.Authorizer
+	RoleAssigner        RoleAssigner
+	RoleLister          RoleLister
+	RoleUnassigner      RoleUnassigner
+	AuditRecorder       AuditRecorder
+	IdempotencyStore    IdempotencyStore
+	IdempotencyTTL      time.Duration
+	RateLimiter         RateLimiter
+	// TrustedProxies are the proxies whose X-Forwarded-For and X-Real-IP
+	// headers identify the client of a request, none by default
+	TrustedProxies  []netip.Prefix
 	Logger          *slog.Logger
 	MetricForwarder MetricsForwarder
 }
 
 func NewRouter(deps Dependencies) chi.Router {
 	r := chi.NewRouter()
-
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
-	r.Get("/debug/vars", expvar.Handler().ServeHTTP)
+	r.Use(tracingMiddleware())
 
 	apiRouter := chi.NewRouter()
 
@@ -35,13 +58,60 @@ func NewRouter(deps Dependencies) chi.Router {
 		chimdw.Recoverer,
 		loggerMiddleware(deps),
 		loggerRequestIDMiddleware(),
+		loggerTraceMiddleware(),
+		ipRateLimitMiddleware(deps),
+		authenticationMiddleware(deps),
+		tenantMiddleware(deps),
 	)
 
-	apiRouter.Post("/tags", HandlePostTags(deps.TagCreator))
-	apiRouter.Get("/tags", HandleGetTags(deps.TagRetriever))
-	apiRouter.Post("/media", HandlePostMedia(deps.MediaCreator))
-	apiRouter.Get("/media/{id}", HandleGetMedia(deps.MediaRetriever))
-	apiRouter.Post("/media/{id}/finalize", HandlePostFinalizeMedia(deps.MediaFinalizer))
+	// POST operations are not idempotent by nature, clients may make them so
+	idempotent := idempotencyMiddleware(deps)
+	// applied within the groups, once the route and the principal are known
+	rateLimited := rateLimitMiddleware(deps)
+
+	apiRouter.Group(func(r chi.Router) {
+		r.Use(rateLimited, requireScope(deps, domain.ScopeTagsRead, domain.ActionReadTags), authorize(deps, domain.ActionReadTags))
+		r.Get("/tags", HandleGetTags(deps.TagRetriever))
+		r.Get("/tags/{id}", HandleGetTag(deps.SingleTagRetriever))
+		r.Get("/tags/{name}/related", HandleGetRelatedTags(deps.RelatedTagRetriever))
+	})
+
+	apiRouter.Group(func(r chi.Router) {
+		r.Use(rateLimited, requireScope(deps, domain.ScopeTagsWrite, domain.ActionWriteTags), authorize(deps, domain.ActionWriteTags))
+		r.With(idempotent).Post("/tags", HandlePostTags(deps.TagCreator))
+		r.Patch("/tags/{id}", HandlePatchTag(deps.TagUpdater))
+	})
+
+	apiRouter.Group(func(r chi.Router) {
+		r.Use(rateLimited, requireScope(deps, domain.ScopeMediaRead, domain.ActionReadMedia), authorize(deps, domain.ActionReadMedia))
+		r.Get("/media", HandleGetMediaList(deps.MediaLister))
+		r.Get("/media/search", HandleSearchMedia(deps.MediaSearcher))
+		r.Get("/media/{id}", HandleGetMedia(deps.MediaRetriever))
+	})
+
+	apiRouter.Group(func(r chi.Router) {
+		r.Use(rateLimited, requireScope(deps, domain.ScopeMediaWrite, domain.ActionWriteMedia), authorize(deps, domain.ActionWriteMedia))
+		r.With(idempotent).Post("/media", HandlePostMedia(deps.MediaCreator))
+		r.With(idempotent).Post("/media:batch", HandlePostMediaBatch(deps.MediaBatchCreator))
+		r.With(idempotent).Post("/media/finalize:batch", HandlePostFinalizeMediaBatch(deps.MediaBatchFinalizer))
+		r.Patch("/media/{id}", HandlePatchMedia(deps.MediaUpdater))
+		r.With(idempotent).Post("/media/{id}/finalize", HandlePostFinalizeMedia(deps.MediaFinalizer))
+	})
+
+	apiRouter.Group(func(r chi.Router) {
+		r.Use(rateLimited, requireScope(deps, domain.ScopeAdmin, domain.ActionManageAPIKeys), authorize(deps, domain.ActionManageAPIKeys))
+		// not idempotent: the stored response would hold the key in clear
+		r.Post("/admin/api-keys", HandlePostAPIKeys(deps.APIKeyCreator))
+		r.Get("/admin/api-keys", HandleGetAPIKeys(deps.APIKeyLister))
+		r.Delete("/admin/api-keys/{id}", HandleDeleteAPIKey(deps.APIKeyRevoker))
+	})
+
+	apiRouter.Group(func(r chi.Router) {
+		r.Use(rateLimited, requireScope(deps, domain.ScopeAdmin, domain.ActionManageRoles), authorize(deps, domain.ActionManageRoles))
+		r.Get("/admin/roles", HandleGetRoles(deps.RoleLister))
+		r.Put("/admin/roles/{subject}", HandlePutRole(deps.RoleAssigner))
+		r.Delete("/admin/roles/{subject}", HandleDeleteRole(deps.RoleUnassigner))
+	})
 
 	r.Mount(BasePath, apiRouter)
 	return r
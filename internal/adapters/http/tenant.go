@@ -0,0 +1,55 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/httplog/v3"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// TenantHeader is the header naming the tenant a request acts on
+const TenantHeader = "X-Tenant-ID"
+
+// tenantMiddleware resolves the tenant of the request: the one of the
+// TenantHeader header, by default the tenant of the principal. A principal can
+// only name another tenant when it was granted domain.ScopeAllTenants. Without
+// principal, i.e. when authentication is disabled, the default tenant is
+// domain.DefaultTenant. The requests naming a tenant they cannot act on are
+// written to the audit trail.
+func tenantMiddleware(deps Dependencies) middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested := r.Header.Get(TenantHeader)
+			if requested != "" && domain.ValidateTenant(requested) != nil {
+				errDetails := "tenant must be up to 63 lowercase letters, digits and dashes"
+				respondWithError(r.Context(), w, http.StatusBadRequest, "INVALID_REQUEST",
+					"Invalid "+TenantHeader+" header", &errDetails, nil)
+				return
+			}
+
+			tenant := requested
+			if principal, ok := domain.PrincipalFromContext(r.Context()); ok {
+				if tenant == "" {
+					tenant = principal.Tenant
+				}
+				if !principal.CanActOn(tenant) {
+					errDetails := "the credentials are bound to another tenant"
+					if err := recordDenial(r.Context(), deps, tenant, domain.ActionAccessTenant, errDetails); err != nil {
+						handleExecutorError(r.Context(), w, err)
+						return
+					}
+					respondWithError(r.Context(), w, http.StatusForbidden, domain.ForbiddenCode,
+						"Tenant not allowed", &errDetails, nil)
+					return
+				}
+			}
+			if tenant == "" {
+				tenant = domain.DefaultTenant
+			}
+
+			httplog.SetAttrs(r.Context(), slog.String("tenant", tenant))
+			next.ServeHTTP(w, r.WithContext(domain.ContextWithTenant(r.Context(), tenant)))
+		})
+	}
+}
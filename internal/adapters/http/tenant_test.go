@@ -0,0 +1,115 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *domain.Principal
+		header         string
+		expectedStatus int
+		expectedTenant string
+		expectedCode   string
+	}{
+		{
+			name:           "default tenant without header",
+			expectedStatus: http.StatusOK,
+			expectedTenant: domain.DefaultTenant,
+		},
+		{
+			name:           "tenant of the header",
+			header:         "acme",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		{
+			name:           "principal of the default tenant cannot name another one",
+			principal:      &domain.Principal{Subject: "admin", Tenant: domain.DefaultTenant},
+			header:         "acme",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   domain.ForbiddenCode,
+		},
+		{
+			name:           "principal without tenant cannot name one",
+			principal:      &domain.Principal{Subject: "admin"},
+			header:         "acme",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   domain.ForbiddenCode,
+		},
+		{
+			name:           "principal granted every tenant may name one",
+			principal:      &domain.Principal{Subject: "admin", Scopes: []domain.Scope{domain.ScopeAllTenants}, Tenant: domain.DefaultTenant},
+			header:         "acme",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		{
+			name:           "principal granted every tenant acts on its tenant by default",
+			principal:      &domain.Principal{Subject: "admin", Scopes: []domain.Scope{domain.ScopeAllTenants}, Tenant: "acme"},
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		{
+			name:           "tenant of the principal",
+			principal:      &domain.Principal{Subject: "user-42", Tenant: "acme"},
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		{
+			name:           "principal naming its own tenant",
+			principal:      &domain.Principal{Subject: "user-42", Tenant: "acme"},
+			header:         "acme",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "acme",
+		},
+		{
+			name:           "principal naming another tenant",
+			principal:      &domain.Principal{Subject: "user-42", Tenant: "acme"},
+			header:         "globex",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   domain.ForbiddenCode,
+		},
+		{
+			name:           "invalid header",
+			header:         "../acme",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "INVALID_REQUEST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tenant string
+			r := chi.NewRouter()
+			r.Use(tenantMiddleware(Dependencies{}))
+			r.Get("/media", func(w http.ResponseWriter, r *http.Request) {
+				tenant = domain.TenantFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/media", nil)
+			if tt.principal != nil {
+				req = req.WithContext(domain.ContextWithPrincipal(req.Context(), *tt.principal))
+			}
+			if tt.header != "" {
+				req.Header.Set(TenantHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedTenant, tenant)
+			if tt.expectedCode != "" {
+				assertErrorCode(t, rec, tt.expectedCode)
+			}
+		})
+	}
+}
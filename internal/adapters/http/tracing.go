@@ -0,0 +1,75 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/httplog/v3"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingMiddleware starts a span for each API request, continuing the trace
+// of the W3C traceparent header when present. The span is named after the
+// route once it is resolved. The tracer provider and the propagator are the global
+// ones: spans are only recorded when tracing is enabled.
+func tracingMiddleware() middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			if pattern := routePattern(r); pattern != "" {
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(spanName(r))
+				span.SetAttributes(attribute.String("http.route", pattern))
+			}
+		})
+
+		return otelhttp.NewHandler(named, "http.server",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return spanName(r)
+			}),
+			// probes and metrics scrapes are not worth a trace
+			otelhttp.WithFilter(func(r *http.Request) bool {
+				return strings.HasPrefix(r.URL.Path, BasePath)
+			}),
+		)
+	}
+}
+
+// spanName is the method and the route pattern of the request, only the method
+// until the route is resolved
+func spanName(r *http.Request) string {
+	if pattern := routePattern(r); pattern != "" {
+		return r.Method + " " + pattern
+	}
+	return r.Method
+}
+
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
+
+// loggerTraceMiddleware adds the ids of the trace and of the span of the
+// request to its log line
+func loggerTraceMiddleware() middlewarehandler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.HasTraceID() {
+				httplog.SetAttrs(r.Context(),
+					slog.String("trace_id", spanContext.TraceID().String()),
+					slog.String("span_id", spanContext.SpanID().String()),
+				)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
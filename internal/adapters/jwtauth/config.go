@@ -0,0 +1,77 @@
+package jwtauth
+
+import (
+	"net/url"
+
+	"github.com/peano88/medias/config"
+)
+
+// Config holds the configuration of the bearer token verification
+type Config struct {
+	// JWKSFile is the path of the JWKS file, reloaded whenever it changes
+	JWKSFile string `mapstructure:"jwks-file"`
+	// JWKSURL is where the JWKS is fetched from when no file is configured
+	JWKSURL string `mapstructure:"jwks-url"`
+	// JWKSRefreshSeconds is the interval between two fetches of JWKSURL
+	JWKSRefreshSeconds int    `mapstructure:"jwks-refresh-seconds"`
+	Issuer             string `mapstructure:"issuer"`
+	Audience           string `mapstructure:"audience"`
+	// ScopeClaim is the claim holding the scopes of the token, either as a
+	// space separated string or as an array of strings
+	ScopeClaim string `mapstructure:"scope-claim"`
+	// TenantClaim is the claim holding the tenant the token is bound to
+	TenantClaim string `mapstructure:"tenant-claim"`
+	// LeewaySeconds is the clock skew tolerated on the time based claims
+	LeewaySeconds int `mapstructure:"leeway-seconds"`
+}
+
+func SetDefaultConfig(loader config.ConfigLoader, prefix string) {
+	// Defaults on the optional keys let them be set from the environment
+	loader.SetDefault(prefix+".jwks-file", "")
+	loader.SetDefault(prefix+".jwks-url", "")
+	loader.SetDefault(prefix+".issuer", "")
+	loader.SetDefault(prefix+".audience", "")
+	loader.SetDefault(prefix+".jwks-refresh-seconds", 300)
+	loader.SetDefault(prefix+".scope-claim", "scope")
+	loader.SetDefault(prefix+".tenant-claim", "tenant")
+	loader.SetDefault(prefix+".leeway-seconds", 30)
+}
+
+// Enabled reports whether bearer tokens are accepted, which requires a JWKS
+func (c *Config) Enabled() bool {
+	return c.JWKSFile != "" || c.JWKSURL != ""
+}
+
+// Validate reports all the problems of the configuration, with their keys.
+// Nothing is required when bearer tokens are not accepted.
+func (c *Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	var errs []error
+	if c.JWKSFile == "" {
+		if u, err := url.Parse(c.JWKSURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, config.Invalid("jwks-url", "must be an http or https URL, got %q", c.JWKSURL))
+		}
+	}
+	if c.JWKSRefreshSeconds < 0 {
+		errs = append(errs, config.Invalid("jwks-refresh-seconds", "must not be negative, got %d", c.JWKSRefreshSeconds))
+	}
+	if c.Issuer == "" {
+		errs = append(errs, config.Invalid("issuer", "is required to accept bearer tokens"))
+	}
+	if c.Audience == "" {
+		errs = append(errs, config.Invalid("audience", "is required to accept bearer tokens"))
+	}
+	if c.ScopeClaim == "" {
+		errs = append(errs, config.Invalid("scope-claim", "must not be empty"))
+	}
+	if c.TenantClaim == "" {
+		errs = append(errs, config.Invalid("tenant-claim", "must not be empty"))
+	}
+	if c.LeewaySeconds < 0 {
+		errs = append(errs, config.Invalid("leeway-seconds", "must not be negative, got %d", c.LeewaySeconds))
+	}
+	return config.Collect("", errs...)
+}
@@ -0,0 +1,86 @@
+package jwtauth
+
+import (
+	"testing"
+
+	"github.com/peano88/medias/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Config{
+		JWKSURL:            "https://idp.example.com/.well-known/jwks.json",
+		JWKSRefreshSeconds: 300,
+		Issuer:             "https://idp.example.com",
+		Audience:           "medias",
+		ScopeClaim:         "scope",
+		TenantClaim:        "tenant",
+		LeewaySeconds:      30,
+	}
+
+	tests := []struct {
+		name     string
+		cfg      func(Config) Config
+		wantKeys []string
+	}{
+		{
+			name: "valid",
+			cfg:  func(c Config) Config { return c },
+		},
+		{
+			name: "disabled",
+			cfg:  func(Config) Config { return Config{} },
+		},
+		{
+			name: "file",
+			cfg: func(c Config) Config {
+				c.JWKSFile, c.JWKSURL = "/etc/medias/jwks.json", ""
+				return c
+			},
+		},
+		{
+			name: "invalid URL",
+			cfg: func(c Config) Config {
+				c.JWKSURL = "idp.example.com/jwks.json"
+				return c
+			},
+			wantKeys: []string{"jwks-url"},
+		},
+		{
+			name: "issuer and audience missing",
+			cfg: func(c Config) Config {
+				c.Issuer, c.Audience = "", ""
+				return c
+			},
+			wantKeys: []string{"issuer", "audience"},
+		},
+		{
+			name: "invalid claims and durations",
+			cfg: func(c Config) Config {
+				c.JWKSRefreshSeconds, c.ScopeClaim, c.TenantClaim, c.LeewaySeconds = -1, "", "", -5
+				return c
+			},
+			wantKeys: []string{"jwks-refresh-seconds", "scope-claim", "tenant-claim", "leeway-seconds"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg(valid)
+			err := cfg.Validate()
+			if len(tt.wantKeys) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErrs config.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+			keys := make([]string, len(validationErrs))
+			for i, fieldErr := range validationErrs {
+				keys[i] = fieldErr.Key
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
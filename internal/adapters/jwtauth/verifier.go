@@ -0,0 +1,252 @@
+package jwtauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/peano88/medias/internal/domain"
+)
+
+const (
+	// maxJWKSSize bounds the size of a fetched JWKS
+	maxJWKSSize  = 1 << 20
+	fetchTimeout = 10 * time.Second
+)
+
+// signingMethods are the accepted signature algorithms. Symmetric algorithms
+// and "none" are excluded: tokens are signed by the gateway private keys.
+var signingMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Verifier verifies the JWTs issued by the gateway against its JWKS
+type Verifier struct {
+	cfg    Config
+	parser *jwt.Parser
+	keys   atomic.Pointer[keySet]
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewVerifier creates a verifier and loads the JWKS. The JWKS is then
+// reloaded whenever its file changes, or fetched periodically from its URL,
+// until ctx is done.
+func NewVerifier(ctx context.Context, cfg Config, logger *slog.Logger) (*Verifier, error) {
+	if !cfg.Enabled() {
+		return nil, errors.New("either a JWKS file or a JWKS URL is required")
+	}
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("issuer and audience are required")
+	}
+
+	v := &Verifier{
+		cfg: cfg,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(signingMethods),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(time.Duration(cfg.LeewaySeconds)*time.Second),
+		),
+		client: &http.Client{Timeout: fetchTimeout},
+		logger: logger,
+	}
+
+	if err := v.load(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load JWKS: %w", err)
+	}
+
+	if cfg.JWKSFile != "" {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch JWKS file: %w", err)
+		}
+		// Watching the directory catches files replaced by a rename, as
+		// editors and mounted secrets do
+		if err := watcher.Add(filepath.Dir(cfg.JWKSFile)); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("failed to watch JWKS file: %w", err)
+		}
+		go v.watchFile(ctx, watcher)
+	} else if cfg.JWKSRefreshSeconds > 0 {
+		go v.refreshPeriodically(ctx, time.Duration(cfg.JWKSRefreshSeconds)*time.Second)
+	}
+
+	logger.Info("JWT verification enabled",
+		slog.String("issuer", cfg.Issuer),
+		slog.String("audience", cfg.Audience),
+		slog.Int("keys", len(*v.keys.Load())),
+	)
+
+	return v, nil
+}
+
+// Verify checks the signature, expiry, issuer and audience of the token and
+// returns its subject, scopes and tenant
+func (v *Verifier) Verify(_ context.Context, token string) (domain.TokenClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		return domain.TokenClaims{}, domain.NewError(domain.UnauthenticatedCode,
+			domain.WithMessage("invalid token"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	subject, _ := claims.GetSubject()
+	tenant, _ := claims[v.cfg.TenantClaim].(string)
+	return domain.TokenClaims{
+		Subject: subject,
+		Scopes:  scopesFromClaim(claims[v.cfg.ScopeClaim]),
+		Tenant:  tenant,
+	}, nil
+}
+
+// keyFunc selects the key the token was signed with. A token without key id
+// is only accepted when the JWKS holds a single key.
+func (v *Verifier) keyFunc(token *jwt.Token) (any, error) {
+	keys := *v.keys.Load()
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// scopesFromClaim reads a space separated string or an array of strings
+func scopesFromClaim(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		scopes := make([]string, 0, len(value))
+		for _, scope := range value {
+			if s, ok := scope.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes
+	default:
+		return nil
+	}
+}
+
+// load reads the JWKS and replaces the keys in use
+func (v *Verifier) load(ctx context.Context) error {
+	var data []byte
+	var err error
+	if v.cfg.JWKSFile != "" {
+		data, err = os.ReadFile(v.cfg.JWKSFile)
+	} else {
+		data, err = v.fetch(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	v.keys.Store(&keys)
+	return nil
+}
+
+func (v *Verifier) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+// reload loads the JWKS again. On failure, the keys in use are kept: a file
+// may be caught while being written, it is reloaded on the next change.
+func (v *Verifier) reload(ctx context.Context) {
+	if err := v.load(ctx); err != nil {
+		v.logger.Error("failed to reload JWKS, keeping the current keys",
+			slog.String("error", err.Error()))
+		return
+	}
+	v.logger.Info("JWKS reloaded", slog.Int("keys", len(*v.keys.Load())))
+}
+
+func (v *Verifier) watchFile(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	file := filepath.Clean(v.cfg.JWKSFile)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			// Kubernetes swaps the ..data symlink when a mounted secret changes
+			if filepath.Clean(event.Name) != file && !strings.HasPrefix(filepath.Base(event.Name), "..") {
+				continue
+			}
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+				v.reload(ctx)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			v.logger.Error("JWKS file watcher error", slog.String("error", err.Error()))
+		}
+	}
+}
+
+func (v *Verifier) refreshPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			v.reload(ctx)
+		}
+	}
+}
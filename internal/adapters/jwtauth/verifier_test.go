@@ -0,0 +1,319 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://gateway.example.com"
+	testAudience = "medias"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	file := writeJWKS(t, t.TempDir(), map[string]crypto.PublicKey{
+		"rsa": rsaKey.Public(),
+		"ec":  ecKey.Public(),
+		"ed":  edKey.Public(),
+	})
+	verifier := newTestVerifier(t, Config{JWKSFile: file})
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":   "user-42",
+			"iss":   testIssuer,
+			"aud":   testAudience,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"scope": "media:read tags:read",
+		}
+	}
+
+	tests := []struct {
+		name     string
+		token    func() string
+		validate func(*testing.T, domain.TokenClaims, error)
+	}{
+		{
+			name:  "success - RSA",
+			token: func() string { return sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, validClaims()) },
+			validate: func(t *testing.T, claims domain.TokenClaims, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-42", claims.Subject)
+				assert.Equal(t, []string{"media:read", "tags:read"}, claims.Scopes)
+				assert.Empty(t, claims.Tenant)
+			},
+		},
+		{
+			name: "success - token bound to a tenant",
+			token: func() string {
+				claims := validClaims()
+				claims["tenant"] = "acme"
+				return sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			validate: func(t *testing.T, claims domain.TokenClaims, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "acme", claims.Tenant)
+			},
+		},
+		{
+			name:  "success - ECDSA",
+			token: func() string { return sign(t, jwt.SigningMethodES256, "ec", ecKey, validClaims()) },
+			validate: func(t *testing.T, claims domain.TokenClaims, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "success - EdDSA with scopes as an array",
+			token: func() string {
+				claims := validClaims()
+				claims["scope"] = []string{"media:write"}
+				return sign(t, jwt.SigningMethodEdDSA, "ed", edKey, claims)
+			},
+			validate: func(t *testing.T, claims domain.TokenClaims, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"media:write"}, claims.Scopes)
+			},
+		},
+		{
+			name: "error - expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+				return sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			validate: assertUnauthenticated,
+		},
+		{
+			name: "error - no expiry",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			validate: assertUnauthenticated,
+		},
+		{
+			name: "error - wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "billing"
+				return sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			validate: assertUnauthenticated,
+		},
+		{
+			name: "error - wrong issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://evil.example.com"
+				return sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+			},
+			validate: assertUnauthenticated,
+		},
+		{
+			name:     "error - signed by an unknown key",
+			token:    func() string { return sign(t, jwt.SigningMethodRS256, "rsa", otherKey, validClaims()) },
+			validate: assertUnauthenticated,
+		},
+		{
+			name:     "error - unknown key id",
+			token:    func() string { return sign(t, jwt.SigningMethodRS256, "other", otherKey, validClaims()) },
+			validate: assertUnauthenticated,
+		},
+		{
+			name:     "error - symmetric algorithm",
+			token:    func() string { return sign(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), validClaims()) },
+			validate: assertUnauthenticated,
+		},
+		{
+			name: "error - unsigned",
+			token: func() string {
+				return sign(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, validClaims())
+			},
+			validate: assertUnauthenticated,
+		},
+		{
+			name:     "error - malformed",
+			token:    func() string { return "not.a.token" },
+			validate: assertUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token())
+			tt.validate(t, claims, err)
+		})
+	}
+}
+
+func TestVerifier_ReloadsChangedFile(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	file := writeJWKS(t, dir, map[string]crypto.PublicKey{"old": oldKey.Public()})
+	verifier := newTestVerifier(t, Config{JWKSFile: file})
+
+	claims := jwt.MapClaims{"sub": "user-42", "iss": testIssuer, "aud": testAudience, "exp": time.Now().Add(time.Hour).Unix()}
+	newToken := sign(t, jwt.SigningMethodRS256, "new", newKey, claims)
+
+	_, err = verifier.Verify(context.Background(), newToken)
+	assert.Error(t, err)
+
+	// an invalid file keeps the keys in use
+	require.NoError(t, os.WriteFile(file, []byte("{"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	_, err = verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "old", oldKey, claims))
+	assert.NoError(t, err)
+
+	writeJWKS(t, dir, map[string]crypto.PublicKey{"new": newKey.Public()})
+	assert.Eventually(t, func() bool {
+		_, err := verifier.Verify(context.Background(), newToken)
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestVerifier_FetchesURL(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jwksJSON(t, map[string]crypto.PublicKey{"ec": key.Public()}))
+	}))
+	defer server.Close()
+
+	verifier := newTestVerifier(t, Config{JWKSURL: server.URL})
+
+	claims := jwt.MapClaims{"sub": "user-42", "iss": testIssuer, "aud": []string{"other", testAudience}, "exp": time.Now().Add(time.Hour).Unix()}
+	_, err = verifier.Verify(context.Background(), sign(t, jwt.SigningMethodES384, "ec", key, claims))
+	assert.NoError(t, err)
+}
+
+func TestNewVerifier_InvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"keys":[]}`), 0o600))
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "no JWKS", cfg: Config{Issuer: testIssuer, Audience: testAudience}},
+		{name: "no issuer", cfg: Config{JWKSFile: invalid, Audience: testAudience}},
+		{name: "no audience", cfg: Config{JWKSFile: invalid, Issuer: testIssuer}},
+		{name: "missing file", cfg: Config{JWKSFile: filepath.Join(dir, "missing.json"), Issuer: testIssuer, Audience: testAudience}},
+		{name: "no key", cfg: Config{JWKSFile: invalid, Issuer: testIssuer, Audience: testAudience}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewVerifier(context.Background(), tt.cfg, slog.New(slog.DiscardHandler))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func newTestVerifier(t *testing.T, cfg Config) *Verifier {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	cfg.Issuer = testIssuer
+	cfg.Audience = testAudience
+	cfg.ScopeClaim = "scope"
+	cfg.TenantClaim = "tenant"
+	verifier, err := NewVerifier(ctx, cfg, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	return verifier
+}
+
+func assertUnauthenticated(t *testing.T, _ domain.TokenClaims, err error) {
+	assert.True(t, domain.HasCode(err, domain.UnauthenticatedCode), "unexpected error: %v", err)
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// writeJWKS writes the keys to a JWKS file through a rename, as atomic updates do
+func writeJWKS(t *testing.T, dir string, keys map[string]crypto.PublicKey) string {
+	t.Helper()
+	file := filepath.Join(dir, "jwks.json")
+	tmp := filepath.Join(dir, "jwks.json.tmp")
+	require.NoError(t, os.WriteFile(tmp, jwksJSON(t, keys), 0o600))
+	require.NoError(t, os.Rename(tmp, file))
+	return file
+}
+
+func jwksJSON(t *testing.T, keys map[string]crypto.PublicKey) []byte {
+	t.Helper()
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	jwks := struct {
+		Keys []map[string]string `json:"keys"`
+	}{}
+	for kid, key := range keys {
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, map[string]string{
+				"kty": "RSA", "kid": kid, "use": "sig",
+				"n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			raw, err := k.Bytes()
+			require.NoError(t, err)
+			size := (len(raw) - 1) / 2
+			jwks.Keys = append(jwks.Keys, map[string]string{
+				"kty": "EC", "kid": kid, "crv": k.Curve.Params().Name,
+				"x": b64(raw[1 : 1+size]), "y": b64(raw[1+size:]),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, map[string]string{
+				"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(k),
+			})
+		}
+	}
+	// a key which is not meant for signatures is ignored
+	jwks.Keys = append(jwks.Keys, map[string]string{"kty": "RSA", "kid": "enc", "use": "enc"})
+
+	data, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	return data
+}
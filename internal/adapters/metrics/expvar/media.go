@@ -0,0 +1,48 @@
+package expvar
+
+import (
+	"expvar"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+var (
+	mediaLifecycle *expvar.Map
+)
+
+func init() {
+	// this panic if the map already exists
+	mediaLifecycle = expvar.NewMap("media_lifecycle")
+}
+
+// MediaReserved counts a media reserved under reserved.<type>.count
+func (em *ExpvarMetrics) MediaReserved(mediaType domain.MediaType) {
+	mediaLifecycle.Add("reserved."+string(mediaType)+".count", 1)
+}
+
+// UploadURLReissued counts an upload URL issued again under
+// upload_url_reissued.<type>.count
+func (em *ExpvarMetrics) UploadURLReissued(mediaType domain.MediaType) {
+	mediaLifecycle.Add("upload_url_reissued."+string(mediaType)+".count", 1)
+}
+
+// MediaFinalized counts a media finalized and its bytes under
+// finalized.<type>.count and finalized.<type>.bytes
+func (em *ExpvarMetrics) MediaFinalized(mediaType domain.MediaType, size int64) {
+	mediaLifecycle.Add("finalized."+string(mediaType)+".count", 1)
+	mediaLifecycle.Add("finalized."+string(mediaType)+".bytes", size)
+}
+
+// FinalizeFailed counts a failed finalization under finalize_failed.<reason>.count
+func (em *ExpvarMetrics) FinalizeFailed(reason domain.FinalizeFailureReason) {
+	mediaLifecycle.Add("finalize_failed."+string(reason)+".count", 1)
+}
+
+// OldestReservedMediaAge sets the age of the oldest reserved media under
+// oldest_reserved_age_seconds
+func (em *ExpvarMetrics) OldestReservedMediaAge(age time.Duration) {
+	v := new(expvar.Float)
+	v.Set(age.Seconds())
+	mediaLifecycle.Set("oldest_reserved_age_seconds", v)
+}
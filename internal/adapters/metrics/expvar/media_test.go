@@ -0,0 +1,28 @@
+package expvar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpvarMetrics_MediaLifecycle(t *testing.T) {
+	em := NewExpvarMetrics()
+
+	em.MediaReserved(domain.MediaTypeImage)
+	em.MediaReserved(domain.MediaTypeImage)
+	em.UploadURLReissued(domain.MediaTypeVideo)
+	em.MediaFinalized(domain.MediaTypeImage, 2048)
+	em.MediaFinalized(domain.MediaTypeImage, 1024)
+	em.FinalizeFailed(domain.FinalizeFailureMissingFile)
+	em.OldestReservedMediaAge(90 * time.Second)
+
+	assert.Equal(t, "2", mediaLifecycle.Get("reserved.image.count").String())
+	assert.Equal(t, "1", mediaLifecycle.Get("upload_url_reissued.video.count").String())
+	assert.Equal(t, "2", mediaLifecycle.Get("finalized.image.count").String())
+	assert.Equal(t, "3072", mediaLifecycle.Get("finalized.image.bytes").String())
+	assert.Equal(t, "1", mediaLifecycle.Get("finalize_failed.missing_file.count").String())
+	assert.Equal(t, "90", mediaLifecycle.Get("oldest_reserved_age_seconds").String())
+}
@@ -0,0 +1,17 @@
+package prometheus
+
+import (
+	"github.com/peano88/medias/config"
+)
+
+// Config holds the configuration of the Prometheus metrics
+type Config struct {
+	// HistogramBuckets are the upper bounds, in seconds, of the request
+	// duration buckets
+	HistogramBuckets []float64 `mapstructure:"histogram-buckets"`
+}
+
+func SetDefaultConfig(loader config.ConfigLoader, prefix string) {
+	loader.SetDefault(prefix+".histogram-buckets",
+		[]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10})
+}
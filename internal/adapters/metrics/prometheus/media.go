@@ -0,0 +1,96 @@
+package prometheus
+
+import (
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// mediaMetrics are the business metrics of the media lifecycle
+type mediaMetrics struct {
+	reservations      *prom.CounterVec
+	reissuedURLs      *prom.CounterVec
+	finalized         *prom.CounterVec
+	finalizedBytes    *prom.CounterVec
+	finalizeFailures  *prom.CounterVec
+	oldestReservedAge prom.Gauge
+}
+
+func newMediaMetrics() *mediaMetrics {
+	return &mediaMetrics{
+		reservations: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "media",
+			Name:      "reservations_total",
+			Help:      "Media created reserved, waiting for their upload, by media type",
+		}, []string{"type"}),
+		reissuedURLs: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "media",
+			Name:      "upload_urls_reissued_total",
+			Help:      "Upload URLs issued again for media already reserved, by media type",
+		}, []string{"type"}),
+		finalized: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "media",
+			Name:      "finalized_total",
+			Help:      "Media finalized, by media type",
+		}, []string{"type"}),
+		finalizedBytes: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "media",
+			Name:      "finalized_bytes_total",
+			Help:      "Bytes of the media finalized, by media type",
+		}, []string{"type"}),
+		finalizeFailures: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "media",
+			Name:      "finalize_failures_total",
+			Help:      "Media which could not be finalized, by reason",
+		}, []string{"reason"}),
+		oldestReservedAge: prom.NewGauge(prom.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "media",
+			Name:      "oldest_reserved_age_seconds",
+			Help:      "Age of the oldest media still reserved, 0 when there is none",
+		}),
+	}
+}
+
+func (mm *mediaMetrics) collectors() []prom.Collector {
+	return []prom.Collector{
+		mm.reservations,
+		mm.reissuedURLs,
+		mm.finalized,
+		mm.finalizedBytes,
+		mm.finalizeFailures,
+		mm.oldestReservedAge,
+	}
+}
+
+// MediaReserved records a media created reserved
+func (pm *PrometheusMetrics) MediaReserved(mediaType domain.MediaType) {
+	pm.media.reservations.WithLabelValues(string(mediaType)).Inc()
+}
+
+// UploadURLReissued records an upload URL issued again for a reserved media
+func (pm *PrometheusMetrics) UploadURLReissued(mediaType domain.MediaType) {
+	pm.media.reissuedURLs.WithLabelValues(string(mediaType)).Inc()
+}
+
+// MediaFinalized records a media finalized along with its size in bytes
+func (pm *PrometheusMetrics) MediaFinalized(mediaType domain.MediaType, size int64) {
+	pm.media.finalized.WithLabelValues(string(mediaType)).Inc()
+	pm.media.finalizedBytes.WithLabelValues(string(mediaType)).Add(float64(size))
+}
+
+// FinalizeFailed records a media which could not be finalized
+func (pm *PrometheusMetrics) FinalizeFailed(reason domain.FinalizeFailureReason) {
+	pm.media.finalizeFailures.WithLabelValues(string(reason)).Inc()
+}
+
+// OldestReservedMediaAge sets the age of the oldest reserved media
+func (pm *PrometheusMetrics) OldestReservedMediaAge(age time.Duration) {
+	pm.media.oldestReservedAge.Set(age.Seconds())
+}
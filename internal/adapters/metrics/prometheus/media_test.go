@@ -0,0 +1,32 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics_MediaLifecycle(t *testing.T) {
+	metrics, err := NewPrometheusMetrics(Config{HistogramBuckets: []float64{1}})
+	require.NoError(t, err)
+
+	metrics.MediaReserved(domain.MediaTypeImage)
+	metrics.MediaReserved(domain.MediaTypeImage)
+	metrics.UploadURLReissued(domain.MediaTypeVideo)
+	metrics.MediaFinalized(domain.MediaTypeImage, 2048)
+	metrics.MediaFinalized(domain.MediaTypeImage, 1024)
+	metrics.FinalizeFailed(domain.FinalizeFailureMissingFile)
+	metrics.OldestReservedMediaAge(90 * time.Second)
+
+	body := scrape(t, metrics.Handler())
+
+	assert.Contains(t, body, `medias_media_reservations_total{type="image"} 2`)
+	assert.Contains(t, body, `medias_media_upload_urls_reissued_total{type="video"} 1`)
+	assert.Contains(t, body, `medias_media_finalized_total{type="image"} 2`)
+	assert.Contains(t, body, `medias_media_finalized_bytes_total{type="image"} 3072`)
+	assert.Contains(t, body, `medias_media_finalize_failures_total{reason="missing_file"} 1`)
+	assert.Contains(t, body, "medias_media_oldest_reserved_age_seconds 90")
+}
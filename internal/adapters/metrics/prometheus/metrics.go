@@ -0,0 +1,81 @@
+package prometheus
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "medias"
+
+// PrometheusMetrics records the HTTP requests and the media lifecycle in a
+// registry of its own, along with the Go runtime and process metrics, exposed
+// in the Prometheus text format by Handler
+type PrometheusMetrics struct {
+	registry         *prom.Registry
+	requestDurations *prom.HistogramVec
+	media            *mediaMetrics
+}
+
+func NewPrometheusMetrics(cfg Config) (*PrometheusMetrics, error) {
+	registry := prom.NewRegistry()
+
+	requestDurations := prom.NewHistogramVec(prom.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the HTTP requests by route and status code",
+		Buckets:   cfg.HistogramBuckets,
+	}, []string{"method", "route", "code", "status_class"})
+
+	media := newMediaMetrics()
+
+	for _, collector := range append([]prom.Collector{
+		requestDurations,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	}, media.collectors()...) {
+		if err := registry.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return &PrometheusMetrics{
+		registry:         registry,
+		requestDurations: requestDurations,
+		media:            media,
+	}, nil
+}
+
+// AddRequestHit records a request to pattern, a method followed by the route
+// pattern such as "GET /api/v1/media/{id}"
+func (pm *PrometheusMetrics) AddRequestHit(pattern string, code int, duration time.Duration) error {
+	method, route, _ := strings.Cut(pattern, " ")
+	pm.requestDurations.
+		WithLabelValues(method, route, strconv.Itoa(code), statusClass(code)).
+		Observe(duration.Seconds())
+	return nil
+}
+
+// Register adds a collector to the exposed metrics
+func (pm *PrometheusMetrics) Register(collector prom.Collector) error {
+	return pm.registry.Register(collector)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func (pm *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(pm.registry, promhttp.HandlerOpts{Registry: pm.registry})
+}
+
+// statusClass groups the status codes by their first digit, e.g. "4xx"
+func statusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
@@ -0,0 +1,59 @@
+package prometheus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics_AddRequestHit(t *testing.T) {
+	metrics, err := NewPrometheusMetrics(Config{HistogramBuckets: []float64{0.1, 1}})
+	require.NoError(t, err)
+
+	require.NoError(t, metrics.AddRequestHit("GET /api/v1/media/{id}", http.StatusOK, 50*time.Millisecond))
+	require.NoError(t, metrics.AddRequestHit("GET /api/v1/media/{id}", http.StatusOK, 500*time.Millisecond))
+	require.NoError(t, metrics.AddRequestHit("POST /api/v1/media", http.StatusTooManyRequests, time.Millisecond))
+
+	body := scrape(t, metrics.Handler())
+
+	assert.Contains(t, body, `medias_http_request_duration_seconds_bucket{code="200",method="GET",route="/api/v1/media/{id}",status_class="2xx",le="0.1"} 1`)
+	assert.Contains(t, body, `medias_http_request_duration_seconds_bucket{code="200",method="GET",route="/api/v1/media/{id}",status_class="2xx",le="1"} 2`)
+	assert.Contains(t, body, `medias_http_request_duration_seconds_count{code="429",method="POST",route="/api/v1/media",status_class="4xx"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		code     int
+		expected string
+	}{
+		{http.StatusOK, "2xx"},
+		{http.StatusNotModified, "3xx"},
+		{http.StatusNotFound, "4xx"},
+		{http.StatusServiceUnavailable, "5xx"},
+		{0, "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, statusClass(tt.code))
+		})
+	}
+}
+
+func scrape(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
@@ -0,0 +1,63 @@
+package prometheus
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolStater provides the statistics of a connection pool
+type PoolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+// PoolCollector exposes the statistics of a pgx connection pool
+type PoolCollector struct {
+	pool PoolStater
+
+	maxConns          *prom.Desc
+	totalConns        *prom.Desc
+	idleConns         *prom.Desc
+	acquiredConns     *prom.Desc
+	constructingConns *prom.Desc
+	acquireCount      *prom.Desc
+	acquireDuration   *prom.Desc
+	emptyAcquireCount *prom.Desc
+	canceledAcquires  *prom.Desc
+}
+
+func NewPoolCollector(pool PoolStater) *PoolCollector {
+	desc := func(name, help string) *prom.Desc {
+		return prom.NewDesc(prom.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+
+	return &PoolCollector{
+		pool:              pool,
+		maxConns:          desc("max_conns", "Maximum size of the pool"),
+		totalConns:        desc("total_conns", "Number of connections in the pool"),
+		idleConns:         desc("idle_conns", "Number of idle connections in the pool"),
+		acquiredConns:     desc("acquired_conns", "Number of connections currently acquired"),
+		constructingConns: desc("constructing_conns", "Number of connections being established"),
+		acquireCount:      desc("acquires_total", "Number of successful connection acquisitions"),
+		acquireDuration:   desc("acquire_duration_seconds_total", "Time spent acquiring connections"),
+		emptyAcquireCount: desc("empty_acquires_total", "Number of acquisitions which waited for a connection"),
+		canceledAcquires:  desc("canceled_acquires_total", "Number of acquisitions canceled by their context"),
+	}
+}
+
+func (pc *PoolCollector) Describe(ch chan<- *prom.Desc) {
+	prom.DescribeByCollect(pc, ch)
+}
+
+func (pc *PoolCollector) Collect(ch chan<- prom.Metric) {
+	stat := pc.pool.Stat()
+
+	ch <- prom.MustNewConstMetric(pc.maxConns, prom.GaugeValue, float64(stat.MaxConns()))
+	ch <- prom.MustNewConstMetric(pc.totalConns, prom.GaugeValue, float64(stat.TotalConns()))
+	ch <- prom.MustNewConstMetric(pc.idleConns, prom.GaugeValue, float64(stat.IdleConns()))
+	ch <- prom.MustNewConstMetric(pc.acquiredConns, prom.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prom.MustNewConstMetric(pc.constructingConns, prom.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prom.MustNewConstMetric(pc.acquireCount, prom.CounterValue, float64(stat.AcquireCount()))
+	ch <- prom.MustNewConstMetric(pc.acquireDuration, prom.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prom.MustNewConstMetric(pc.emptyAcquireCount, prom.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prom.MustNewConstMetric(pc.canceledAcquires, prom.CounterValue, float64(stat.CanceledAcquireCount()))
+}
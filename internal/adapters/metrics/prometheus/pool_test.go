@@ -0,0 +1,27 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolCollector(t *testing.T) {
+	// the pool connects lazily, its statistics are available right away
+	pool, err := pgxpool.New(context.Background(), "postgres://medias@127.0.0.1:1/medias?pool_max_conns=7")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	metrics, err := NewPrometheusMetrics(Config{HistogramBuckets: []float64{1}})
+	require.NoError(t, err)
+	require.NoError(t, metrics.Register(NewPoolCollector(pool)))
+
+	body := scrape(t, metrics.Handler())
+
+	assert.Contains(t, body, "medias_db_pool_max_conns 7")
+	assert.Contains(t, body, "medias_db_pool_acquired_conns 0")
+	assert.Contains(t, body, "medias_db_pool_acquires_total 0")
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"fmt"
+
+	"github.com/peano88/medias/config"
+)
+
+const (
+	// StoreMemory keeps the buckets in the process, each replica limiting
+	// the clients on its own
+	StoreMemory = "memory"
+	// StorePostgres shares the buckets between the replicas. Each request
+	// costs two UPSERTs on the pool serving the API, one for the bucket of
+	// its IP address before the authentication and one for the bucket of its
+	// client on the route after it: they cannot share a statement since the
+	// client is only known once authenticated. Size database.max-conns for it.
+	StorePostgres = "postgres"
+)
+
+// Config holds the configuration of the rate limiting
+type Config struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Store is where the buckets are kept, StoreMemory or StorePostgres
+	Store string `mapstructure:"store"`
+	// Default is the limit of the routes without a limit of their own
+	Default LimitConfig `mapstructure:"default"`
+	// Routes are the limits of specific routes
+	Routes []RouteConfig `mapstructure:"routes"`
+	// IP is the limit of each IP address over every route, applied before
+	// the client is authenticated
+	IP LimitConfig `mapstructure:"ip"`
+}
+
+// LimitConfig is a token bucket per client
+type LimitConfig struct {
+	RequestsPerSecond float64 `mapstructure:"requests-per-second"`
+	Burst             int     `mapstructure:"burst"`
+}
+
+// RouteConfig is the limit of a route, identified by its method and pattern
+// relative to the API base path, e.g. "POST /media" or "GET /media/{id}"
+type RouteConfig struct {
+	Route       string `mapstructure:"route"`
+	LimitConfig `mapstructure:",squash"`
+}
+
+func SetDefaultConfig(loader config.ConfigLoader, prefix string) {
+	loader.SetDefault(prefix+".enabled", true)
+	loader.SetDefault(prefix+".store", StoreMemory)
+	loader.SetDefault(prefix+".default.requests-per-second", 20)
+	loader.SetDefault(prefix+".default.burst", 40)
+	loader.SetDefault(prefix+".routes", []map[string]any{})
+	loader.SetDefault(prefix+".ip.requests-per-second", 50)
+	loader.SetDefault(prefix+".ip.burst", 100)
+}
+
+// Validate reports all the problems of the configuration, with their keys
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Store != StoreMemory && c.Store != StorePostgres {
+		errs = append(errs, config.Invalid("store", "must be %s or %s, got %q", StoreMemory, StorePostgres, c.Store))
+	}
+	if _, err := c.Default.rateLimit(); err != nil {
+		errs = append(errs, config.Invalid("default", "%s", err))
+	}
+	if _, err := c.IP.rateLimit(); err != nil {
+		errs = append(errs, config.Invalid("ip", "%s", err))
+	}
+	for i, route := range c.Routes {
+		if _, _, err := route.compile(); err != nil {
+			errs = append(errs, config.Invalid(fmt.Sprintf("routes[%d]", i), "%s", err))
+		}
+	}
+	return config.Collect("", errs...)
+}
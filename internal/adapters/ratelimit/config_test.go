@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/peano88/medias/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	validDefault := LimitConfig{RequestsPerSecond: 10, Burst: 20}
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		wantKeys []string
+	}{
+		{
+			name: "valid",
+			cfg: Config{
+				Store:   StoreMemory,
+				Default: validDefault,
+				Routes:  []RouteConfig{{Route: "POST /media", LimitConfig: validDefault}},
+				IP:      validDefault,
+			},
+		},
+		{
+			name:     "unknown store",
+			cfg:      Config{Store: "redis", Default: validDefault, IP: validDefault},
+			wantKeys: []string{"store"},
+		},
+		{
+			name: "invalid limits",
+			cfg: Config{
+				Store:   StorePostgres,
+				Default: LimitConfig{RequestsPerSecond: 10},
+				Routes: []RouteConfig{
+					{Route: "POST /media", LimitConfig: validDefault},
+					{Route: "/media", LimitConfig: validDefault},
+				},
+				IP: LimitConfig{Burst: 100},
+			},
+			wantKeys: []string{"default", "ip", "routes[1]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if len(tt.wantKeys) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErrs config.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+			keys := make([]string, len(validationErrs))
+			for i, fieldErr := range validationErrs {
+				keys[i] = fieldErr.Key
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+// Store keeps the token buckets
+type Store interface {
+	// Take takes a token from the bucket identified by key, created full when
+	// it does not exist
+	Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error)
+}
+
+// Limiter limits the requests of each client to each route
+type Limiter struct {
+	store Store
+	// limits are replaced as a whole by Update
+	limits atomic.Pointer[limits]
+}
+
+type limits struct {
+	defaultLimit domain.RateLimit
+	routes       map[string]domain.RateLimit
+	ip           domain.RateLimit
+}
+
+// New creates a limiter applying the limits of cfg, keeping the buckets in store
+func New(cfg Config, store Store) (*Limiter, error) {
+	l := &Limiter{store: store}
+	if err := l.Update(cfg); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Update applies the limits of cfg to the requests to come, keeping the
+// current ones when cfg is invalid. The buckets are kept as well, refilled at
+// the new rates.
+func (l *Limiter) Update(cfg Config) error {
+	compiled, err := compile(cfg)
+	if err != nil {
+		return err
+	}
+	l.limits.Store(compiled)
+	return nil
+}
+
+// Allow takes a token from the bucket of the client for the route, a method
+// and a pattern such as "POST /media"
+func (l *Limiter) Allow(ctx context.Context, route, client string) (domain.RateLimitDecision, error) {
+	current := l.limits.Load()
+	limit, ok := current.routes[route]
+	if !ok {
+		limit = current.defaultLimit
+	}
+	return l.store.Take(ctx, route+"|"+client, limit)
+}
+
+// AllowIP takes a token from the bucket of the IP address, shared by every
+// route
+func (l *Limiter) AllowIP(ctx context.Context, ip string) (domain.RateLimitDecision, error) {
+	return l.store.Take(ctx, "*|ip:"+ip, l.limits.Load().ip)
+}
+
+func compile(cfg Config) (*limits, error) {
+	defaultLimit, err := cfg.Default.rateLimit()
+	if err != nil {
+		return nil, fmt.Errorf("invalid default limit: %w", err)
+	}
+	ipLimit, err := cfg.IP.rateLimit()
+	if err != nil {
+		return nil, fmt.Errorf("invalid ip limit: %w", err)
+	}
+	routes := make(map[string]domain.RateLimit, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		key, limit, err := route.compile()
+		if err != nil {
+			return nil, err
+		}
+		routes[key] = limit
+	}
+	return &limits{defaultLimit: defaultLimit, routes: routes, ip: ipLimit}, nil
+}
+
+// compile returns the key of the route, its method and pattern, and its limit
+func (r RouteConfig) compile() (string, domain.RateLimit, error) {
+	method, pattern, ok := strings.Cut(r.Route, " ")
+	if !ok || method == "" || !strings.HasPrefix(pattern, "/") {
+		return "", domain.RateLimit{}, fmt.Errorf("invalid route %q, expected a method and a pattern such as \"POST /media\"", r.Route)
+	}
+	limit, err := r.rateLimit()
+	if err != nil {
+		return "", domain.RateLimit{}, fmt.Errorf("invalid limit of route %q: %w", r.Route, err)
+	}
+	return strings.ToUpper(method) + " " + pattern, limit, nil
+}
+
+func (c LimitConfig) rateLimit() (domain.RateLimit, error) {
+	if c.RequestsPerSecond <= 0 || c.Burst < 1 {
+		return domain.RateLimit{}, fmt.Errorf("requests-per-second must be positive and burst at least 1")
+	}
+	return domain.RateLimit{Rate: c.RequestsPerSecond, Burst: c.Burst}, nil
+}
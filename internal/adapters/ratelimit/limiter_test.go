@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingStore struct {
+	key   string
+	limit domain.RateLimit
+}
+
+func (s *recordingStore) Take(_ context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error) {
+	s.key = key
+	s.limit = limit
+	return domain.RateLimitDecision{Allowed: true}, nil
+}
+
+func TestNew(t *testing.T) {
+	validDefault := LimitConfig{RequestsPerSecond: 10, Burst: 20}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name: "valid",
+			cfg: Config{
+				Default: validDefault,
+				Routes: []RouteConfig{
+					{Route: "POST /media", LimitConfig: LimitConfig{RequestsPerSecond: 0.5, Burst: 1}},
+				},
+				IP: validDefault,
+			},
+		},
+		{
+			name:    "invalid default",
+			cfg:     Config{Default: LimitConfig{RequestsPerSecond: 0, Burst: 20}, IP: validDefault},
+			wantErr: "invalid default limit",
+		},
+		{
+			name:    "invalid ip limit",
+			cfg:     Config{Default: validDefault, IP: LimitConfig{RequestsPerSecond: 50}},
+			wantErr: "invalid ip limit",
+		},
+		{
+			name: "route without method",
+			cfg: Config{
+				Default: validDefault,
+				Routes:  []RouteConfig{{Route: "/media", LimitConfig: validDefault}},
+				IP:      validDefault,
+			},
+			wantErr: "invalid route",
+		},
+		{
+			name: "invalid route limit",
+			cfg: Config{
+				Default: validDefault,
+				Routes:  []RouteConfig{{Route: "POST /media", LimitConfig: LimitConfig{RequestsPerSecond: 1}}},
+				IP:      validDefault,
+			},
+			wantErr: "invalid limit of route",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.cfg, &recordingStore{})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	store := &recordingStore{}
+	limiter, err := New(Config{
+		Default: LimitConfig{RequestsPerSecond: 10, Burst: 20},
+		Routes: []RouteConfig{
+			{Route: "post /media", LimitConfig: LimitConfig{RequestsPerSecond: 0.5, Burst: 2}},
+		},
+		IP: LimitConfig{RequestsPerSecond: 50, Burst: 100},
+	}, store)
+	require.NoError(t, err)
+
+	_, err = limiter.Allow(context.Background(), "POST /media", "ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "POST /media|ip:10.0.0.1", store.key)
+	assert.Equal(t, domain.RateLimit{Rate: 0.5, Burst: 2}, store.limit)
+
+	_, err = limiter.Allow(context.Background(), "GET /media", "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "GET /media|key-1", store.key)
+	assert.Equal(t, domain.RateLimit{Rate: 10, Burst: 20}, store.limit)
+
+	_, err = limiter.AllowIP(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "*|ip:10.0.0.1", store.key)
+	assert.Equal(t, domain.RateLimit{Rate: 50, Burst: 100}, store.limit)
+}
+
+func TestLimiter_Update(t *testing.T) {
+	store := &recordingStore{}
+	limiter, err := New(Config{
+		Default: LimitConfig{RequestsPerSecond: 10, Burst: 20},
+		IP:      LimitConfig{RequestsPerSecond: 50, Burst: 100},
+	}, store)
+	require.NoError(t, err)
+
+	err = limiter.Update(Config{
+		Default: LimitConfig{RequestsPerSecond: 5, Burst: 10},
+		Routes: []RouteConfig{
+			{Route: "POST /media", LimitConfig: LimitConfig{RequestsPerSecond: 1, Burst: 1}},
+		},
+		IP: LimitConfig{RequestsPerSecond: 25, Burst: 50},
+	})
+	require.NoError(t, err)
+
+	_, err = limiter.Allow(context.Background(), "POST /media", "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RateLimit{Rate: 1, Burst: 1}, store.limit)
+	_, err = limiter.Allow(context.Background(), "GET /media", "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RateLimit{Rate: 5, Burst: 10}, store.limit)
+	_, err = limiter.AllowIP(context.Background(), "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RateLimit{Rate: 25, Burst: 50}, store.limit)
+
+	// an invalid configuration keeps the current limits
+	err = limiter.Update(Config{Default: LimitConfig{RequestsPerSecond: 0, Burst: 10}, IP: LimitConfig{RequestsPerSecond: 25, Burst: 50}})
+	assert.ErrorContains(t, err, "invalid default limit")
+
+	_, err = limiter.Allow(context.Background(), "POST /media", "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RateLimit{Rate: 1, Burst: 1}, store.limit)
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryStore keeps the token buckets in memory
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an in-memory store. Every sweepInterval until ctx is
+// done, the buckets idle for that long are dropped: they are full again by
+// then, unless the limit refills slower than that. A zero interval never
+// drops them.
+func NewMemoryStore(ctx context.Context, sweepInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+	if sweepInterval > 0 {
+		go s.sweepPeriodically(ctx, sweepInterval)
+	}
+	return s
+}
+
+// Take takes a token from the bucket identified by key
+func (s *MemoryStore) Take(_ context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updatedAt).Seconds()*limit.Rate)
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return limit.Decision(b.tokens, allowed), nil
+}
+
+func (s *MemoryStore) sweep(idle time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	threshold := s.now().Add(-idle)
+	for key, b := range s.buckets {
+		if b.updatedAt.Before(threshold) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func (s *MemoryStore) sweepPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep(interval)
+		}
+	}
+}
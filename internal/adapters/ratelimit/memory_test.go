@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(ctx, time.Hour)
+	store.now = func() time.Time { return now }
+
+	limit := domain.RateLimit{Rate: 1, Burst: 2}
+
+	decision, err := store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 1, decision.Remaining)
+
+	decision, err = store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	assert.Equal(t, 2*time.Second, decision.Reset)
+
+	decision, err = store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, time.Second, decision.RetryAfter)
+
+	// other clients have their own bucket
+	decision, err = store.Take(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	// the bucket is refilled over time, up to its size
+	now = now.Add(time.Second)
+	decision, err = store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	now = now.Add(time.Minute)
+	decision, err = store.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 1, decision.Remaining)
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(ctx, time.Hour)
+	store.now = func() time.Time { return now }
+
+	limit := domain.RateLimit{Rate: 1, Burst: 2}
+	_, err := store.Take(ctx, "idle", limit)
+	require.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = store.Take(ctx, "active", limit)
+	require.NoError(t, err)
+
+	store.sweep(30 * time.Second)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "active")
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// advisoryLockClass namespaces the advisory locks of the service ("medi" in
+// ASCII). They are taken in the two-key form, the class first, whose key space
+// does not overlap the single-key form: they cannot collide with the locks of
+// other applications sharing the database unless those pick the same class.
+const advisoryLockClass int32 = 0x6d656469
+
+// advisoryLock identifies an advisory lock of the service within its class
+type advisoryLock int32
+
+const (
+	// tagCooccurrencesLock serializes the refresh of the tag co-occurrence
+	// view across replicas
+	tagCooccurrencesLock advisoryLock = 1
+	// migrationsLock serializes the migrations across replicas
+	migrationsLock advisoryLock = 2
+)
+
+// lock takes the session lock on conn, waiting for it if another session
+// holds it
+func (l advisoryLock) lock(ctx context.Context, conn *pgxpool.Conn) error {
+	_, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1, $2)", advisoryLockClass, int32(l))
+	return err
+}
+
+// tryLock takes the session lock on conn if no other session holds it,
+// reporting whether it did
+func (l advisoryLock) tryLock(ctx context.Context, conn *pgxpool.Conn) (bool, error) {
+	var locked bool
+	err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1, $2)", advisoryLockClass, int32(l)).Scan(&locked)
+	return locked, err
+}
+
+// unlock releases the session lock held on conn. It does not take a context:
+// the lock must be released even once the context of the work is done.
+func (l advisoryLock) unlock(conn *pgxpool.Conn) {
+	_, _ = conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1, $2)", advisoryLockClass, int32(l))
+}
@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
+)
+
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, tenant_id, created_at, revoked_at"
+
+// APIKeyRepository stores the API keys authenticating the clients
+type APIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository with the given connection pool
+func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{pool: pool}
+}
+
+// CreateAPIKey stores a new API key and returns it with DB-generated fields.
+// The key in clear is not stored, only its hash.
+func (ar *APIKeyRepository) CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, tenant_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + apiKeyColumns
+
+	created, err := scanAPIKey(ar.pool.QueryRow(ctx, query, key.Name, key.Prefix, key.Hash, scopesToStrings(key.Scopes), key.Tenant))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return domain.APIKey{}, domain.NewError(domain.ConflictCode,
+				domain.WithMessage("API key already exists"),
+				domain.WithTS(time.Now()),
+			)
+		}
+
+		return domain.APIKey{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to create API key"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return created, nil
+}
+
+// FindAPIKeyByHash retrieves the API key with the given hash, revoked or not
+func (ar *APIKeyRepository) FindAPIKeyByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	key, err := scanAPIKey(ar.pool.QueryRow(ctx, query, hash))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.APIKey{}, domain.NewError(domain.NotFoundCode,
+				domain.WithMessage("API key not found"),
+				domain.WithTS(time.Now()),
+			)
+		}
+		return domain.APIKey{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find API key"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return key, nil
+}
+
+// FindAllAPIKeys retrieves paginated API keys, revoked ones included, and returns the total count
+func (ar *APIKeyRepository) FindAllAPIKeys(ctx context.Context, params domain.PaginationParams) ([]domain.APIKey, int, error) {
+	var total int
+	if err := ar.pool.QueryRow(ctx, "SELECT COUNT(*) FROM api_keys").Scan(&total); err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to count API keys"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		ORDER BY created_at ASC, id ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := ar.pool.Query(ctx, query, params.Limit, params.Offset)
+	if err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to retrieve API keys"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer rows.Close()
+
+	keys := []domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, 0, domain.NewError(domain.InternalCode,
+				domain.WithMessage("failed to collect API keys"),
+				domain.WithDetails(err.Error()),
+				domain.WithTS(time.Now()),
+			)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to collect API keys"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return keys, total, nil
+}
+
+// RevokeAPIKey marks the API key as revoked. Revoking a key twice keeps the
+// time of the first revocation.
+func (ar *APIKeyRepository) RevokeAPIKey(ctx context.Context, id uuid.UUID) (domain.APIKey, error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(ar.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.APIKey{}, domain.NewError(domain.NotFoundCode,
+				domain.WithMessage("API key not found"),
+				domain.WithTS(time.Now()),
+			)
+		}
+		return domain.APIKey{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to revoke API key"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return key, nil
+}
+
+func scanAPIKey(row pgx.Row) (domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes []string
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&scopes,
+		&key.Tenant,
+		&key.CreatedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	key.Scopes = make([]domain.Scope, len(scopes))
+	for i, scope := range scopes {
+		key.Scopes[i] = domain.Scope(scope)
+	}
+
+	return key, nil
+}
+
+func scopesToStrings(scopes []domain.Scope) []string {
+	result := make([]string, len(scopes))
+	for i, scope := range scopes {
+		result[i] = string(scope)
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRepository_CreateAPIKey(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewAPIKeyRepository(testPool)
+
+	tests := []struct {
+		name     string
+		key      domain.APIKey
+		validate func(*testing.T, domain.APIKey, error)
+	}{
+		{
+			name: "success - stores the hash of the key",
+			key: domain.APIKey{
+				Name:   "uploader",
+				Prefix: "mk_newke",
+				Hash:   domain.HashAPIKey("mk_newkey_0123456789"),
+				Key:    "mk_newkey_0123456789",
+				Scopes: []domain.Scope{domain.ScopeMediaWrite},
+				Tenant: domain.DefaultTenant,
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.NoError(t, err)
+				assert.NotEqual(t, uuid.Nil, key.ID)
+				assert.Equal(t, "uploader", key.Name)
+				assert.Equal(t, domain.HashAPIKey("mk_newkey_0123456789"), key.Hash)
+				assert.Empty(t, key.Key)
+				assert.Equal(t, []domain.Scope{domain.ScopeMediaWrite}, key.Scopes)
+				assert.Equal(t, domain.DefaultTenant, key.Tenant)
+				assert.False(t, key.Revoked())
+			},
+		},
+		{
+			name: "success - key bound to a tenant",
+			key: domain.APIKey{
+				Name:   "tenant uploader",
+				Prefix: "mk_tenant",
+				Hash:   domain.HashAPIKey("mk_tenantkey_0123456789"),
+				Scopes: []domain.Scope{domain.ScopeMediaWrite},
+				Tenant: "acme",
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "acme", key.Tenant)
+				assert.Equal(t, "acme", key.Principal().Tenant)
+			},
+		},
+		{
+			name: "error - same key twice",
+			key: domain.APIKey{
+				Name:   "duplicate",
+				Prefix: "mk_activ",
+				Hash:   domain.HashAPIKey("mk_activekey_0123456789"),
+				Scopes: []domain.Scope{domain.ScopeTagsRead},
+				Tenant: domain.DefaultTenant,
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.True(t, domain.HasCode(err, domain.ConflictCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := repo.CreateAPIKey(ctx, tt.key)
+			tt.validate(t, key, err)
+		})
+	}
+}
+
+func TestAPIKeyRepository_FindAPIKeyByHash(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewAPIKeyRepository(testPool)
+
+	key, err := repo.FindAPIKeyByHash(ctx, domain.HashAPIKey("mk_activekey_0123456789"))
+	assert.NoError(t, err)
+	assert.Equal(t, "reader", key.Name)
+	assert.Equal(t, []domain.Scope{domain.ScopeTagsRead, domain.ScopeMediaRead}, key.Scopes)
+	assert.False(t, key.Revoked())
+
+	key, err = repo.FindAPIKeyByHash(ctx, domain.HashAPIKey("mk_revokedkey_0123456789"))
+	assert.NoError(t, err)
+	assert.True(t, key.Revoked())
+
+	_, err = repo.FindAPIKeyByHash(ctx, domain.HashAPIKey("unknown"))
+	assert.True(t, domain.HasCode(err, domain.NotFoundCode))
+}
+
+func TestAPIKeyRepository_FindAllAPIKeys(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewAPIKeyRepository(testPool)
+
+	keys, total, err := repo.FindAllAPIKeys(ctx, domain.PaginationParams{Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	if assert.Len(t, keys, 1) {
+		assert.Equal(t, "former writer", keys[0].Name)
+	}
+}
+
+func TestAPIKeyRepository_RevokeAPIKey(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewAPIKeyRepository(testPool)
+
+	key, err := repo.RevokeAPIKey(ctx, uuid.MustParse("aaa1aaaa-1111-4111-8111-111111111111"))
+	assert.NoError(t, err)
+	assert.True(t, key.Revoked())
+
+	// revoking again keeps the first revocation
+	key, err = repo.RevokeAPIKey(ctx, uuid.MustParse("bbb2bbbb-2222-4222-8222-222222222222"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, key.RevokedAt) {
+		assert.Equal(t, 2023, key.RevokedAt.Year())
+	}
+
+	_, err = repo.RevokeAPIKey(ctx, uuid.New())
+	assert.True(t, domain.HasCode(err, domain.NotFoundCode))
+}
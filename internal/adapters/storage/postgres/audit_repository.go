@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// AuditRepository stores the audit trail
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAuditRepository creates a new AuditRepository with the given connection pool
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+// RecordAuditEvent appends the event to the audit trail
+func (ar *AuditRepository) RecordAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (tenant_id, subject, action, outcome, details)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := ar.pool.Exec(ctx, query, event.Tenant, event.Subject, event.Action, event.Outcome, event.Details); err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to record audit event"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return nil
+}
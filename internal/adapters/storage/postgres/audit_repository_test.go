@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository_RecordAuditEvent(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewAuditRepository(testPool)
+
+	err := repo.RecordAuditEvent(ctx, domain.AuditEvent{
+		Tenant:  "acme",
+		Subject: "audited-subject",
+		Action:  domain.ActionManageRoles,
+		Outcome: domain.AuditOutcomeDenied,
+		Details: "the role viewer does not allow roles.manage",
+	})
+	assert.NoError(t, err)
+
+	var action, outcome, tenant string
+	err = testPool.QueryRow(ctx,
+		"SELECT action, outcome, tenant_id FROM audit_events WHERE subject = 'audited-subject'",
+	).Scan(&action, &outcome, &tenant)
+	assert.NoError(t, err)
+	assert.Equal(t, string(domain.ActionManageRoles), action)
+	assert.Equal(t, string(domain.AuditOutcomeDenied), outcome)
+	assert.Equal(t, "acme", tenant)
+}
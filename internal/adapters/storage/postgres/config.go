@@ -2,21 +2,30 @@ package postgres
 
 import (
 	"fmt"
-	"os"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/peano88/medias/config"
 )
 
 type Config struct {
-	Host               string `mapstructure:"host"`
-	Port               int    `mapstructure:"port"`
-	Database           string `mapstructure:"database"`
-	User               string `mapstructure:"user"`
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Database string `mapstructure:"database"`
+	User     string `mapstructure:"user"`
+	// Password is read from DB_PASSWORD, or the file named by DB_PASSWORD_FILE
+	Password           string `mapstructure:"password" secret:"true"`
 	SSLMode            string `mapstructure:"ssl-mode"`
 	MaxConns           int32  `mapstructure:"max-conns"`
 	MinConns           int32  `mapstructure:"min-conns"`
 	MaxConnLifetimeMin int    `mapstructure:"max-conn-lifetime-min"`
 	MaxConnIdleTimeMin int    `mapstructure:"max-conn-idle-time-min"`
+	// TextSearchConfig is the postgres text search configuration used to
+	// index and query media. Changing it requires reindexing existing media.
+	TextSearchConfig string `mapstructure:"text-search-config"`
+	// AutoMigrate applies the pending migrations on startup
+	AutoMigrate bool `mapstructure:"auto-migrate"`
 }
 
 func SetDefaultConfig(loader config.ConfigLoader, prefix string) {
@@ -25,22 +34,49 @@ func SetDefaultConfig(loader config.ConfigLoader, prefix string) {
 	loader.SetDefault(prefix+".max-conn-lifetime-min", 60)
 	loader.SetDefault(prefix+".max-conn-idle-time-min", 30)
 	loader.SetDefault(prefix+".ssl-mode", "require")
+	loader.SetDefault(prefix+".text-search-config", "english")
+	loader.SetDefault(prefix+".auto-migrate", false)
+	loader.BindEnv(prefix+".password", "DB_PASSWORD")
 }
 
-// ConnectionString builds the database connection string
-// Password must be provided via DB_PASSWORD environment variable
+// connectionString builds the database connection string
 func (d *Config) connectionString() string {
-	password := os.Getenv("DB_PASSWORD")
-	if password == "" {
-		password = "" // Allow empty for development, but should be set in production
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(d.User, d.Password),
+		Host:     fmt.Sprintf("%s:%d", d.Host, d.Port),
+		Path:     d.Database,
+		RawQuery: url.Values{"sslmode": {d.SSLMode}}.Encode(),
 	}
+	return u.String()
+}
+
+// sslModes are the ssl-mode values supported by pgx
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
 
-	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		d.User,
-		password,
-		d.Host,
-		d.Port,
-		d.Database,
-		d.SSLMode,
-	)
+// Validate reports all the problems of the configuration, with their keys
+func (d *Config) Validate() error {
+	var errs []error
+	if d.Host == "" {
+		errs = append(errs, config.Invalid("host", "must be set"))
+	}
+	if d.Port < 1 || d.Port > 65535 {
+		errs = append(errs, config.Invalid("port", "must be between 1 and 65535, got %d", d.Port))
+	}
+	if d.Database == "" {
+		errs = append(errs, config.Invalid("database", "must be set"))
+	}
+	if d.User == "" {
+		errs = append(errs, config.Invalid("user", "must be set"))
+	}
+	if !slices.Contains(sslModes, d.SSLMode) {
+		errs = append(errs, config.Invalid("ssl-mode", "must be one of %s, got %q", strings.Join(sslModes, ", "), d.SSLMode))
+	}
+	if d.MaxConns < 1 {
+		errs = append(errs, config.Invalid("max-conns", "must be at least 1, got %d", d.MaxConns))
+	}
+	if d.MinConns < 0 || d.MinConns > d.MaxConns {
+		errs = append(errs, config.Invalid("min-conns", "must be between 0 and max-conns (%d), got %d", d.MaxConns, d.MinConns))
+	}
+	return config.Collect("", errs...)
 }
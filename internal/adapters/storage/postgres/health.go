@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/pressly/goose/v3"
+)
+
+// HealthChecker checks the database is reachable through the pool
+type HealthChecker struct {
+	pool *pgxpool.Pool
+}
+
+// NewHealthChecker creates a new HealthChecker
+func NewHealthChecker(pool *pgxpool.Pool) *HealthChecker {
+	return &HealthChecker{pool: pool}
+}
+
+// Name identifies the check in the readiness report
+func (hc *HealthChecker) Name() string {
+	return "postgres"
+}
+
+// CheckHealth pings the database with a connection of the pool
+func (hc *HealthChecker) CheckHealth(ctx context.Context) error {
+	if err := hc.pool.Ping(ctx); err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("database unreachable"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	return nil
+}
+
+// MigrationsHealthChecker checks the schema of the database is at the version
+// of the embedded migrations
+type MigrationsHealthChecker struct {
+	provider *goose.Provider
+}
+
+// NewMigrationsHealthChecker creates a new MigrationsHealthChecker reading the
+// applied migrations through the pool
+func NewMigrationsHealthChecker(pool *pgxpool.Pool) (*MigrationsHealthChecker, error) {
+	provider, err := newMigrationsProvider(pool)
+	if err != nil {
+		return nil, err
+	}
+	return &MigrationsHealthChecker{provider: provider}, nil
+}
+
+// Name identifies the check in the readiness report
+func (mc *MigrationsHealthChecker) Name() string {
+	return "migrations"
+}
+
+// CheckHealth fails while some migrations are not applied
+func (mc *MigrationsHealthChecker) CheckHealth(ctx context.Context) error {
+	pending, err := mc.provider.HasPending(ctx)
+	if err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to read the schema version"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	if pending {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("database migrations pending"),
+			domain.WithTS(time.Now()),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthChecker_CheckHealth(t *testing.T) {
+	ctx := context.Background()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	checker := NewHealthChecker(testPool)
+	assert.Equal(t, "postgres", checker.Name())
+	assert.NoError(t, checker.CheckHealth(ctx))
+	assert.True(t, domain.HasCode(checker.CheckHealth(cancelCtx), domain.InternalCode))
+}
+
+func TestMigrationsHealthChecker_CheckHealth(t *testing.T) {
+	ctx := context.Background()
+
+	checker, err := NewMigrationsHealthChecker(testPool)
+	require.NoError(t, err)
+	assert.Equal(t, "migrations", checker.Name())
+
+	// the test database is migrated up to the last version
+	assert.NoError(t, checker.CheckHealth(ctx))
+
+	_, err = testDB.Exec("DELETE FROM goose_db_version WHERE version_id = (SELECT MAX(version_id) FROM goose_db_version)")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := testDB.Exec("INSERT INTO goose_db_version (version_id, is_applied) SELECT MAX(version_id) + 1, true FROM goose_db_version")
+		require.NoError(t, err)
+	})
+
+	err = checker.CheckHealth(ctx)
+	assert.True(t, domain.HasCode(err, domain.InternalCode))
+	assert.Equal(t, "database migrations pending", err.Error())
+}
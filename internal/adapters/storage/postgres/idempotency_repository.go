@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// IdempotencyRepository stores the responses of requests sent with an idempotency key
+type IdempotencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIdempotencyRepository creates a new IdempotencyRepository with the given connection pool
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{pool: pool}
+}
+
+// Reserve atomically claims the key for a request with the given fingerprint.
+// It returns true when the key was free (or expired) and is now reserved,
+// otherwise it returns the record already stored under the key.
+func (ir *IdempotencyRepository) Reserve(ctx context.Context, key domain.IdempotencyKey, fingerprint string, ttl time.Duration) (domain.IdempotencyRecord, bool, error) {
+	// Expired entries not yet purged are taken over as if they did not exist
+	query := `
+		INSERT INTO idempotency_keys (tenant_id, subject, key, fingerprint, expires_at)
+		VALUES ($1, $2, $3, $4, NOW() + make_interval(secs => $5))
+		ON CONFLICT (tenant_id, subject, key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+			status_code = NULL,
+			content_type = NULL,
+			headers = NULL,
+			body = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+		RETURNING tenant_id, subject, key, fingerprint, created_at, expires_at
+	`
+
+	var record domain.IdempotencyRecord
+	err := ir.pool.QueryRow(ctx, query, key.Tenant, key.Subject, key.Key, fingerprint, ttl.Seconds()).Scan(
+		&record.Tenant,
+		&record.Subject,
+		&record.Key,
+		&record.Fingerprint,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err == nil {
+		return record, true, nil
+	}
+	if err != pgx.ErrNoRows {
+		return domain.IdempotencyRecord{}, false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to reserve idempotency key"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	existing, err := ir.find(ctx, key)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	return existing, false, nil
+}
+
+// Complete stores the response of the request which reserved the key
+func (ir *IdempotencyRepository) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $5, content_type = $6, headers = $7, body = $8
+		WHERE tenant_id = $1 AND subject = $2 AND key = $3 AND fingerprint = $4
+	`
+
+	_, err := ir.pool.Exec(ctx, query,
+		record.Tenant,
+		record.Subject,
+		record.Key,
+		record.Fingerprint,
+		record.StatusCode,
+		record.ContentType,
+		record.Headers,
+		record.Body,
+	)
+	if err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to store idempotent response"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return nil
+}
+
+// Release frees a key whose request did not complete, so that it can be retried
+func (ir *IdempotencyRepository) Release(ctx context.Context, key domain.IdempotencyKey) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE tenant_id = $1 AND subject = $2 AND key = $3 AND status_code IS NULL
+	`
+
+	if _, err := ir.pool.Exec(ctx, query, key.Tenant, key.Subject, key.Key); err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to release idempotency key"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return nil
+}
+
+// DeleteExpired purges the expired keys and returns how many were deleted
+func (ir *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= NOW()
+	`
+
+	tag, err := ir.pool.Exec(ctx, query)
+	if err != nil {
+		return 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to delete expired idempotency keys"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (ir *IdempotencyRepository) find(ctx context.Context, key domain.IdempotencyKey) (domain.IdempotencyRecord, error) {
+	query := `
+		SELECT tenant_id, subject, key, fingerprint, COALESCE(status_code, 0), COALESCE(content_type, ''), COALESCE(headers, '{}'), body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE tenant_id = $1 AND subject = $2 AND key = $3
+	`
+
+	var record domain.IdempotencyRecord
+	err := ir.pool.QueryRow(ctx, query, key.Tenant, key.Subject, key.Key).Scan(
+		&record.Tenant,
+		&record.Subject,
+		&record.Key,
+		&record.Fingerprint,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.Headers,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.IdempotencyRecord{}, domain.NewError(domain.NotFoundCode,
+				domain.WithMessage("idempotency key not found"),
+				domain.WithTS(time.Now()),
+			)
+		}
+		return domain.IdempotencyRecord{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find idempotency key"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return record, nil
+}
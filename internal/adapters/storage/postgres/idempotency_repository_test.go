@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRepository_Reserve(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	fingerprint := strings.Repeat("3", 64)
+	subject := "api-key:11111111-1111-1111-1111-111111111111"
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		key      domain.IdempotencyKey
+		validate func(*testing.T, domain.IdempotencyRecord, bool, error)
+	}{
+		{
+			name: "success - reserves a new key",
+			ctx:  ctx,
+			key:  domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: subject, Key: "new-key"},
+			validate: func(t *testing.T, record domain.IdempotencyRecord, reserved bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, reserved)
+				assert.Equal(t, domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: subject, Key: "new-key"}, record.IdempotencyKey)
+				assert.Equal(t, fingerprint, record.Fingerprint)
+				assert.False(t, record.Completed())
+				assert.True(t, record.ExpiresAt.After(record.CreatedAt))
+			},
+		},
+		{
+			name: "success - returns the in progress key",
+			ctx:  ctx,
+			key:  domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: subject, Key: "new-key"},
+			validate: func(t *testing.T, record domain.IdempotencyRecord, reserved bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, reserved)
+				assert.False(t, record.Completed())
+			},
+		},
+		{
+			name: "success - returns the completed key",
+			ctx:  ctx,
+			key:  domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: subject, Key: "completed-key"},
+			validate: func(t *testing.T, record domain.IdempotencyRecord, reserved bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, reserved)
+				assert.Equal(t, strings.Repeat("1", 64), record.Fingerprint)
+				assert.Equal(t, 201, record.StatusCode)
+				assert.Equal(t, "application/json", record.ContentType)
+				assert.JSONEq(t, `{"data":{"name":"soccer"}}`, string(record.Body))
+			},
+		},
+		{
+			name: "success - takes over an expired key",
+			ctx:  ctx,
+			key:  domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: subject, Key: "expired-key"},
+			validate: func(t *testing.T, record domain.IdempotencyRecord, reserved bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, reserved)
+				assert.Equal(t, fingerprint, record.Fingerprint)
+			},
+		},
+		{
+			name: "success - reserves a key used by another subject",
+			ctx:  ctx,
+			key:  domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: "api-key:22222222-2222-2222-2222-222222222222", Key: "completed-key"},
+			validate: func(t *testing.T, record domain.IdempotencyRecord, reserved bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, reserved)
+				assert.Equal(t, fingerprint, record.Fingerprint)
+			},
+		},
+		{
+			name: "success - reserves a key used in another tenant",
+			ctx:  ctx,
+			key:  domain.IdempotencyKey{Tenant: "acme", Subject: subject, Key: "completed-key"},
+			validate: func(t *testing.T, record domain.IdempotencyRecord, reserved bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, reserved)
+				assert.Equal(t, "acme", record.Tenant)
+			},
+		},
+		{
+			name: "cancelled context",
+			ctx:  cancelCtx,
+			key:  domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: subject, Key: "another-key"},
+			validate: func(t *testing.T, record domain.IdempotencyRecord, reserved bool, err error) {
+				assert.False(t, reserved)
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+	}
+
+	repo := NewIdempotencyRepository(testPool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, reserved, err := repo.Reserve(tt.ctx, tt.key, fingerprint, time.Hour)
+			tt.validate(t, record, reserved, err)
+		})
+	}
+}
+
+func TestIdempotencyRepository_CompleteAndRelease(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewIdempotencyRepository(testPool)
+	fingerprint := strings.Repeat("4", 64)
+	completeMe := domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: "user-42", Key: "complete-me"}
+	releaseMe := domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: "user-42", Key: "release-me"}
+
+	record, reserved, err := repo.Reserve(ctx, completeMe, fingerprint, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	record.StatusCode = 201
+	record.ContentType = "application/json"
+	record.Headers = map[string]string{"Location": "/api/v1/tags/1"}
+	record.Body = []byte(`{"data":{}}`)
+	assert.NoError(t, repo.Complete(ctx, record))
+
+	// A completed key is not released
+	assert.NoError(t, repo.Release(ctx, completeMe))
+	stored, reserved, err := repo.Reserve(ctx, completeMe, fingerprint, time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, 201, stored.StatusCode)
+	assert.Equal(t, map[string]string{"Location": "/api/v1/tags/1"}, stored.Headers)
+	assert.Equal(t, `{"data":{}}`, string(stored.Body))
+
+	// An in progress key is released and can be reserved again
+	_, reserved, err = repo.Reserve(ctx, releaseMe, fingerprint, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	assert.NoError(t, repo.Release(ctx, releaseMe))
+	_, reserved, err = repo.Reserve(ctx, releaseMe, fingerprint, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+}
+
+func TestIdempotencyRepository_DeleteExpired(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewIdempotencyRepository(testPool)
+
+	deleted, err := repo.DeleteExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	completed := domain.IdempotencyKey{Tenant: domain.DefaultTenant, Subject: "api-key:11111111-1111-1111-1111-111111111111", Key: "completed-key"}
+	_, reserved, err := repo.Reserve(ctx, completed, strings.Repeat("1", 64), time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+}
@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,7 +12,11 @@ import (
 	"github.com/peano88/medias/internal/domain"
 )
 
-// MediaRepository allows interaction with the media storage in postgres
+// MediaRepository allows interaction with the media storage in postgres.
+// Every query is scoped to the principal carried by the context: media are
+// only read when visible to it and only modified when owned by it. The tenant
+// isolation is enforced by the database, see NewPool, and repeated by the
+// queries which all filter on the tenant of the context.
 type MediaRepository struct {
 	pool *pgxpool.Pool
 }
@@ -23,13 +29,12 @@ func NewMediaRepository(pool *pgxpool.Pool) *MediaRepository {
 // FindByID finds a media record by ID
 func (mr *MediaRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Media, error) {
 	query := `
-		SELECT id, filename, description, status, type, mime_type, size, sha256, created_at, updated_at
-		FROM media
-		WHERE id = $1
-	`
+		SELECT m.id, m.filename, m.description, m.status, m.type, m.mime_type, m.size, m.sha256, m.owner, m.visibility, m.tenant_id, m.created_at, m.updated_at
+		FROM media m
+		WHERE m.id = $1 AND m.tenant_id = $3 AND ` + visibleMediaCondition(2)
 
 	var media domain.Media
-	err := mr.pool.QueryRow(ctx, query, id).Scan(
+	err := mr.pool.QueryRow(ctx, query, id, domain.SubjectFromContext(ctx), domain.TenantFromContext(ctx)).Scan(
 		&media.ID,
 		&media.Filename,
 		&media.Description,
@@ -38,6 +43,9 @@ func (mr *MediaRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.M
 		&media.MimeType,
 		&media.Size,
 		&media.SHA256,
+		&media.Owner,
+		&media.Visibility,
+		&media.Tenant,
 		&media.CreatedAt,
 		&media.UpdatedAt,
 	)
@@ -66,16 +74,17 @@ func (mr *MediaRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.M
 	return media, nil
 }
 
-// FindByFilenameAndSHA256 finds a media record by filename and sha256
+// FindByFilenameAndSHA256 finds a media record of the principal by filename
+// and sha256
 func (mr *MediaRepository) FindByFilenameAndSHA256(ctx context.Context, filename, sha256 string) (domain.Media, error) {
 	query := `
-		SELECT id, filename, description, status, type, mime_type, size, sha256, created_at, updated_at
+		SELECT id, filename, description, status, type, mime_type, size, sha256, owner, visibility, tenant_id, created_at, updated_at
 		FROM media
-		WHERE filename = $1 AND sha256 = $2
+		WHERE filename = $1 AND sha256 = $2 AND owner = $3 AND tenant_id = $4
 	`
 
 	var media domain.Media
-	err := mr.pool.QueryRow(ctx, query, filename, sha256).Scan(
+	err := mr.pool.QueryRow(ctx, query, filename, sha256, domain.SubjectFromContext(ctx), domain.TenantFromContext(ctx)).Scan(
 		&media.ID,
 		&media.Filename,
 		&media.Description,
@@ -84,6 +93,9 @@ func (mr *MediaRepository) FindByFilenameAndSHA256(ctx context.Context, filename
 		&media.MimeType,
 		&media.Size,
 		&media.SHA256,
+		&media.Owner,
+		&media.Visibility,
+		&media.Tenant,
 		&media.CreatedAt,
 		&media.UpdatedAt,
 	)
@@ -128,9 +140,9 @@ func (mr *MediaRepository) CreateMedia(ctx context.Context, media domain.Media,
 
 	// Insert media record
 	query := `
-		INSERT INTO media (filename, description, status, type, mime_type, size, sha256)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, filename, description, status, type, mime_type, size, sha256, created_at, updated_at
+		INSERT INTO media (filename, description, status, type, mime_type, size, sha256, owner, visibility, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, filename, description, status, type, mime_type, size, sha256, owner, visibility, tenant_id, created_at, updated_at
 	`
 
 	var created domain.Media
@@ -142,6 +154,9 @@ func (mr *MediaRepository) CreateMedia(ctx context.Context, media domain.Media,
 		media.MimeType,
 		media.Size,
 		media.SHA256,
+		media.Owner,
+		media.Visibility,
+		domain.TenantFromContext(ctx),
 	).Scan(
 		&created.ID,
 		&created.Filename,
@@ -151,6 +166,9 @@ func (mr *MediaRepository) CreateMedia(ctx context.Context, media domain.Media,
 		&created.MimeType,
 		&created.Size,
 		&created.SHA256,
+		&created.Owner,
+		&created.Visibility,
+		&created.Tenant,
 		&created.CreatedAt,
 		&created.UpdatedAt,
 	)
@@ -190,12 +208,12 @@ func (mr *MediaRepository) UpdateStatus(ctx context.Context, media domain.Media,
 	query := `
 		UPDATE media
 		SET status = $1, updated_at = NOW()
-		WHERE id = $2
+		WHERE id = $2 AND owner = $3 AND tenant_id = $4
 		RETURNING updated_at
 	`
 
 	var updatedAt time.Time
-	err := mr.pool.QueryRow(ctx, query, status, media.ID).Scan(&updatedAt)
+	err := mr.pool.QueryRow(ctx, query, status, media.ID, domain.SubjectFromContext(ctx), domain.TenantFromContext(ctx)).Scan(&updatedAt)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -218,17 +236,338 @@ func (mr *MediaRepository) UpdateStatus(ctx context.Context, media domain.Media,
 	return media, nil
 }
 
+// UpdateMediaDescription updates the description of a media only if it was
+// not modified since expectedUpdatedAt, in a single conditional statement
+func (mr *MediaRepository) UpdateMediaDescription(ctx context.Context, id uuid.UUID, description *string, expectedUpdatedAt time.Time) (domain.Media, error) {
+	query := `
+		UPDATE media
+		SET description = $2
+		WHERE id = $1 AND updated_at = $3 AND owner = $4 AND tenant_id = $5
+		RETURNING id, filename, description, status, type, mime_type, size, sha256, owner, visibility, tenant_id, created_at, updated_at
+	`
+
+	var media domain.Media
+	err := mr.pool.QueryRow(ctx, query, id, description, expectedUpdatedAt, domain.SubjectFromContext(ctx), domain.TenantFromContext(ctx)).Scan(
+		&media.ID,
+		&media.Filename,
+		&media.Description,
+		&media.Status,
+		&media.Type,
+		&media.MimeType,
+		&media.Size,
+		&media.SHA256,
+		&media.Owner,
+		&media.Visibility,
+		&media.Tenant,
+		&media.CreatedAt,
+		&media.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.Media{}, mr.unmodifiedMediaError(ctx, id)
+		}
+		return domain.Media{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to update media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	tags, err := mr.loadMediaTags(ctx, media.ID)
+	if err != nil {
+		return domain.Media{}, err
+	}
+	media.Tags = tags
+
+	return media, nil
+}
+
+// unmodifiedMediaError explains why a conditional update of a media matched
+// no row: the media is not visible to the principal, it belongs to another
+// owner or it was modified since the expected version
+func (mr *MediaRepository) unmodifiedMediaError(ctx context.Context, id uuid.UUID) error {
+	subject := domain.SubjectFromContext(ctx)
+	query := `SELECT m.owner = $2 FROM media m WHERE m.id = $1 AND m.tenant_id = $3 AND ` + visibleMediaCondition(2)
+
+	var owned bool
+	err := mr.pool.QueryRow(ctx, query, id, subject, domain.TenantFromContext(ctx)).Scan(&owned)
+	if err == pgx.ErrNoRows {
+		return domain.NewError(domain.NotFoundCode,
+			domain.WithMessage("media not found"),
+			domain.WithTS(time.Now()),
+		)
+	}
+	if err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if !owned {
+		return domain.NewError(domain.ForbiddenCode,
+			domain.WithMessage("media owned by another client"),
+			domain.WithDetails("only the owner of a media can modify it"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return domain.NewError(domain.PreconditionFailedCode,
+		domain.WithMessage("media was modified"),
+		domain.WithDetails("the media was modified since the expected version"),
+		domain.WithTS(time.Now()),
+	)
+}
+
+// FindByFilenamesAndSHA256s finds the media records of the principal matching
+// any of the filename and sha256 pairs of the given media, with a single query
+func (mr *MediaRepository) FindByFilenamesAndSHA256s(ctx context.Context, medias []domain.Media) ([]domain.Media, error) {
+	if len(medias) == 0 {
+		return []domain.Media{}, nil
+	}
+
+	filenames := make([]string, len(medias))
+	sha256s := make([]string, len(medias))
+	for i, media := range medias {
+		filenames[i] = media.Filename
+		sha256s[i] = media.SHA256
+	}
+
+	query := `
+		SELECT m.id, m.filename, m.description, m.status, m.type, m.mime_type, m.size, m.sha256, m.owner, m.visibility, m.tenant_id, m.created_at, m.updated_at
+		FROM media m
+		INNER JOIN unnest($1::text[], $2::text[]) AS k(filename, sha256)
+			ON m.filename = k.filename AND m.sha256 = k.sha256
+		WHERE m.owner = $3 AND m.tenant_id = $4
+	`
+
+	rows, err := mr.pool.Query(ctx, query, filenames, sha256s, domain.SubjectFromContext(ctx), domain.TenantFromContext(ctx))
+	if err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer rows.Close()
+
+	found, err := collectMedia(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mr.loadTagsForMedia(ctx, found); err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// CreateMediaBatch creates several media records with their tag associations
+// in a single transaction, using one statement per step rather than per item.
+// Results are aligned with items: an item referencing unknown tags or
+// conflicting with an existing media fails on its own without affecting the
+// others, while the returned error reports a failure of the whole batch.
+func (mr *MediaRepository) CreateMediaBatch(ctx context.Context, items []domain.MediaBatchItem) ([]domain.MediaBatchResult, error) {
+	results := make([]domain.MediaBatchResult, len(items))
+	if len(items) == 0 {
+		return results, nil
+	}
+
+	tx, err := mr.pool.Begin(ctx)
+	if err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to begin transaction"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	tagsByName, err := mr.findTagsByNames(ctx, tx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items referencing unknown tags are not inserted
+	var filenames, statuses, types, mimeTypes, sha256s, owners, visibilities []string
+	var descriptions []*string
+	var sizes []int64
+	toInsert := make(map[string]int, len(items))
+	for i, item := range items {
+		tags, ok := resolveTags(tagsByName, item.TagNames)
+		if !ok {
+			results[i].Err = domain.NewError(domain.InvalidEntityCode,
+				domain.WithMessage("some tags not found"),
+				domain.WithDetails("one or more tag names do not exist"),
+				domain.WithTS(time.Now()),
+			)
+			continue
+		}
+
+		toInsert[mediaKey(item.Media.Filename, item.Media.SHA256)] = i
+		results[i].Media.Tags = tags
+		filenames = append(filenames, item.Media.Filename)
+		descriptions = append(descriptions, item.Media.Description)
+		statuses = append(statuses, string(item.Media.Status))
+		types = append(types, string(item.Media.Type))
+		mimeTypes = append(mimeTypes, item.Media.MimeType)
+		sizes = append(sizes, item.Media.Size)
+		sha256s = append(sha256s, item.Media.SHA256)
+		owners = append(owners, item.Media.Owner)
+		visibilities = append(visibilities, string(item.Media.Visibility))
+	}
+
+	if len(toInsert) > 0 {
+		// Media created concurrently by another request are skipped and reported as conflicts
+		query := `
+			INSERT INTO media (filename, description, status, type, mime_type, size, sha256, owner, visibility, tenant_id)
+			SELECT *, $10::text FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::bigint[], $7::text[], $8::text[], $9::text[])
+			ON CONFLICT ON CONSTRAINT unq_tenant_owner_filename_sha256 DO NOTHING
+			RETURNING id, filename, description, status, type, mime_type, size, sha256, owner, visibility, tenant_id, created_at, updated_at
+		`
+
+		rows, err := tx.Query(ctx, query, filenames, descriptions, statuses, types, mimeTypes, sizes, sha256s, owners, visibilities, domain.TenantFromContext(ctx))
+		if err != nil {
+			return nil, domain.NewError(domain.InternalCode,
+				domain.WithMessage("failed to create media"),
+				domain.WithDetails(err.Error()),
+				domain.WithTS(time.Now()),
+			)
+		}
+
+		created, err := collectMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		var mediaIDs, tagIDs []uuid.UUID
+		for _, media := range created {
+			i := toInsert[mediaKey(media.Filename, media.SHA256)]
+			delete(toInsert, mediaKey(media.Filename, media.SHA256))
+
+			media.Tags = results[i].Media.Tags
+			for _, tag := range media.Tags {
+				mediaIDs = append(mediaIDs, media.ID)
+				tagIDs = append(tagIDs, tag.ID)
+			}
+			results[i].Media = media
+		}
+
+		for _, i := range toInsert {
+			results[i].Media = domain.Media{}
+			results[i].Err = domain.NewError(domain.ConflictCode,
+				domain.WithMessage("media already exists"),
+				domain.WithDetails("a media file with this filename and sha256 was created concurrently"),
+				domain.WithTS(time.Now()),
+			)
+		}
+
+		if len(mediaIDs) > 0 {
+			associateQuery := `
+				INSERT INTO media_tags (media_id, tag_id, tenant_id)
+				SELECT *, $3::text FROM unnest($1::uuid[], $2::uuid[])
+			`
+			if _, err := tx.Exec(ctx, associateQuery, mediaIDs, tagIDs, domain.TenantFromContext(ctx)); err != nil {
+				return nil, domain.NewError(domain.InternalCode,
+					domain.WithMessage("failed to associate tags"),
+					domain.WithDetails(err.Error()),
+					domain.WithTS(time.Now()),
+				)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to commit transaction"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return results, nil
+}
+
+// findTagsByNames loads all the tags referenced by the batch items, by name
+func (mr *MediaRepository) findTagsByNames(ctx context.Context, tx pgx.Tx, items []domain.MediaBatchItem) (map[string]domain.Tag, error) {
+	var names []string
+	for _, item := range items {
+		names = append(names, item.TagNames...)
+	}
+
+	tagsByName := make(map[string]domain.Tag)
+	if len(names) == 0 {
+		return tagsByName, nil
+	}
+
+	query := `
+		SELECT id, name, description, created_at, updated_at
+		FROM tags
+		WHERE name = ANY($1) AND tenant_id = $2
+	`
+
+	rows, err := tx.Query(ctx, query, names, domain.TenantFromContext(ctx))
+	if err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find tags"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer rows.Close()
+
+	tags, err := pgx.CollectRows(rows, pgx.RowToStructByName[domain.Tag])
+	if err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to collect tags"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	for _, tag := range tags {
+		tagsByName[tag.Name] = tag
+	}
+
+	return tagsByName, nil
+}
+
+// resolveTags returns the tags with the given names, or false when some are
+// unknown or repeated, matching the checks done by associateTags
+func resolveTags(tagsByName map[string]domain.Tag, names []string) ([]domain.Tag, bool) {
+	tags := make([]domain.Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		tag, ok := tagsByName[name]
+		if !ok || seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		tags = append(tags, tag)
+	}
+	return tags, true
+}
+
+// mediaKey identifies a media by its unique filename and sha256 pair
+func mediaKey(filename, sha256 string) string {
+	return filename + "\x00" + sha256
+}
+
 // loadMediaTags loads all tags associated with a media record
 func (mr *MediaRepository) loadMediaTags(ctx context.Context, mediaID uuid.UUID) ([]domain.Tag, error) {
 	query := `
 		SELECT t.id, t.name, t.description, t.created_at, t.updated_at
 		FROM tags t
 		INNER JOIN media_tags mt ON t.id = mt.tag_id
-		WHERE mt.media_id = $1
+		WHERE mt.media_id = $1 AND mt.tenant_id = $2 AND t.tenant_id = $2
 		ORDER BY t.name ASC
 	`
 
-	rows, err := mr.pool.Query(ctx, query, mediaID)
+	rows, err := mr.pool.Query(ctx, query, mediaID, domain.TenantFromContext(ctx))
 	if err != nil {
 		return nil, domain.NewError(domain.InternalCode,
 			domain.WithMessage("failed to load media tags"),
@@ -256,10 +595,10 @@ func (mr *MediaRepository) associateTags(ctx context.Context, tx pgx.Tx, mediaID
 	query := `
 		SELECT id, name, description, created_at, updated_at
 		FROM tags
-		WHERE name = ANY($1)
+		WHERE name = ANY($1) AND tenant_id = $2
 	`
 
-	rows, err := tx.Query(ctx, query, tagNames)
+	rows, err := tx.Query(ctx, query, tagNames, domain.TenantFromContext(ctx))
 	if err != nil {
 		return nil, domain.NewError(domain.InternalCode,
 			domain.WithMessage("failed to find tags"),
@@ -290,10 +629,10 @@ func (mr *MediaRepository) associateTags(ctx context.Context, tx pgx.Tx, mediaID
 	// Create media_tags associations
 	for _, tag := range tags {
 		insertQuery := `
-			INSERT INTO media_tags (media_id, tag_id)
-			VALUES ($1, $2)
+			INSERT INTO media_tags (media_id, tag_id, tenant_id)
+			VALUES ($1, $2, $3)
 		`
-		_, err := tx.Exec(ctx, insertQuery, mediaID, tag.ID)
+		_, err := tx.Exec(ctx, insertQuery, mediaID, tag.ID, domain.TenantFromContext(ctx))
 		if err != nil {
 			return nil, domain.NewError(domain.InternalCode,
 				domain.WithMessage("failed to associate tag"),
@@ -305,3 +644,320 @@ func (mr *MediaRepository) associateTags(ctx context.Context, tx pgx.Tx, mediaID
 
 	return tags, nil
 }
+
+// FindAllMedia retrieves paginated media matching the filter and returns the total count
+func (mr *MediaRepository) FindAllMedia(ctx context.Context, filter domain.MediaFilter, params domain.PaginationParams) ([]domain.Media, int, error) {
+	conditions, args := mediaFilterConditions(filter,
+		[]string{visibleMediaCondition(1), "m.tenant_id = $2"},
+		[]any{domain.SubjectFromContext(ctx), domain.TenantFromContext(ctx)})
+	where := strings.Join(conditions, " AND ")
+
+	// Get total count
+	var total int
+	countQuery := "SELECT COUNT(*) FROM media m WHERE " + where
+	err := mr.pool.QueryRow(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to count media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	// Get paginated results (ASC ordering for stable pagination)
+	query := fmt.Sprintf(`
+		SELECT m.id, m.filename, m.description, m.status, m.type, m.mime_type, m.size, m.sha256, m.owner, m.visibility, m.tenant_id, m.created_at, m.updated_at
+		FROM media m
+		WHERE %s
+		ORDER BY m.created_at ASC, m.id ASC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := mr.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to retrieve media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer rows.Close()
+
+	medias, err := collectMedia(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := mr.loadTagsForMedia(ctx, medias); err != nil {
+		return nil, 0, err
+	}
+
+	return medias, total, nil
+}
+
+// SearchMedia performs a full-text search over media filenames and descriptions,
+// narrowed down by the given filter. Results are ordered by relevance and the
+// total number of matches is returned alongside the requested page.
+func (mr *MediaRepository) SearchMedia(ctx context.Context, query string, filter domain.MediaFilter, params domain.PaginationParams) ([]domain.Media, int, error) {
+	conditions, args := mediaFilterConditions(filter,
+		[]string{"m.search_vector @@ q.query", visibleMediaCondition(2), "m.tenant_id = $3"},
+		[]any{query, domain.SubjectFromContext(ctx), domain.TenantFromContext(ctx)})
+	where := strings.Join(conditions, " AND ")
+
+	// Get total count
+	var total int
+	countQuery := `
+		SELECT COUNT(*)
+		FROM media m, websearch_to_tsquery($1) q(query)
+		WHERE ` + where
+	err := mr.pool.QueryRow(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to count media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	// Get paginated results, most relevant first (created_at keeps the order stable)
+	searchQuery := fmt.Sprintf(`
+		SELECT m.id, m.filename, m.description, m.status, m.type, m.mime_type, m.size, m.sha256, m.owner, m.visibility, m.tenant_id, m.created_at, m.updated_at
+		FROM media m, websearch_to_tsquery($1) q(query)
+		WHERE %s
+		ORDER BY ts_rank(m.search_vector, q.query) DESC, m.created_at ASC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := mr.pool.Query(ctx, searchQuery, args...)
+	if err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to search media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer rows.Close()
+
+	medias, err := collectMedia(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := mr.loadTagsForMedia(ctx, medias); err != nil {
+		return nil, 0, err
+	}
+
+	return medias, total, nil
+}
+
+// visibleMediaCondition is the SQL condition selecting the media m visible to
+// the subject bound at the given position: its own media, the public ones
+// and, for authenticated clients, the shared ones
+func visibleMediaCondition(subjectArg int) string {
+	return fmt.Sprintf(`(m.owner = $%[1]d OR m.visibility = 'public' OR (m.visibility = 'shared' AND $%[1]d <> ''))`, subjectArg)
+}
+
+// mediaFilterConditions appends the SQL conditions and positional arguments
+// matching the given filter to the provided ones. Media are aliased as m.
+func mediaFilterConditions(filter domain.MediaFilter, conditions []string, args []any) ([]string, []any) {
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("m.status = $%d", len(args)))
+	}
+
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		conditions = append(conditions, fmt.Sprintf("m.type = $%d", len(args)))
+	}
+
+	if len(filter.Tags) > 0 {
+		args = append(args, filter.Tags)
+		conditions = append(conditions, fmt.Sprintf(`m.id IN (
+			SELECT mt.media_id
+			FROM media_tags mt
+			INNER JOIN tags t ON t.id = mt.tag_id
+			WHERE t.name = ANY($%d)
+			GROUP BY mt.media_id
+			HAVING COUNT(DISTINCT t.name) = %d
+		)`, len(args), len(filter.Tags)))
+	}
+
+	if filter.TagsQuery != nil {
+		var condition string
+		condition, args = tagExprCondition(filter.TagsQuery, args)
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, args
+}
+
+// tagExprCondition translates a tag query into an SQL condition on media m.
+// Tag names are always bound as positional arguments.
+func tagExprCondition(expr domain.TagExpr, args []any) (string, []any) {
+	switch e := expr.(type) {
+	case domain.TagExprName:
+		args = append(args, e.Name)
+		return fmt.Sprintf(`EXISTS (
+			SELECT 1
+			FROM media_tags mt
+			INNER JOIN tags t ON t.id = mt.tag_id
+			WHERE mt.media_id = m.id AND t.name = $%d
+		)`, len(args)), args
+	case domain.TagExprNot:
+		inner, args := tagExprCondition(e.Expr, args)
+		return "NOT " + inner, args
+	case domain.TagExprAnd:
+		left, args := tagExprCondition(e.Left, args)
+		right, args := tagExprCondition(e.Right, args)
+		return "(" + left + " AND " + right + ")", args
+	case domain.TagExprOr:
+		left, args := tagExprCondition(e.Left, args)
+		right, args := tagExprCondition(e.Right, args)
+		return "(" + left + " OR " + right + ")", args
+	default:
+		// unknown nodes match nothing rather than everything
+		return "FALSE", args
+	}
+}
+
+// collectMedia scans media rows selected with the standard media column list
+func collectMedia(rows pgx.Rows) ([]domain.Media, error) {
+	medias, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Media, error) {
+		var media domain.Media
+		err := row.Scan(
+			&media.ID,
+			&media.Filename,
+			&media.Description,
+			&media.Status,
+			&media.Type,
+			&media.MimeType,
+			&media.Size,
+			&media.SHA256,
+			&media.Owner,
+			&media.Visibility,
+			&media.Tenant,
+			&media.CreatedAt,
+			&media.UpdatedAt,
+		)
+		return media, err
+	})
+	if err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to collect media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return medias, nil
+}
+
+// loadTagsForMedia loads the tags of several media records with a single query
+func (mr *MediaRepository) loadTagsForMedia(ctx context.Context, medias []domain.Media) error {
+	if len(medias) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(medias))
+	for i, media := range medias {
+		ids[i] = media.ID
+	}
+
+	query := `
+		SELECT mt.media_id, t.id, t.name, t.description, t.created_at, t.updated_at
+		FROM tags t
+		INNER JOIN media_tags mt ON t.id = mt.tag_id
+		WHERE mt.media_id = ANY($1) AND mt.tenant_id = $2 AND t.tenant_id = $2
+		ORDER BY t.name ASC
+	`
+
+	rows, err := mr.pool.Query(ctx, query, ids, domain.TenantFromContext(ctx))
+	if err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to load media tags"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer rows.Close()
+
+	tagsByMedia := make(map[uuid.UUID][]domain.Tag, len(medias))
+	for rows.Next() {
+		var mediaID uuid.UUID
+		var tag domain.Tag
+		if err := rows.Scan(&mediaID, &tag.ID, &tag.Name, &tag.Description, &tag.CreatedAt, &tag.UpdatedAt); err != nil {
+			return domain.NewError(domain.InternalCode,
+				domain.WithMessage("failed to collect tags"),
+				domain.WithDetails(err.Error()),
+				domain.WithTS(time.Now()),
+			)
+		}
+		tagsByMedia[mediaID] = append(tagsByMedia[mediaID], tag)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to collect tags"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	for i := range medias {
+		medias[i].Tags = tagsByMedia[medias[i].ID]
+		if medias[i].Tags == nil {
+			medias[i].Tags = []domain.Tag{}
+		}
+	}
+
+	return nil
+}
+
+// OldestReservedCreatedAt returns the creation time of the oldest media still
+// reserved across every tenant, false when there is none
+func (mr *MediaRepository) OldestReservedCreatedAt(ctx context.Context) (time.Time, bool, error) {
+	// The reserved media of every tenant count, the transaction lifts the
+	// tenant isolation
+	tx, err := mr.pool.Begin(ctx)
+	if err != nil {
+		return time.Time{}, false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to begin transaction"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, "SELECT set_config('app.all_tenants', 'on', true)"); err != nil {
+		return time.Time{}, false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find oldest reserved media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	var oldest *time.Time
+	if err := tx.QueryRow(ctx, "SELECT MIN(created_at) FROM media WHERE status = 'reserved'").Scan(&oldest); err != nil {
+		return time.Time{}, false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find oldest reserved media"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return time.Time{}, false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to commit transaction"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if oldest == nil {
+		return time.Time{}, false, nil
+	}
+	return *oldest, true, nil
+}
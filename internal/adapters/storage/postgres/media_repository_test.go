@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/peano88/medias/internal/domain"
@@ -406,7 +407,508 @@ func TestMediaRepository_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestMediaRepository_FindAllMedia(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		filter            domain.MediaFilter
+		params            domain.PaginationParams
+		expectedNames     []string
+		expectedTotal     int
+		expectedErrorCode string
+	}{
+		{
+			name:          "returns all media ordered by created_at asc",
+			ctx:           ctx,
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"world-cup-final.jpg", "tennis-serve.mp4", "hockey-goal.jpg"},
+			expectedTotal: 3,
+		},
+		{
+			name:          "filters by status and type",
+			ctx:           ctx,
+			filter:        domain.MediaFilter{Status: domain.MediaStatusReserved, Type: domain.MediaTypeVideo},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"tennis-serve.mp4"},
+			expectedTotal: 1,
+		},
+		{
+			name: "tags query with OR",
+			ctx:  ctx,
+			filter: domain.MediaFilter{TagsQuery: domain.TagExprOr{
+				Left:  domain.TagExprName{Name: "basketball"},
+				Right: domain.TagExprName{Name: "soccer"},
+			}},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"world-cup-final.jpg"},
+			expectedTotal: 1,
+		},
+		{
+			name: "tags query with AND NOT",
+			ctx:  ctx,
+			filter: domain.MediaFilter{TagsQuery: domain.TagExprAnd{
+				Left:  domain.TagExprName{Name: "soccer"},
+				Right: domain.TagExprNot{Expr: domain.TagExprName{Name: "football"}},
+			}},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{},
+			expectedTotal: 0,
+		},
+		{
+			name:          "tags query with NOT only",
+			ctx:           ctx,
+			filter:        domain.MediaFilter{TagsQuery: domain.TagExprNot{Expr: domain.TagExprName{Name: "soccer"}}},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"tennis-serve.mp4", "hockey-goal.jpg"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "tag names are bound, not interpolated",
+			ctx:           ctx,
+			filter:        domain.MediaFilter{TagsQuery: domain.TagExprName{Name: "soccer') OR TRUE --"}},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{},
+			expectedTotal: 0,
+		},
+		{
+			name:          "paginates results",
+			ctx:           ctx,
+			params:        domain.PaginationParams{Limit: 1, Offset: 1},
+			expectedNames: []string{"tennis-serve.mp4"},
+			expectedTotal: 3,
+		},
+		{
+			name:              "cancelled context",
+			ctx:               cancelCtx,
+			params:            domain.PaginationParams{Limit: 50},
+			expectedErrorCode: domain.InternalCode,
+		},
+	}
+
+	repo := NewMediaRepository(testPool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			medias, total, err := repo.FindAllMedia(tt.ctx, tt.filter, tt.params)
+			if tt.expectedErrorCode != "" {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, domainErr.Code)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTotal, total)
+			names := make([]string, len(medias))
+			for i, media := range medias {
+				names[i] = media.Filename
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestMediaRepository_SearchMedia(t *testing.T) {
+	resetDB(t)
+
+	// fixtures are loaded with triggers disabled: compute the search vectors
+	_, err := testPool.Exec(context.Background(), "UPDATE media SET search_vector = media_search_vector(filename, description)")
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		query             string
+		filter            domain.MediaFilter
+		params            domain.PaginationParams
+		expectedNames     []string
+		expectedTotal     int
+		expectedErrorCode string
+	}{
+		{
+			name:          "ranks filename matches first",
+			ctx:           ctx,
+			query:         "goal",
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"hockey-goal.jpg", "world-cup-final.jpg"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "stems english words",
+			ctx:           ctx,
+			query:         "goals",
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"hockey-goal.jpg", "world-cup-final.jpg"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "matches words inside filenames",
+			ctx:           ctx,
+			query:         "serve",
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"tennis-serve.mp4"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "filters by status",
+			ctx:           ctx,
+			query:         "goal",
+			filter:        domain.MediaFilter{Status: domain.MediaStatusFailed},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"hockey-goal.jpg"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "filters by type",
+			ctx:           ctx,
+			query:         "goal",
+			filter:        domain.MediaFilter{Type: domain.MediaTypeVideo},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{},
+			expectedTotal: 0,
+		},
+		{
+			name:          "filters by tags",
+			ctx:           ctx,
+			query:         "goal",
+			filter:        domain.MediaFilter{Tags: []string{"soccer", "football"}},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{"world-cup-final.jpg"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "requires all tags",
+			ctx:           ctx,
+			query:         "goal",
+			filter:        domain.MediaFilter{Tags: []string{"soccer", "basketball"}},
+			params:        domain.PaginationParams{Limit: 50},
+			expectedNames: []string{},
+			expectedTotal: 0,
+		},
+		{
+			name:          "paginates results",
+			ctx:           ctx,
+			query:         "goal",
+			params:        domain.PaginationParams{Limit: 1, Offset: 1},
+			expectedNames: []string{"world-cup-final.jpg"},
+			expectedTotal: 2,
+		},
+		{
+			name:              "cancelled context",
+			ctx:               cancelCtx,
+			query:             "goal",
+			params:            domain.PaginationParams{Limit: 50},
+			expectedErrorCode: domain.InternalCode,
+		},
+	}
+
+	repo := NewMediaRepository(testPool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			medias, total, err := repo.SearchMedia(tt.ctx, tt.query, tt.filter, tt.params)
+			if tt.expectedErrorCode != "" {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, domainErr.Code)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedTotal, total)
+			assert.NotNil(t, medias)
+			names := make([]string, len(medias))
+			for i, media := range medias {
+				names[i] = media.Filename
+				assert.NotNil(t, media.Tags)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
 // Helper function
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestMediaRepository_FindByFilenamesAndSHA256s(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewMediaRepository(testPool)
+
+	t.Run("success - finds only matching pairs with tags", func(t *testing.T) {
+		result, err := repo.FindByFilenamesAndSHA256s(ctx, []domain.Media{
+			{Filename: "world-cup-final.jpg", SHA256: "w0rldcup2023"},
+			{Filename: "tennis-serve.mp4", SHA256: "t3nn1ss3rv3"},
+			{Filename: "world-cup-final.jpg", SHA256: "wrong-sha"},
+			{Filename: "nonexistent.jpg", SHA256: "n0n3x1st"},
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, result, 2) {
+			byName := map[string]domain.Media{}
+			for _, media := range result {
+				byName[media.Filename] = media
+			}
+			assert.Len(t, byName["world-cup-final.jpg"].Tags, 2)
+			assert.Equal(t, domain.MediaStatusReserved, byName["tennis-serve.mp4"].Status)
+			assert.NotNil(t, byName["tennis-serve.mp4"].Tags)
+		}
+	})
+
+	t.Run("success - empty input", func(t *testing.T) {
+		result, err := repo.FindByFilenamesAndSHA256s(ctx, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+}
+
+func TestMediaRepository_CreateMediaBatch(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	reserved := func(filename, sha256 string) domain.Media {
+		return domain.Media{
+			Filename: filename,
+			Status:   domain.MediaStatusReserved,
+			Type:     domain.MediaTypeImage,
+			MimeType: "image/jpeg",
+			Size:     1000000,
+			SHA256:   sha256,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		items    []domain.MediaBatchItem
+		validate func(*testing.T, []domain.MediaBatchResult, error)
+	}{
+		{
+			name: "success - creates items and reports item errors",
+			ctx:  ctx,
+			items: []domain.MediaBatchItem{
+				{Media: reserved("marathon-finish.jpg", "m4r4th0n"), TagNames: []string{"soccer", "basketball"}},
+				{Media: reserved("badminton-smash.jpg", "b4dm1nt0n"), TagNames: []string{"nonexistent-tag"}},
+				{Media: reserved("world-cup-final.jpg", "w0rldcup2023")},
+				{Media: reserved("volleyball-spike.jpg", "v0ll3yb4ll")},
+			},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.NoError(t, err)
+				if !assert.Len(t, results, 4) {
+					return
+				}
+
+				assert.NoError(t, results[0].Err)
+				assert.NotEmpty(t, results[0].Media.ID)
+				assert.Equal(t, "marathon-finish.jpg", results[0].Media.Filename)
+				assert.Len(t, results[0].Media.Tags, 2)
+
+				assert.True(t, domain.HasCode(results[1].Err, domain.InvalidEntityCode))
+				assert.True(t, domain.HasCode(results[2].Err, domain.ConflictCode))
+
+				assert.NoError(t, results[3].Err)
+				assert.Equal(t, "volleyball-spike.jpg", results[3].Media.Filename)
+				assert.NotNil(t, results[3].Media.Tags)
+				assert.Len(t, results[3].Media.Tags, 0)
+
+				// Created media are persisted with their tags
+				repo := NewMediaRepository(testPool)
+				created, err := repo.FindByFilenameAndSHA256(ctx, "marathon-finish.jpg", "m4r4th0n")
+				assert.NoError(t, err)
+				assert.Len(t, created.Tags, 2)
+
+				_, err = repo.FindByFilenameAndSHA256(ctx, "badminton-smash.jpg", "b4dm1nt0n")
+				assert.True(t, domain.HasCode(err, domain.NotFoundCode))
+			},
+		},
+		{
+			name: "cancelled context",
+			ctx:  cancelCtx,
+			items: []domain.MediaBatchItem{
+				{Media: reserved("rugby-tackle.jpg", "rugbyt4ckl3")},
+			},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.Nil(t, results)
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InternalCode, domainErr.Code)
+				}
+			},
+		},
+	}
+
+	repo := NewMediaRepository(testPool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.CreateMediaBatch(tt.ctx, tt.items)
+			tt.validate(t, results, err)
+		})
+	}
+}
+
+func TestMediaRepository_UpdateMediaDescription(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewMediaRepository(testPool)
+	mediaID := uuid.MustParse("111e1111-e11b-11d1-a111-111111111111")
+	fixtureUpdatedAt := time.Date(2023, 6, 1, 11, 0, 0, 0, time.UTC)
+
+	updated, err := repo.UpdateMediaDescription(ctx, mediaID, stringPtr("Winning goal"), fixtureUpdatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "Winning goal", *updated.Description)
+	assert.Len(t, updated.Tags, 2)
+	assert.True(t, updated.UpdatedAt.After(fixtureUpdatedAt))
+
+	// The version returned by the update is the one to use next
+	_, err = repo.UpdateMediaDescription(ctx, mediaID, nil, fixtureUpdatedAt)
+	assert.True(t, domain.HasCode(err, domain.PreconditionFailedCode))
+
+	cleared, err := repo.UpdateMediaDescription(ctx, mediaID, nil, updated.UpdatedAt)
+	assert.NoError(t, err)
+	assert.Nil(t, cleared.Description)
+
+	_, err = repo.UpdateMediaDescription(ctx, uuid.MustParse("999e9999-e99b-99d9-a999-999999999999"), nil, fixtureUpdatedAt)
+	assert.True(t, domain.HasCode(err, domain.NotFoundCode))
+}
+
+func TestMediaRepository_Ownership(t *testing.T) {
+	resetDB(t)
+
+	anonymous := context.Background()
+	alice := domain.ContextWithPrincipal(anonymous, domain.Principal{Subject: "alice"})
+	bob := domain.ContextWithPrincipal(anonymous, domain.Principal{Subject: "bob"})
+
+	privateID := uuid.MustParse("444e4444-e44b-44d4-a444-444444444444")
+	sharedID := uuid.MustParse("555e5555-e55b-55d5-a555-555555555555")
+	unownedID := uuid.MustParse("111e1111-e11b-11d1-a111-111111111111")
+
+	repo := NewMediaRepository(testPool)
+
+	t.Run("find by ID is scoped to the visible media", func(t *testing.T) {
+		tests := []struct {
+			name string
+			ctx  context.Context
+			id   uuid.UUID
+			code string
+		}{
+			{name: "owner sees its private media", ctx: alice, id: privateID},
+			{name: "others do not see private media", ctx: bob, id: privateID, code: domain.NotFoundCode},
+			{name: "authenticated clients see shared media", ctx: bob, id: sharedID},
+			{name: "anonymous clients do not see shared media", ctx: anonymous, id: sharedID, code: domain.NotFoundCode},
+			{name: "anonymous clients see media created anonymously", ctx: anonymous, id: unownedID},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				media, err := repo.FindByID(tt.ctx, tt.id)
+				if tt.code != "" {
+					assert.True(t, domain.HasCode(err, tt.code), "unexpected error: %v", err)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, media.ID)
+			})
+		}
+	})
+
+	t.Run("filename and sha256 are unique per owner", func(t *testing.T) {
+		media, err := repo.FindByFilenameAndSHA256(alice, "world-cup-final.jpg", "w0rldcup2023")
+		assert.NoError(t, err)
+		assert.Equal(t, privateID, media.ID)
+		assert.Equal(t, "alice", media.Owner)
+		assert.Equal(t, domain.VisibilityPrivate, media.Visibility)
+
+		_, err = repo.FindByFilenameAndSHA256(bob, "world-cup-final.jpg", "w0rldcup2023")
+		assert.True(t, domain.HasCode(err, domain.NotFoundCode))
+
+		created, err := repo.CreateMedia(bob, domain.Media{
+			Filename:   "world-cup-final.jpg",
+			Status:     domain.MediaStatusReserved,
+			Type:       domain.MediaTypeImage,
+			MimeType:   "image/jpeg",
+			Size:       2048000,
+			SHA256:     "w0rldcup2023",
+			Owner:      "bob",
+			Visibility: domain.VisibilityPublic,
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "bob", created.Owner)
+
+		// public media are visible to anyone
+		_, err = repo.FindByID(anonymous, created.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("only the owner modifies a media", func(t *testing.T) {
+		_, err := repo.UpdateMediaDescription(bob, sharedID, stringPtr("jump"), time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC))
+		assert.True(t, domain.HasCode(err, domain.ForbiddenCode), "unexpected error: %v", err)
+
+		_, err = repo.UpdateMediaDescription(bob, privateID, stringPtr("goal"), time.Date(2023, 6, 4, 10, 0, 0, 0, time.UTC))
+		assert.True(t, domain.HasCode(err, domain.NotFoundCode), "unexpected error: %v", err)
+
+		_, err = repo.UpdateStatus(bob, domain.Media{ID: sharedID}, domain.MediaStatusFailed)
+		assert.True(t, domain.HasCode(err, domain.NotFoundCode), "unexpected error: %v", err)
+
+		updated, err := repo.UpdateMediaDescription(alice, sharedID, stringPtr("jump"), time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, "jump", *updated.Description)
+	})
+
+	t.Run("listings only include the visible media", func(t *testing.T) {
+		all := domain.PaginationParams{Limit: 100}
+
+		_, anonymousTotal, err := repo.FindAllMedia(anonymous, domain.MediaFilter{}, all)
+		assert.NoError(t, err)
+		_, aliceTotal, err := repo.FindAllMedia(alice, domain.MediaFilter{}, all)
+		assert.NoError(t, err)
+		_, bobTotal, err := repo.FindAllMedia(bob, domain.MediaFilter{}, all)
+		assert.NoError(t, err)
+
+		// the fixtures created anonymously and the public media of bob
+		assert.Equal(t, 4, anonymousTotal)
+		// her two media and the public one
+		assert.Equal(t, 3, aliceTotal)
+		// the shared media of alice and his public one
+		assert.Equal(t, 2, bobTotal)
+	})
+}
+
+func TestMediaRepository_OldestReservedCreatedAt(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewMediaRepository(testPool)
+
+	oldest, found, err := repo.OldestReservedCreatedAt(ctx)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, time.Date(2023, 6, 2, 14, 0, 0, 0, time.UTC), oldest.UTC())
+
+	_, err = repo.UpdateStatus(ctx, domain.Media{ID: uuid.MustParse("222e2222-e22b-22d2-a222-222222222222")}, domain.MediaStatusFinalized)
+	assert.NoError(t, err)
+
+	_, found, err = repo.OldestReservedCreatedAt(ctx)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err = repo.OldestReservedCreatedAt(cancelCtx)
+	assert.True(t, domain.HasCode(err, domain.InternalCode))
+}
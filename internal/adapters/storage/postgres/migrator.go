@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/migrations"
+	"github.com/pressly/goose/v3"
+)
+
+// Migration describes an embedded migration and whether it is applied
+type Migration struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies the migrations embedded in the binary. Each operation
+// holds an advisory lock, the replicas migrating at the same time wait for
+// each other instead of racing.
+type Migrator struct {
+	pool     *pgxpool.Pool
+	provider *goose.Provider
+}
+
+// NewMigrator creates a new Migrator running the migrations through the pool
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	provider, err := newMigrationsProvider(pool)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, provider: provider}, nil
+}
+
+func newMigrationsProvider(pool *pgxpool.Pool) (*goose.Provider, error) {
+	return goose.NewProvider(goose.DialectPostgres, stdlib.OpenDBFromPool(pool), migrations.FS)
+}
+
+// Up applies all the pending migrations and returns them
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		results, err := m.provider.Up(ctx)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			applied = append(applied, migrationFrom(result.Source, true))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, migrationError("failed to apply migrations", err)
+	}
+	return applied, nil
+}
+
+// Down rolls back the last applied migration and returns it, false when none
+// is applied
+func (m *Migrator) Down(ctx context.Context) (Migration, bool, error) {
+	var rolledBack Migration
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		result, err := m.provider.Down(ctx)
+		if err != nil {
+			return err
+		}
+		rolledBack = migrationFrom(result.Source, false)
+		return nil
+	})
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return Migration{}, false, nil
+	}
+	if err != nil {
+		return Migration{}, false, migrationError("failed to roll back migration", err)
+	}
+	return rolledBack, true, nil
+}
+
+// Redo rolls back the last applied migration and applies it again, false
+// when none is applied
+func (m *Migrator) Redo(ctx context.Context) (Migration, bool, error) {
+	var redone Migration
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		result, err := m.provider.Down(ctx)
+		if err != nil {
+			return err
+		}
+		result, err = m.provider.ApplyVersion(ctx, result.Source.Version, true)
+		if err != nil {
+			return err
+		}
+		redone = migrationFrom(result.Source, true)
+		return nil
+	})
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		return Migration{}, false, nil
+	}
+	if err != nil {
+		return Migration{}, false, migrationError("failed to redo migration", err)
+	}
+	return redone, true, nil
+}
+
+// Status lists the embedded migrations, ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, migrationError("failed to read migrations status", err)
+	}
+
+	result := make([]Migration, 0, len(statuses))
+	for _, status := range statuses {
+		migration := migrationFrom(status.Source, status.State == goose.StateApplied)
+		migration.AppliedAt = status.AppliedAt
+		result = append(result, migration)
+	}
+	return result, nil
+}
+
+// withLock runs fn while holding the migrations advisory lock, waiting for
+// it if another replica holds it
+func (m *Migrator) withLock(ctx context.Context, fn func(context.Context) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if err := migrationsLock.lock(ctx, conn); err != nil {
+		return err
+	}
+	defer migrationsLock.unlock(conn)
+
+	return fn(ctx)
+}
+
+func migrationFrom(source *goose.Source, applied bool) Migration {
+	return Migration{
+		Version: source.Version,
+		Name:    strings.TrimSuffix(filepath.Base(source.Path), filepath.Ext(source.Path)),
+		Applied: applied,
+	}
+}
+
+func migrationError(message string, err error) error {
+	return domain.NewError(domain.InternalCode,
+		domain.WithMessage(message),
+		domain.WithDetails(err.Error()),
+		domain.WithTS(time.Now()),
+	)
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator_Status(t *testing.T) {
+	migrator, err := NewMigrator(testPool)
+	require.NoError(t, err)
+
+	migrations, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, migrations, 15)
+
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "00001_create_tags_table", migrations[0].Name)
+	for _, migration := range migrations {
+		assert.True(t, migration.Applied, migration.Name)
+		assert.False(t, migration.AppliedAt.IsZero(), migration.Name)
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	migrator, err := NewMigrator(testPool)
+	require.NoError(t, err)
+
+	// the replicas starting together wait for each other, then find nothing
+	// left to apply
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			applied, err := migrator.Up(context.Background())
+			assert.Empty(t, applied)
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = migrator.Up(cancelCtx)
+	assert.Error(t, err)
+}
@@ -2,9 +2,12 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
 )
 
 // NewPool creates a new PostgreSQL connection pool with the given configuration
@@ -21,6 +24,16 @@ func NewPool(ctx context.Context, cfg *Config) (*pgxpool.Pool, error) {
 	poolConfig.MaxConnLifetime = time.Duration(cfg.MaxConnLifetimeMin) * time.Minute
 	poolConfig.MaxConnIdleTime = time.Duration(cfg.MaxConnIdleTimeMin) * time.Minute
 
+	// Used by the media search vector trigger and queries
+	if cfg.TextSearchConfig != "" {
+		poolConfig.ConnConfig.RuntimeParams["default_text_search_config"] = cfg.TextSearchConfig
+	}
+
+	// Isolates the data of the tenants
+	poolConfig.PrepareConn = setTenant
+
+	poolConfig.ConnConfig.Tracer = queryTracer{}
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
@@ -34,3 +47,45 @@ func NewPool(ctx context.Context, cfg *Config) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
+
+// setTenant binds each connection handed out by the pool to the tenant of the
+// context it is acquired for, which the row-level security policies of the
+// tenant data rely on
+func setTenant(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	if _, err := conn.Exec(ctx, "SELECT set_config('app.tenant_id', $1, false)", domain.TenantFromContext(ctx)); err != nil {
+		// the state of the connection is unknown, it is discarded
+		return false, err
+	}
+	return true, nil
+}
+
+// CheckTenantIsolation verifies the role the pool connects as is subject to
+// the row-level security policies isolating the tenants. Superusers and the
+// roles with BYPASSRLS are exempted from them: the service must not connect
+// as one.
+func CheckTenantIsolation(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		SELECT current_user, current_setting('is_superuser') = 'on', rolbypassrls
+		FROM pg_roles
+		WHERE rolname = current_user
+	`
+
+	var role string
+	var superuser, bypassRLS bool
+	if err := pool.QueryRow(ctx, query).Scan(&role, &superuser, &bypassRLS); err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to check the database role"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if superuser || bypassRLS {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("the database role bypasses the tenant isolation"),
+			domain.WithDetails(fmt.Sprintf("the role %s is a superuser or has BYPASSRLS, the service must connect as a role which is neither", role)),
+			domain.WithTS(time.Now()),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSuperuserPool connects as a superuser, which bypasses the row-level
+// security policies
+func newSuperuserPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	poolConfig, err := pgxpool.ParseConfig(superuserURL)
+	require.NoError(t, err)
+	poolConfig.PrepareConn = setTenant
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestCheckTenantIsolation(t *testing.T) {
+	assert.NoError(t, CheckTenantIsolation(context.Background(), testPool))
+
+	err := CheckTenantIsolation(context.Background(), newSuperuserPool(t))
+	assert.True(t, domain.HasCode(err, domain.InternalCode), "unexpected error: %v", err)
+	assert.ErrorContains(t, err, "bypasses the tenant isolation")
+}
+
+func TestTenantIsolation(t *testing.T) {
+	resetDB(t)
+
+	defaultTenant := context.Background()
+	acme := domain.ContextWithTenant(context.Background(), "acme")
+
+	tagRepo := NewTagRepository(testPool)
+	mediaRepo := NewMediaRepository(testPool)
+
+	defaultMediaID := uuid.MustParse("111e1111-e11b-11d1-a111-111111111111")
+	acmeMediaID := uuid.MustParse("666e6666-e66b-66d6-a666-666666666666")
+	acmeSoccerID := uuid.MustParse("423e4567-e89b-12d3-a456-426614174000")
+
+	t.Run("reads only return the data of the tenant", func(t *testing.T) {
+		tags, total, err := tagRepo.FindAllTags(acme, domain.PaginationParams{Limit: 50})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		if assert.Len(t, tags, 1) {
+			assert.Equal(t, acmeSoccerID, tags[0].ID)
+		}
+
+		_, total, err = tagRepo.FindAllTags(defaultTenant, domain.PaginationParams{Limit: 50})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+
+		media, err := mediaRepo.FindByID(acme, acmeMediaID)
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", media.Tenant)
+		if assert.Len(t, media.Tags, 1) {
+			assert.Equal(t, acmeSoccerID, media.Tags[0].ID)
+		}
+
+		_, err = mediaRepo.FindByID(acme, defaultMediaID)
+		assert.True(t, domain.HasCode(err, domain.NotFoundCode), "unexpected error: %v", err)
+		_, err = mediaRepo.FindByID(defaultTenant, acmeMediaID)
+		assert.True(t, domain.HasCode(err, domain.NotFoundCode), "unexpected error: %v", err)
+	})
+
+	t.Run("queries filter on the tenant without the policies", func(t *testing.T) {
+		superuserPool := newSuperuserPool(t)
+
+		_, total, err := NewTagRepository(superuserPool).FindAllTags(acme, domain.PaginationParams{Limit: 50})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+
+		_, err = NewMediaRepository(superuserPool).FindByID(acme, defaultMediaID)
+		assert.True(t, domain.HasCode(err, domain.NotFoundCode), "unexpected error: %v", err)
+		media, total, err := NewMediaRepository(superuserPool).FindAllMedia(acme, domain.MediaFilter{}, domain.PaginationParams{Limit: 50})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		if assert.Len(t, media, 1) {
+			assert.Equal(t, acmeMediaID, media[0].ID)
+			assert.Len(t, media[0].Tags, 1)
+		}
+	})
+
+	t.Run("tag names are unique per tenant", func(t *testing.T) {
+		created, err := tagRepo.CreateTag(acme, domain.Tag{Name: "basketball"})
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, created.ID)
+
+		_, err = tagRepo.CreateTag(acme, domain.Tag{Name: "soccer"})
+		assert.True(t, domain.HasCode(err, domain.ConflictCode), "unexpected error: %v", err)
+	})
+
+	t.Run("media are associated with the tags of their tenant", func(t *testing.T) {
+		created, err := mediaRepo.CreateMedia(acme, domain.Media{
+			Filename: "acme-kick-off.jpg",
+			Status:   domain.MediaStatusReserved,
+			Type:     domain.MediaTypeImage,
+			MimeType: "image/jpeg",
+			Size:     1000,
+			SHA256:   "4cm3k1ck",
+		}, []string{"soccer"})
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", created.Tenant)
+		if assert.Len(t, created.Tags, 1) {
+			assert.Equal(t, acmeSoccerID, created.Tags[0].ID)
+		}
+	})
+
+	t.Run("writes cannot reach another tenant", func(t *testing.T) {
+		_, err := tagRepo.UpdateTagDescription(acme, uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"), nil, time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC))
+		assert.True(t, domain.HasCode(err, domain.NotFoundCode), "unexpected error: %v", err)
+
+		_, err = testPool.Exec(acme, "INSERT INTO tags (name, tenant_id) VALUES ('smuggled', 'default')")
+		assert.Error(t, err)
+	})
+
+	t.Run("the co-occurrences are refreshed for every tenant", func(t *testing.T) {
+		refreshed, err := tagRepo.RefreshTagCooccurrences(acme)
+		assert.NoError(t, err)
+		assert.True(t, refreshed)
+
+		related, err := tagRepo.FindRelatedTags(defaultTenant, "soccer", 10)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, related)
+	})
+}
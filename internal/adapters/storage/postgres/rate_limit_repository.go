@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// RateLimitRepository stores the token buckets of the rate limiting, so that
+// the replicas of the service share them
+type RateLimitRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRateLimitRepository creates a new RateLimitRepository with the given connection pool
+func NewRateLimitRepository(pool *pgxpool.Pool) *RateLimitRepository {
+	return &RateLimitRepository{pool: pool}
+}
+
+// Take atomically refills the bucket identified by key for the time elapsed
+// since its last request and takes a token from it. A missing bucket is
+// created full.
+func (rr *RateLimitRepository) Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitDecision, error) {
+	query := `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+		VALUES ($1, $3::DOUBLE PRECISION - 1, TRUE, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET (tokens, allowed, updated_at) = (
+			SELECT
+				CASE WHEN refilled >= 1 THEN refilled - 1 ELSE refilled END,
+				refilled >= 1,
+				NOW()
+			FROM (
+				SELECT LEAST($3::DOUBLE PRECISION,
+					b.tokens + GREATEST(0, EXTRACT(EPOCH FROM NOW() - b.updated_at)) * $2::DOUBLE PRECISION) AS refilled
+			) AS r
+		)
+		RETURNING tokens, allowed
+	`
+
+	var tokens float64
+	var allowed bool
+	err := rr.pool.QueryRow(ctx, query, key, limit.Rate, float64(limit.Burst)).Scan(&tokens, &allowed)
+	if err != nil {
+		return domain.RateLimitDecision{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to take rate limit token"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return limit.Decision(tokens, allowed), nil
+}
+
+// DeleteIdle purges the buckets without request for idle and returns how many
+// were deleted. Such buckets are full again, unless their limit refills slower.
+func (rr *RateLimitRepository) DeleteIdle(ctx context.Context, idle time.Duration) (int64, error) {
+	query := `
+		DELETE FROM rate_limit_buckets
+		WHERE updated_at <= NOW() - make_interval(secs => $1)
+	`
+
+	tag, err := rr.pool.Exec(ctx, query, idle.Seconds())
+	if err != nil {
+		return 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to delete idle rate limit buckets"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitRepository_Take(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewRateLimitRepository(testPool)
+
+	// a slow refill keeps the elapsed time between the requests negligible
+	limit := domain.RateLimit{Rate: 0.001, Burst: 2}
+
+	decision, err := repo.Take(ctx, "POST /media|take", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 2, decision.Limit)
+	assert.Equal(t, 1, decision.Remaining)
+
+	decision, err = repo.Take(ctx, "POST /media|take", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+
+	decision, err = repo.Take(ctx, "POST /media|take", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Greater(t, decision.RetryAfter, 15*time.Minute)
+
+	// other clients have their own bucket
+	decision, err = repo.Take(ctx, "POST /media|other", limit)
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = repo.Take(cancelCtx, "POST /media|take", limit)
+	assert.True(t, domain.HasCode(err, domain.InternalCode))
+}
+
+func TestRateLimitRepository_DeleteIdle(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewRateLimitRepository(testPool)
+
+	limit := domain.RateLimit{Rate: 1, Burst: 1}
+	_, err := repo.Take(ctx, "GET /tags|idle", limit)
+	require.NoError(t, err)
+
+	_, err = testPool.Exec(ctx, `UPDATE rate_limit_buckets SET updated_at = NOW() - INTERVAL '1 hour' WHERE key = $1`, "GET /tags|idle")
+	require.NoError(t, err)
+
+	_, err = repo.Take(ctx, "GET /tags|active", limit)
+	require.NoError(t, err)
+
+	deleted, err := repo.DeleteIdle(ctx, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	decision, err := repo.Take(ctx, "GET /tags|active", limit)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+}
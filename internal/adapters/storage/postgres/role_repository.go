@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
+)
+
+const roleAssignmentColumns = "subject, role, tenant_id, created_at, updated_at"
+
+// RoleRepository stores the roles granted to the principals
+type RoleRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRoleRepository creates a new RoleRepository with the given connection pool
+func NewRoleRepository(pool *pgxpool.Pool) *RoleRepository {
+	return &RoleRepository{pool: pool}
+}
+
+// AssignRole grants the role to the subject in the tenant of the assignment,
+// replacing the role it held there
+func (rr *RoleRepository) AssignRole(ctx context.Context, assignment domain.RoleAssignment) (domain.RoleAssignment, error) {
+	query := `
+		INSERT INTO role_assignments (subject, role, tenant_id)
+		VALUES ($1, $2, NULLIF($3, ''))
+		ON CONFLICT (subject, (COALESCE(tenant_id, ''))) DO UPDATE
+		SET role = EXCLUDED.role, updated_at = NOW()
+		RETURNING ` + roleAssignmentColumns
+
+	assigned, err := scanRoleAssignment(rr.pool.QueryRow(ctx, query, assignment.Subject, assignment.Role, assignment.Tenant))
+	if err != nil {
+		return domain.RoleAssignment{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to assign role"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return assigned, nil
+}
+
+// FindRoleAssignment retrieves the role of the subject in the tenant, falling
+// back on the role it holds in every tenant
+func (rr *RoleRepository) FindRoleAssignment(ctx context.Context, subject, tenant string) (domain.RoleAssignment, error) {
+	query := `
+		SELECT ` + roleAssignmentColumns + `
+		FROM role_assignments
+		WHERE subject = $1 AND (tenant_id = $2 OR tenant_id IS NULL)
+		ORDER BY tenant_id NULLS LAST
+		LIMIT 1
+	`
+
+	assignment, err := scanRoleAssignment(rr.pool.QueryRow(ctx, query, subject, tenant))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.RoleAssignment{}, domain.NewError(domain.NotFoundCode,
+				domain.WithMessage("role assignment not found"),
+				domain.WithTS(time.Now()),
+			)
+		}
+		return domain.RoleAssignment{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find role assignment"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return assignment, nil
+}
+
+// FindAllRoleAssignments retrieves paginated role assignments and returns the
+// total count. When tenant is not empty, only the assignments in that tenant
+// are retrieved.
+func (rr *RoleRepository) FindAllRoleAssignments(ctx context.Context, tenant string, params domain.PaginationParams) ([]domain.RoleAssignment, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM role_assignments WHERE $1 = '' OR tenant_id = $1`
+	if err := rr.pool.QueryRow(ctx, countQuery, tenant).Scan(&total); err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to count role assignments"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	query := `
+		SELECT ` + roleAssignmentColumns + `
+		FROM role_assignments
+		WHERE $1 = '' OR tenant_id = $1
+		ORDER BY created_at ASC, subject ASC, tenant_id ASC NULLS FIRST
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := rr.pool.Query(ctx, query, tenant, params.Limit, params.Offset)
+	if err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to retrieve role assignments"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer rows.Close()
+
+	assignments := []domain.RoleAssignment{}
+	for rows.Next() {
+		assignment, err := scanRoleAssignment(rows)
+		if err != nil {
+			return nil, 0, domain.NewError(domain.InternalCode,
+				domain.WithMessage("failed to collect role assignments"),
+				domain.WithDetails(err.Error()),
+				domain.WithTS(time.Now()),
+			)
+		}
+		assignments = append(assignments, assignment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to collect role assignments"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return assignments, total, nil
+}
+
+// UnassignRole removes the role of the subject in the tenant, empty meaning
+// the role held in every tenant
+func (rr *RoleRepository) UnassignRole(ctx context.Context, subject, tenant string) error {
+	query := `DELETE FROM role_assignments WHERE subject = $1 AND COALESCE(tenant_id, '') = $2`
+
+	tag, err := rr.pool.Exec(ctx, query, subject, tenant)
+	if err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to unassign role"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.NewError(domain.NotFoundCode,
+			domain.WithMessage("role assignment not found"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return nil
+}
+
+func scanRoleAssignment(row pgx.Row) (domain.RoleAssignment, error) {
+	var assignment domain.RoleAssignment
+	var tenant *string
+	err := row.Scan(
+		&assignment.Subject,
+		&assignment.Role,
+		&tenant,
+		&assignment.CreatedAt,
+		&assignment.UpdatedAt,
+	)
+	if err != nil {
+		return domain.RoleAssignment{}, err
+	}
+
+	if tenant != nil {
+		assignment.Tenant = *tenant
+	}
+
+	return assignment, nil
+}
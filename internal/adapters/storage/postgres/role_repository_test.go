@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleRepository_FindRoleAssignment(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewRoleRepository(testPool)
+
+	tests := []struct {
+		name         string
+		subject      string
+		tenant       string
+		expectedRole domain.Role
+		expectedCode string
+	}{
+		{name: "role in every tenant", subject: "alice", tenant: "default", expectedRole: domain.RoleEditor},
+		{name: "role in the tenant takes precedence", subject: "alice", tenant: "acme", expectedRole: domain.RoleViewer},
+		{name: "role in the tenant only", subject: "bob", tenant: "acme", expectedRole: domain.RoleAdmin},
+		{name: "no role in the tenant", subject: "bob", tenant: "default", expectedCode: domain.NotFoundCode},
+		{name: "unknown subject", subject: "carol", tenant: "default", expectedCode: domain.NotFoundCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assignment, err := repo.FindRoleAssignment(ctx, tt.subject, tt.tenant)
+			if tt.expectedCode != "" {
+				assert.True(t, domain.HasCode(err, tt.expectedCode), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRole, assignment.Role)
+		})
+	}
+}
+
+func TestRoleRepository_AssignRole(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewRoleRepository(testPool)
+
+	t.Run("new assignment", func(t *testing.T) {
+		assigned, err := repo.AssignRole(ctx, domain.RoleAssignment{Subject: "carol", Role: domain.RoleViewer, Tenant: "acme"})
+		assert.NoError(t, err)
+		assert.Equal(t, "carol", assigned.Subject)
+		assert.Equal(t, domain.RoleViewer, assigned.Role)
+		assert.Equal(t, "acme", assigned.Tenant)
+		assert.False(t, assigned.CreatedAt.IsZero())
+	})
+
+	t.Run("replaces the role in the same tenant", func(t *testing.T) {
+		assigned, err := repo.AssignRole(ctx, domain.RoleAssignment{Subject: "alice", Role: domain.RoleAdmin})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleAdmin, assigned.Role)
+		assert.Empty(t, assigned.Tenant)
+		assert.True(t, assigned.UpdatedAt.After(assigned.CreatedAt))
+
+		// the role in acme is untouched
+		assignment, err := repo.FindRoleAssignment(ctx, "alice", "acme")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleViewer, assignment.Role)
+	})
+}
+
+func TestRoleRepository_FindAllRoleAssignments(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewRoleRepository(testPool)
+
+	assignments, total, err := repo.FindAllRoleAssignments(ctx, "", domain.PaginationParams{Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	if assert.Len(t, assignments, 2) {
+		assert.Equal(t, "alice", assignments[0].Subject)
+		assert.Empty(t, assignments[0].Tenant)
+	}
+
+	assignments, total, err = repo.FindAllRoleAssignments(ctx, "acme", domain.PaginationParams{Limit: 50})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	for _, assignment := range assignments {
+		assert.Equal(t, "acme", assignment.Tenant)
+	}
+}
+
+func TestRoleRepository_UnassignRole(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewRoleRepository(testPool)
+
+	assert.NoError(t, repo.UnassignRole(ctx, "alice", "acme"))
+
+	// the role in every tenant applies again
+	assignment, err := repo.FindRoleAssignment(ctx, "alice", "acme")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.RoleEditor, assignment.Role)
+
+	err = repo.UnassignRole(ctx, "alice", "acme")
+	assert.True(t, domain.HasCode(err, domain.NotFoundCode), "unexpected error: %v", err)
+}
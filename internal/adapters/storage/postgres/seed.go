@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"database/sql"
+	"embed"
+	"time"
+
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// fixturesFS holds the development data, shared with the tests of the package
+//
+//go:embed fixtures/*.yml
+var fixturesFS embed.FS
+
+// Seed replaces the content of the tables having fixtures with the
+// development data, for every tenant. The tables without fixtures are left
+// untouched. It runs as the role of the service, the policies isolating the
+// tenants being lifted for the seed.
+func Seed(cfg *Config) error {
+	db, err := openSeedDB(cfg.connectionString())
+	if err != nil {
+		return seedError(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	return seed(db)
+}
+
+// openSeedDB connects to the database at url with the tenant isolation
+// lifted, the fixtures spanning every tenant
+func openSeedDB(url string) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	connConfig.RuntimeParams["app.all_tenants"] = "on"
+	return stdlib.OpenDB(*connConfig), nil
+}
+
+func seed(db *sql.DB) error {
+	loader, err := testfixtures.New(
+		testfixtures.Database(db),
+		testfixtures.Dialect("postgres"),
+		testfixtures.FS(fixturesFS),
+		testfixtures.Directory("fixtures"),
+		// the development database is not named after a test one
+		testfixtures.DangerousSkipTestDatabaseCheck(),
+		// disabling the triggers requires a superuser, the foreign keys are
+		// deferrable instead
+		testfixtures.UseAlterConstraint(),
+		// the tables with fixtures have no sequence
+		testfixtures.SkipResetSequences(),
+	)
+	if err != nil {
+		return seedError(err)
+	}
+	if err := loader.Load(); err != nil {
+		return seedError(err)
+	}
+	return nil
+}
+
+func seedError(err error) error {
+	return domain.NewError(domain.InternalCode,
+		domain.WithMessage("failed to seed the database"),
+		domain.WithDetails(err.Error()),
+		domain.WithTS(time.Now()),
+	)
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	resetDB(t)
+
+	_, err := testDB.Exec("DELETE FROM media_tags; DELETE FROM media")
+	require.NoError(t, err)
+
+	// as the role of the service, subject to the tenant isolation
+	db, err := openSeedDB(serviceURL)
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+	require.NoError(t, seed(db))
+
+	var count int
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM media").Scan(&count))
+	assert.Equal(t, 6, count)
+	require.NoError(t, testDB.QueryRow("SELECT COUNT(*) FROM media WHERE tenant_id = 'acme'").Scan(&count))
+	assert.Equal(t, 1, count)
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/peano88/medias/internal/domain"
 )
 
-// TagRepository allows interaction with the tags storage in postgres
+// TagRepository allows interaction with the tags storage in postgres. Every
+// query filters on the tenant of the context, on top of the tenant isolation
+// enforced by the database.
 type TagRepository struct {
 	pool *pgxpool.Pool
 }
@@ -25,13 +28,13 @@ func NewTagRepository(pool *pgxpool.Pool) *TagRepository {
 func (tr *TagRepository) CreateTag(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
 	// Insert into database and return all fields (including DB-generated ones)
 	query := `
-		INSERT INTO tags (name, description)
-		VALUES ($1, $2)
+		INSERT INTO tags (name, description, tenant_id)
+		VALUES ($1, $2, $3)
 		RETURNING id, name, description, created_at, updated_at
 	`
 
 	var created domain.Tag
-	err := tr.pool.QueryRow(ctx, query, tag.Name, tag.Description).Scan(
+	err := tr.pool.QueryRow(ctx, query, tag.Name, tag.Description, domain.TenantFromContext(ctx)).Scan(
 		&created.ID,
 		&created.Name,
 		&created.Description,
@@ -45,7 +48,7 @@ func (tr *TagRepository) CreateTag(ctx context.Context, tag domain.Tag) (domain.
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return domain.Tag{}, domain.NewError(domain.ConflictCode,
 				domain.WithMessage("tag name already exists"),
-				domain.WithDetails("a tag with this name already exists in the database"),
+				domain.WithDetails("a tag with this name already exists in the tenant"),
 				domain.WithTS(time.Now()),
 			)
 		}
@@ -63,10 +66,12 @@ func (tr *TagRepository) CreateTag(ctx context.Context, tag domain.Tag) (domain.
 
 // FindAllTags retrieves paginated tags from the database and returns total count
 func (tr *TagRepository) FindAllTags(ctx context.Context, params domain.PaginationParams) ([]domain.Tag, int, error) {
+	tenant := domain.TenantFromContext(ctx)
+
 	// Get total count
 	var total int
-	countQuery := "SELECT COUNT(*) FROM tags"
-	err := tr.pool.QueryRow(ctx, countQuery).Scan(&total)
+	countQuery := "SELECT COUNT(*) FROM tags WHERE tenant_id = $1"
+	err := tr.pool.QueryRow(ctx, countQuery, tenant).Scan(&total)
 	if err != nil {
 		return nil, 0, domain.NewError(domain.InternalCode,
 			domain.WithMessage("failed to count tags"),
@@ -79,11 +84,12 @@ func (tr *TagRepository) FindAllTags(ctx context.Context, params domain.Paginati
 	query := `
 		SELECT id, name, description, created_at, updated_at
 		FROM tags
+		WHERE tenant_id = $3
 		ORDER BY created_at ASC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := tr.pool.Query(ctx, query, params.Limit, params.Offset)
+	rows, err := tr.pool.Query(ctx, query, params.Limit, params.Offset, tenant)
 	if err != nil {
 		return nil, 0, domain.NewError(domain.InternalCode,
 			domain.WithMessage("failed to retrieve tags"),
@@ -104,3 +110,203 @@ func (tr *TagRepository) FindAllTags(ctx context.Context, params domain.Paginati
 
 	return tags, total, nil
 }
+
+// FindRelatedTags retrieves the tags most often associated with the same media
+// as the named tag. Results come from the co-occurrence view and are as fresh as
+// its last refresh.
+func (tr *TagRepository) FindRelatedTags(ctx context.Context, name string, limit int) ([]domain.RelatedTag, error) {
+	tenant := domain.TenantFromContext(ctx)
+
+	var exists bool
+	err := tr.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM tags WHERE name = $1 AND tenant_id = $2)", name, tenant).Scan(&exists)
+	if err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find tag"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	if !exists {
+		return nil, domain.NewError(domain.NotFoundCode,
+			domain.WithMessage("tag not found"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	query := `
+		SELECT r.id, r.name, r.description, r.created_at, r.updated_at, tc.cooccurrences, tc.lift
+		FROM tags t
+		INNER JOIN tag_cooccurrences tc ON tc.tag_id = t.id
+		INNER JOIN tags r ON r.id = tc.related_tag_id
+		WHERE t.name = $1 AND t.tenant_id = $3 AND tc.tenant_id = $3 AND r.tenant_id = $3
+		ORDER BY tc.cooccurrences DESC, tc.lift DESC, r.name ASC
+		LIMIT $2
+	`
+
+	rows, err := tr.pool.Query(ctx, query, name, limit, tenant)
+	if err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to retrieve related tags"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer rows.Close()
+
+	related, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.RelatedTag, error) {
+		var rt domain.RelatedTag
+		err := row.Scan(
+			&rt.Tag.ID,
+			&rt.Tag.Name,
+			&rt.Tag.Description,
+			&rt.Tag.CreatedAt,
+			&rt.Tag.UpdatedAt,
+			&rt.Cooccurrences,
+			&rt.Lift,
+		)
+		return rt, err
+	})
+	if err != nil {
+		return nil, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to collect related tags"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return related, nil
+}
+
+// RefreshTagCooccurrences recomputes the tag co-occurrence view without
+// blocking readers. It returns false without refreshing when another
+// replica already holds the refresh lock.
+func (tr *TagRepository) RefreshTagCooccurrences(ctx context.Context) (bool, error) {
+	conn, err := tr.pool.Acquire(ctx)
+	if err != nil {
+		return false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to acquire connection"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer conn.Release()
+
+	locked, err := tagCooccurrencesLock.tryLock(ctx, conn)
+	if err != nil {
+		return false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to lock tag co-occurrences"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	if !locked {
+		return false, nil
+	}
+	defer tagCooccurrencesLock.unlock(conn)
+
+	// The view spans every tenant, the transaction lifts the tenant isolation.
+	// The view belongs to the owner of the schema, refresh_tag_cooccurrences
+	// refreshes it on its behalf.
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to begin transaction"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, "SELECT set_config('app.all_tenants', 'on', true)"); err != nil {
+		return false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to refresh tag co-occurrences"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if _, err := tx.Exec(ctx, "SELECT refresh_tag_cooccurrences()"); err != nil {
+		return false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to refresh tag co-occurrences"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to commit transaction"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return true, nil
+}
+
+// FindTagByID finds a tag by ID
+func (tr *TagRepository) FindTagByID(ctx context.Context, id uuid.UUID) (domain.Tag, error) {
+	query := `
+		SELECT id, name, description, created_at, updated_at
+		FROM tags
+		WHERE id = $1 AND tenant_id = $2
+	`
+
+	var tag domain.Tag
+	err := tr.pool.QueryRow(ctx, query, id, domain.TenantFromContext(ctx)).Scan(
+		&tag.ID,
+		&tag.Name,
+		&tag.Description,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.Tag{}, domain.NewError(domain.NotFoundCode,
+				domain.WithMessage("tag not found"),
+				domain.WithTS(time.Now()),
+			)
+		}
+		return domain.Tag{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to find tag"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return tag, nil
+}
+
+// UpdateTagDescription updates the description of a tag only if it was not
+// modified since expectedUpdatedAt, in a single conditional statement
+func (tr *TagRepository) UpdateTagDescription(ctx context.Context, id uuid.UUID, description *string, expectedUpdatedAt time.Time) (domain.Tag, error) {
+	query := `
+		UPDATE tags
+		SET description = $2
+		WHERE id = $1 AND updated_at = $3 AND tenant_id = $4
+		RETURNING id, name, description, created_at, updated_at
+	`
+
+	var tag domain.Tag
+	err := tr.pool.QueryRow(ctx, query, id, description, expectedUpdatedAt, domain.TenantFromContext(ctx)).Scan(
+		&tag.ID,
+		&tag.Name,
+		&tag.Description,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.Tag{}, staleOrMissingError(ctx, tr.pool, "tags", "tag", id)
+		}
+		return domain.Tag{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to update tag"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return tag, nil
+}
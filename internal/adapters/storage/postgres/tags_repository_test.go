@@ -3,9 +3,12 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/peano88/medias/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Example test showing how to use the test helper with TestMain
@@ -206,3 +209,158 @@ func TestTagRepository_FindAllTags_EmptyTable(t *testing.T) {
 	assert.Equal(t, 0, total)
 	assert.NotNil(t, tags) // Should be empty slice, not nil
 }
+
+func TestTagRepository_FindRelatedTags(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	repo := NewTagRepository(testPool)
+	refreshed, err := repo.RefreshTagCooccurrences(ctx)
+	assert.NoError(t, err)
+	assert.True(t, refreshed)
+
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		tagName           string
+		expectedNames     []string
+		expectedErrorCode string
+	}{
+		{
+			name:          "returns co-occurring tags",
+			ctx:           ctx,
+			tagName:       "soccer",
+			expectedNames: []string{"football"},
+		},
+		{
+			name:          "tag without media",
+			ctx:           ctx,
+			tagName:       "basketball",
+			expectedNames: []string{},
+		},
+		{
+			name:              "tag not found",
+			ctx:               ctx,
+			tagName:           "curling",
+			expectedErrorCode: domain.NotFoundCode,
+		},
+		{
+			name:              "cancelled context",
+			ctx:               cancelCtx,
+			tagName:           "soccer",
+			expectedErrorCode: domain.InternalCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			related, err := repo.FindRelatedTags(tt.ctx, tt.tagName, 10)
+			if tt.expectedErrorCode != "" {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, tt.expectedErrorCode, domainErr.Code)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			names := make([]string, len(related))
+			for i, rt := range related {
+				names[i] = rt.Tag.Name
+				assert.Equal(t, 1, rt.Cooccurrences)
+				// only one tagged media: both tags always appear together
+				assert.InDelta(t, 1.0, rt.Lift, 0.0001)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestTagRepository_FindTagByID(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewTagRepository(testPool)
+
+	tag, err := repo.FindTagByID(ctx, uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"))
+	assert.NoError(t, err)
+	assert.Equal(t, "soccer", tag.Name)
+
+	_, err = repo.FindTagByID(ctx, uuid.MustParse("999e9999-e99b-99d9-a999-999999999999"))
+	assert.True(t, domain.HasCode(err, domain.NotFoundCode))
+}
+
+func TestTagRepository_UpdateTagDescription(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	soccerID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	fixtureUpdatedAt := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		id                uuid.UUID
+		expectedUpdatedAt time.Time
+		validate          func(*testing.T, domain.Tag, error)
+	}{
+		{
+			name:              "success - matching version",
+			id:                soccerID,
+			expectedUpdatedAt: fixtureUpdatedAt,
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Association football", *tag.Description)
+				assert.True(t, tag.UpdatedAt.After(fixtureUpdatedAt))
+			},
+		},
+		{
+			name:              "stale version - previous update changed updated_at",
+			id:                soccerID,
+			expectedUpdatedAt: fixtureUpdatedAt,
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				assert.True(t, domain.HasCode(err, domain.PreconditionFailedCode))
+			},
+		},
+		{
+			name:              "not found",
+			id:                uuid.MustParse("999e9999-e99b-99d9-a999-999999999999"),
+			expectedUpdatedAt: fixtureUpdatedAt,
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				assert.True(t, domain.HasCode(err, domain.NotFoundCode))
+			},
+		},
+	}
+
+	repo := NewTagRepository(testPool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := repo.UpdateTagDescription(ctx, tt.id, stringPtr("Association football"), tt.expectedUpdatedAt)
+			tt.validate(t, tag, err)
+		})
+	}
+}
+
+func TestTagRepository_RefreshTagCooccurrences_Locked(t *testing.T) {
+	resetDB(t)
+
+	ctx := context.Background()
+	repo := NewTagRepository(testPool)
+
+	// another replica refreshing
+	conn, err := testPool.Acquire(ctx)
+	require.NoError(t, err)
+	defer conn.Release()
+	require.NoError(t, tagCooccurrencesLock.lock(ctx, conn))
+
+	refreshed, err := repo.RefreshTagCooccurrences(ctx)
+	assert.NoError(t, err)
+	assert.False(t, refreshed)
+
+	tagCooccurrencesLock.unlock(conn)
+	refreshed, err = repo.RefreshTagCooccurrences(ctx)
+	assert.NoError(t, err)
+	assert.True(t, refreshed)
+}
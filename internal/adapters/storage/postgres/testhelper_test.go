@@ -19,8 +19,10 @@ import (
 )
 
 var (
-	testDB       *sql.DB       // For migrations and fixtures
-	testPool     *pgxpool.Pool // For actual tests
+	testDB       *sql.DB       // For fixtures, as a superuser
+	testPool     *pgxpool.Pool // For actual tests, subject to the tenant isolation
+	superuserURL string        // For the tests of a role bypassing the tenant isolation
+	serviceURL   string        // For the tests opening their own connections as the service role
 	fixtures     *testfixtures.Loader
 	dockerPool   *dockertest.Pool
 	testResource *dockertest.Resource
@@ -61,10 +63,11 @@ func TestMain(m *testing.M) {
 
 	hostAndPort := testResource.GetHostPort("5432/tcp")
 	databaseUrl := fmt.Sprintf("postgres://test:secret@%s/testdb?sslmode=disable", hostAndPort)
+	superuserURL = databaseUrl
 
 	_ = testResource.Expire(300) // Container will be killed after 5 minutes
 
-	// Wait for database to be ready and create sql.DB for migrations/fixtures
+	// Wait for database to be ready and create sql.DB for fixtures
 	dockerPool.MaxWait = 30 * time.Second
 	if err = dockerPool.Retry(func() error {
 		testDB, err = sql.Open("pgx", databaseUrl)
@@ -76,8 +79,36 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Could not connect to database: %s", err)
 	}
 
+	// The roles are provisioned like in the deployments: the schema belongs
+	// to a migration owner, the tests connect as a service role which is
+	// neither a superuser nor BYPASSRLS, like the service must, since those
+	// bypass the row-level security policies
+	for _, statement := range []string{
+		"CREATE ROLE medias_owner LOGIN PASSWORD 'secret' NOSUPERUSER NOBYPASSRLS",
+		"ALTER DATABASE testdb OWNER TO medias_owner",
+		"CREATE ROLE medias LOGIN PASSWORD 'secret' NOSUPERUSER NOBYPASSRLS",
+		"ALTER DEFAULT PRIVILEGES FOR ROLE medias_owner IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO medias",
+	} {
+		if _, err := testDB.Exec(statement); err != nil {
+			log.Fatalf("Could not create the roles: %s", err)
+		}
+	}
+
+	// Run migrations as the owner
+	ownerDB, err := sql.Open("pgx", fmt.Sprintf("postgres://medias_owner:secret@%s/testdb?sslmode=disable", hostAndPort))
+	if err != nil {
+		log.Fatalf("Could not connect as the owner: %s", err)
+	}
+	goose.SetBaseFS(migrations.FS)
+	if err := goose.Up(ownerDB, "."); err != nil {
+		log.Fatalf("Could not run migrations: %s", err)
+	}
+	_ = ownerDB.Close()
+
+	serviceURL = fmt.Sprintf("postgres://medias:secret@%s/testdb?sslmode=disable", hostAndPort)
+
 	// Create pgxpool for actual tests
-	poolConfig, err := pgxpool.ParseConfig(databaseUrl)
+	poolConfig, err := pgxpool.ParseConfig(serviceURL)
 	if err != nil {
 		log.Fatalf("Could not parse pool config: %s", err)
 	}
@@ -87,6 +118,7 @@ func TestMain(m *testing.M) {
 	poolConfig.MinConns = 2
 	poolConfig.MaxConnLifetime = 1 * time.Hour
 	poolConfig.MaxConnIdleTime = 30 * time.Minute
+	poolConfig.PrepareConn = setTenant
 
 	testPool, err = pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
@@ -98,12 +130,6 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Could not ping pool: %s", err)
 	}
 
-	// Run migrations
-	goose.SetBaseFS(migrations.FS)
-	if err := goose.Up(testDB, "."); err != nil {
-		log.Fatalf("Could not run migrations: %s", err)
-	}
-
 	// Setup testfixtures
 	fixtures, err = testfixtures.New(
 		testfixtures.Database(testDB),
@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/peano88/medias/internal/domain"
+)
+
+// staleOrMissingError explains why a conditional update on updated_at
+// matched no row: either the entity does not exist or it was modified since
+// the version the caller based its update on. The update itself does not
+// depend on this lookup.
+func staleOrMissingError(ctx context.Context, pool *pgxpool.Pool, table, entity string, id uuid.UUID) error {
+	// table is always a constant of the calling repository
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND tenant_id = $2)`, table)
+
+	var exists bool
+	if err := pool.QueryRow(ctx, query, id, domain.TenantFromContext(ctx)).Scan(&exists); err != nil {
+		return domain.NewError(domain.InternalCode,
+			domain.WithMessage(fmt.Sprintf("failed to find %s", entity)),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if !exists {
+		return domain.NewError(domain.NotFoundCode,
+			domain.WithMessage(fmt.Sprintf("%s not found", entity)),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return domain.NewError(domain.PreconditionFailedCode,
+		domain.WithMessage(fmt.Sprintf("%s was modified", entity)),
+		domain.WithDetails(fmt.Sprintf("the %s was modified since the expected version", entity)),
+		domain.WithTS(time.Now()),
+	)
+}
@@ -0,0 +1,58 @@
+package tlscert
+
+import (
+	"os"
+
+	"github.com/peano88/medias/config"
+)
+
+// Config holds the configuration of the native TLS of the server
+type Config struct {
+	// CertFile and KeyFile are the PEM certificate chain and private key of
+	// the server, reloaded whenever they change. TLS is off when unset.
+	CertFile string `mapstructure:"cert-file"`
+	KeyFile  string `mapstructure:"key-file"`
+	// ClientCAFile, when set, requires the clients to present a certificate
+	// signed by one of its PEM CAs (mTLS)
+	ClientCAFile string `mapstructure:"client-ca-file"`
+}
+
+func SetDefaultConfig(loader config.ConfigLoader, prefix string) {
+	// Defaults on the optional keys let them be set from the environment
+	loader.SetDefault(prefix+".cert-file", "")
+	loader.SetDefault(prefix+".key-file", "")
+	loader.SetDefault(prefix+".client-ca-file", "")
+}
+
+// Enabled reports whether the server serves TLS
+func (c *Config) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Validate reports all the problems of the configuration, with their keys
+func (c *Config) Validate() error {
+	if !c.Enabled() {
+		if c.ClientCAFile != "" {
+			return config.Collect("", config.Invalid("client-ca-file", "requires cert-file and key-file"))
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, file := range []struct{ key, path string }{
+		{"cert-file", c.CertFile},
+		{"key-file", c.KeyFile},
+		{"client-ca-file", c.ClientCAFile},
+	} {
+		if file.path == "" {
+			if file.key != "client-ca-file" {
+				errs = append(errs, config.Invalid(file.key, "must be set with TLS enabled"))
+			}
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			errs = append(errs, config.Invalid(file.key, "cannot be read: %s", err))
+		}
+	}
+	return config.Collect("", errs...)
+}
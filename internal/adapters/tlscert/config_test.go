@@ -0,0 +1,66 @@
+package tlscert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/peano88/medias/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "tls.crt")
+	require.NoError(t, os.WriteFile(existing, []byte("certificate"), 0o600))
+	missing := filepath.Join(dir, "missing.crt")
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		wantKeys []string
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{},
+		},
+		{
+			name: "enabled with mTLS",
+			cfg:  Config{CertFile: existing, KeyFile: existing, ClientCAFile: existing},
+		},
+		{
+			name:     "key missing",
+			cfg:      Config{CertFile: existing},
+			wantKeys: []string{"key-file"},
+		},
+		{
+			name:     "unreadable files",
+			cfg:      Config{CertFile: missing, KeyFile: existing, ClientCAFile: missing},
+			wantKeys: []string{"cert-file", "client-ca-file"},
+		},
+		{
+			name:     "client CAs without TLS",
+			cfg:      Config{ClientCAFile: existing},
+			wantKeys: []string{"client-ca-file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if len(tt.wantKeys) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErrs config.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+			keys := make([]string, len(validationErrs))
+			for i, fieldErr := range validationErrs {
+				keys[i] = fieldErr.Key
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package tlscert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Certificate is the certificate of the server, reloaded from its files
+// whenever they change
+type Certificate struct {
+	cfg    Config
+	cert   atomic.Pointer[tls.Certificate]
+	logger *slog.Logger
+}
+
+// NewServerConfig creates the TLS configuration of a server serving the
+// certificate of cfg, reloaded until ctx is done, and requiring client
+// certificates when cfg has client CAs
+func NewServerConfig(ctx context.Context, cfg Config, logger *slog.Logger) (*tls.Config, error) {
+	cert, err := NewCertificate(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: cert.GetCertificate,
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CAs: %w", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no client CA certificate found")
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// NewCertificate loads the certificate and key of cfg, then reloads them
+// whenever their files change, until ctx is done
+func NewCertificate(ctx context.Context, cfg Config, logger *slog.Logger) (*Certificate, error) {
+	c := &Certificate{cfg: cfg, logger: logger}
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch TLS certificate: %w", err)
+	}
+	// Watching the directories catches files replaced by a rename, as
+	// cert-manager and mounted secrets do
+	dirs := []string{filepath.Dir(cfg.CertFile), filepath.Dir(cfg.KeyFile)}
+	slices.Sort(dirs)
+	for _, dir := range slices.Compact(dirs) {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("failed to watch TLS certificate: %w", err)
+		}
+	}
+	go c.watchFiles(ctx, watcher)
+
+	return c, nil
+}
+
+// GetCertificate returns the certificate in use, for tls.Config
+func (c *Certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.cert.Load(), nil
+}
+
+// load reads the certificate and key and replaces the certificate in use
+func (c *Certificate) load() error {
+	cert, err := tls.LoadX509KeyPair(c.cfg.CertFile, c.cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+	c.cert.Store(&cert)
+	return nil
+}
+
+// reload loads the certificate again. On failure, the certificate in use is
+// kept: the certificate may be caught replaced before its key, it is
+// reloaded on the next change.
+func (c *Certificate) reload() {
+	if err := c.load(); err != nil {
+		c.logger.Error("failed to reload TLS certificate, keeping the current one",
+			slog.String("error", err.Error()))
+		return
+	}
+	c.logger.Info("TLS certificate reloaded", slog.Time("not_after", c.cert.Load().Leaf.NotAfter))
+}
+
+func (c *Certificate) watchFiles(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	files := []string{filepath.Clean(c.cfg.CertFile), filepath.Clean(c.cfg.KeyFile)}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			// Kubernetes swaps the ..data symlink when a mounted secret changes
+			if !slices.Contains(files, filepath.Clean(event.Name)) && !strings.HasPrefix(filepath.Base(event.Name), "..") {
+				continue
+			}
+			if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) {
+				c.reload()
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			c.logger.Error("TLS certificate watcher error", slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package tlscert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA signs the certificates of the tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns the PEM certificate and key of a leaf certificate
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "medias"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func (ca *testCA) pem() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+}
+
+func writeFile(t *testing.T, path string, content []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+}
+
+// serve serves tlsConfig on a local port and returns its address
+func serve(t *testing.T, tlsConfig *tls.Config) string {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	require.NoError(t, err)
+	server := &http.Server{
+		Handler:           http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		ReadHeaderTimeout: time.Second,
+		ErrorLog:          slog.NewLogLogger(slog.DiscardHandler, slog.LevelError),
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+	return listener.Addr().String()
+}
+
+// handshake connects to addr and returns the serial of the server certificate
+func handshake(addr string, ca *testCA, clientCerts ...tls.Certificate) (int64, error) {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: roots, Certificates: clientCerts})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	// TLS 1.3 reports a rejected client certificate on the first read
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: medias\r\n\r\n")); err != nil {
+		return 0, err
+	}
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return 0, err
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestNewServerConfig_ReloadsChangedCertificate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	cfg := Config{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}
+	certPEM, keyPEM := ca.issue(t, 100, x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+
+	tlsConfig, err := NewServerConfig(ctx, cfg, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	addr := serve(t, tlsConfig)
+
+	serial, err := handshake(addr, ca)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), serial)
+
+	// an invalid certificate keeps the one in use
+	writeFile(t, cfg.CertFile, []byte("not a certificate"))
+	time.Sleep(100 * time.Millisecond)
+	serial, err = handshake(addr, ca)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), serial)
+
+	certPEM, keyPEM = ca.issue(t, 200, x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.KeyFile, keyPEM)
+	writeFile(t, cfg.CertFile, certPEM)
+	assert.Eventually(t, func() bool {
+		serial, err := handshake(addr, ca)
+		return err == nil && serial == 200
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestNewServerConfig_ClientCertificates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	dir := t.TempDir()
+	cfg := Config{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+	certPEM, keyPEM := ca.issue(t, 100, x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+	writeFile(t, cfg.ClientCAFile, ca.pem())
+
+	tlsConfig, err := NewServerConfig(ctx, cfg, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+	addr := serve(t, tlsConfig)
+
+	clientCert, err := tls.X509KeyPair(ca.issue(t, 300, x509.ExtKeyUsageClientAuth))
+	require.NoError(t, err)
+	otherCert, err := tls.X509KeyPair(otherCA.issue(t, 400, x509.ExtKeyUsageClientAuth))
+	require.NoError(t, err)
+
+	_, err = handshake(addr, ca, clientCert)
+	assert.NoError(t, err)
+
+	_, err = handshake(addr, ca)
+	assert.Error(t, err, "no client certificate")
+
+	_, err = handshake(addr, ca, otherCert)
+	assert.Error(t, err, "client certificate of another CA")
+}
+
+func TestNewServerConfig_InvalidFiles(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, 100, x509.ExtKeyUsageServerAuth)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	invalid := filepath.Join(dir, "invalid.pem")
+	writeFile(t, invalid, []byte("not a certificate"))
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "missing certificate", cfg: Config{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile}},
+		{name: "invalid key", cfg: Config{CertFile: certFile, KeyFile: invalid}},
+		{name: "invalid client CAs", cfg: Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: invalid}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewServerConfig(context.Background(), tt.cfg, slog.New(slog.DiscardHandler))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"github.com/peano88/medias/config"
+)
+
+const (
+	// ExporterStdout writes the spans to the standard output
+	ExporterStdout = "stdout"
+	// ExporterFile appends the spans to File, in the OTLP JSON lines format
+	ExporterFile = "file"
+	// ExporterOTLPHTTP sends the spans to a collector at Endpoint, OTLP over HTTP
+	ExporterOTLPHTTP = "otlp-http"
+)
+
+// Config holds the configuration of the tracing
+type Config struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Exporter string `mapstructure:"exporter"`
+	// File is the file of ExporterFile
+	File string `mapstructure:"file"`
+	// Endpoint is the host and port of the collector of ExporterOTLPHTTP
+	Endpoint string `mapstructure:"endpoint"`
+	// Insecure sends the spans to the collector over plain HTTP
+	Insecure bool `mapstructure:"insecure"`
+	// SampleRatio is the share of the traces started by the service which are
+	// recorded. Traces started by the callers follow their sampling decision.
+	SampleRatio float64 `mapstructure:"sample-ratio"`
+}
+
+func SetDefaultConfig(loader config.ConfigLoader, prefix string) {
+	loader.SetDefault(prefix+".enabled", false)
+	loader.SetDefault(prefix+".exporter", ExporterStdout)
+	loader.SetDefault(prefix+".file", "traces.jsonl")
+	loader.SetDefault(prefix+".endpoint", "localhost:4318")
+	loader.SetDefault(prefix+".insecure", true)
+	loader.SetDefault(prefix+".sample-ratio", 1.0)
+}
+
+// Validate reports all the problems of the configuration, with their keys.
+// Nothing is required when the tracing is disabled.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	var errs []error
+	switch c.Exporter {
+	case ExporterStdout:
+	case ExporterFile:
+		if c.File == "" {
+			errs = append(errs, config.Invalid("file", "is required by the %s exporter", ExporterFile))
+		}
+	case ExporterOTLPHTTP:
+		if c.Endpoint == "" {
+			errs = append(errs, config.Invalid("endpoint", "is required by the %s exporter", ExporterOTLPHTTP))
+		}
+	default:
+		errs = append(errs, config.Invalid("exporter", "must be %s, %s or %s, got %q", ExporterStdout, ExporterFile, ExporterOTLPHTTP, c.Exporter))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, config.Invalid("sample-ratio", "must be between 0 and 1, got %g", c.SampleRatio))
+	}
+	return config.Collect("", errs...)
+}
@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/peano88/medias/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		wantKeys []string
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{Exporter: "zipkin", SampleRatio: 2},
+		},
+		{
+			name: "stdout",
+			cfg:  Config{Enabled: true, Exporter: ExporterStdout, SampleRatio: 1},
+		},
+		{
+			name: "otlp-http",
+			cfg:  Config{Enabled: true, Exporter: ExporterOTLPHTTP, Endpoint: "collector:4318", SampleRatio: 0.1},
+		},
+		{
+			name:     "unknown exporter",
+			cfg:      Config{Enabled: true, Exporter: "zipkin", SampleRatio: 1},
+			wantKeys: []string{"exporter"},
+		},
+		{
+			name:     "file missing",
+			cfg:      Config{Enabled: true, Exporter: ExporterFile, SampleRatio: 1},
+			wantKeys: []string{"file"},
+		},
+		{
+			name:     "endpoint missing and invalid ratio",
+			cfg:      Config{Enabled: true, Exporter: ExporterOTLPHTTP, SampleRatio: 1.5},
+			wantKeys: []string{"endpoint", "sample-ratio"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if len(tt.wantKeys) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErrs config.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+			keys := make([]string, len(validationErrs))
+			for i, fieldErr := range validationErrs {
+				keys[i] = fieldErr.Key
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+		})
+	}
+}
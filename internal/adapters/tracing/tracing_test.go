@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_File(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	shutdown, err := Setup(ctx, Config{
+		Enabled:     true,
+		Exporter:    ExporterFile,
+		File:        path,
+		SampleRatio: 1,
+	}, "medias-test", "0.0.1")
+	require.NoError(t, err)
+
+	parentCtx, parent := otel.Tracer("test").Start(ctx, "GET /api/v1/media/{id}")
+	_, child := otel.Tracer("test").Start(parentCtx, "getmedia.Execute")
+	child.End()
+	parent.End()
+
+	require.NoError(t, shutdown(ctx))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+
+	var request struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []struct {
+					Key   string `json:"key"`
+					Value struct {
+						StringValue string `json:"stringValue"`
+					} `json:"value"`
+				} `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []struct {
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+					Kind         int    `json:"kind"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &request))
+	require.Len(t, request.ResourceSpans, 1)
+
+	attributes := map[string]string{}
+	for _, attribute := range request.ResourceSpans[0].Resource.Attributes {
+		attributes[attribute.Key] = attribute.Value.StringValue
+	}
+	assert.Equal(t, "medias-test", attributes["service.name"])
+	assert.Equal(t, "0.0.1", attributes["service.version"])
+
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 2)
+	assert.Equal(t, "getmedia.Execute", spans[0].Name)
+	assert.Equal(t, parent.SpanContext().TraceID().String(), spans[0].TraceID)
+	assert.Equal(t, parent.SpanContext().SpanID().String(), spans[0].ParentSpanID)
+	assert.Equal(t, child.SpanContext().SpanID().String(), spans[0].SpanID)
+	assert.Equal(t, 1, spans[0].Kind)
+}
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	ctx := context.Background()
+
+	t.Run("disabled", func(t *testing.T) {
+		shutdown, err := Setup(ctx, Config{Exporter: "unknown"}, "medias-test", "0.0.1")
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(ctx))
+		assert.Equal(t, previous, otel.GetTracerProvider())
+		assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		_, err := Setup(ctx, Config{Enabled: true, Exporter: "zipkin"}, "medias-test", "0.0.1")
+		assert.ErrorContains(t, err, "unknown exporter")
+	})
+
+	t.Run("file exporter without file", func(t *testing.T) {
+		_, err := Setup(ctx, Config{Enabled: true, Exporter: ExporterFile}, "medias-test", "0.0.1")
+		assert.ErrorContains(t, err, "a file is required")
+	})
+}
@@ -0,0 +1,70 @@
+package assignrole
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// RoleRepository defines the repository contract for assigning roles
+type RoleRepository interface {
+	AssignRole(ctx context.Context, assignment domain.RoleAssignment) (domain.RoleAssignment, error)
+}
+
+// UseCase handles the assignment of roles to principals
+type UseCase struct {
+	repo RoleRepository
+}
+
+// New creates a new AssignRole use case
+func New(repo RoleRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute grants the role of input to its subject in its tenant, empty
+// meaning every tenant, replacing the role the subject held there. Only a
+// principal bound to no tenant may assign roles in every tenant.
+func (uc *UseCase) Execute(ctx context.Context, input domain.RoleAssignment) (_ domain.RoleAssignment, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "assignrole.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	subject := strings.TrimSpace(input.Subject)
+	if len(subject) == 0 || len(subject) > 255 {
+		return domain.RoleAssignment{}, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid subject"),
+			domain.WithDetails("subject is mandatory and should be less than 255 characters"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if err := domain.ValidateRole(input.Role); err != nil {
+		return domain.RoleAssignment{}, err
+	}
+
+	tenant, err := domain.BindTenant(ctx, input.Tenant)
+	if err != nil {
+		return domain.RoleAssignment{}, err
+	}
+
+	// Granting roles is how privileges escalate, the route check is not
+	// relied upon alone
+	if err := domain.Authorize(ctx, domain.ActionManageRoles); err != nil {
+		return domain.RoleAssignment{}, err
+	}
+
+	assigned, err := uc.repo.AssignRole(ctx, domain.RoleAssignment{
+		Subject: subject,
+		Role:    input.Role,
+		Tenant:  tenant,
+	})
+	if err != nil {
+		return domain.RoleAssignment{}, domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error assigning role: %s", err)))
+	}
+
+	return assigned, nil
+}
@@ -0,0 +1,143 @@
+package assignrole
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/assignrole RoleRepository
+//go:generate mockgen -destination=mocks/mock_authorizer.go -package=mocks github.com/peano88/medias/internal/domain Authorizer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/app/assignrole/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	admin := domain.Principal{Subject: "admin", Scopes: []domain.Scope{domain.ScopeAdmin, domain.ScopeAllTenants}, Tenant: domain.DefaultTenant}
+	acmeAdmin := domain.Principal{Subject: "acme-admin", Scopes: []domain.Scope{domain.ScopeAdmin}, Tenant: "acme"}
+	defaultAdmin := domain.Principal{Subject: "default-admin", Scopes: []domain.Scope{domain.ScopeAdmin}, Tenant: domain.DefaultTenant}
+
+	tests := []struct {
+		name       string
+		principal  domain.Principal
+		input      domain.RoleAssignment
+		setupMocks func(*mocks.MockRoleRepository, *mocks.MockAuthorizer)
+		validate   func(*testing.T, domain.RoleAssignment, error)
+	}{
+		{
+			name:      "success - role in every tenant",
+			principal: admin,
+			input:     domain.RoleAssignment{Subject: " user-42 ", Role: domain.RoleEditor},
+			setupMocks: func(repo *mocks.MockRoleRepository, authorizer *mocks.MockAuthorizer) {
+				authorizer.EXPECT().Authorize(gomock.Any(), domain.ActionManageRoles).Return(nil)
+				repo.EXPECT().
+					AssignRole(gomock.Any(), domain.RoleAssignment{Subject: "user-42", Role: domain.RoleEditor}).
+					Return(domain.RoleAssignment{Subject: "user-42", Role: domain.RoleEditor}, nil)
+			},
+			validate: func(t *testing.T, assignment domain.RoleAssignment, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.RoleEditor, assignment.Role)
+			},
+		},
+		{
+			name:      "success - principal bound to a tenant assigns in its tenant",
+			principal: acmeAdmin,
+			input:     domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer},
+			setupMocks: func(repo *mocks.MockRoleRepository, authorizer *mocks.MockAuthorizer) {
+				authorizer.EXPECT().Authorize(gomock.Any(), domain.ActionManageRoles).Return(nil)
+				repo.EXPECT().
+					AssignRole(gomock.Any(), domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer, Tenant: "acme"}).
+					Return(domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer, Tenant: "acme"}, nil)
+			},
+			validate: func(t *testing.T, assignment domain.RoleAssignment, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "acme", assignment.Tenant)
+			},
+		},
+		{
+			name:      "success - principal without tenant in its credentials assigns in the default tenant",
+			principal: defaultAdmin,
+			input:     domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer},
+			setupMocks: func(repo *mocks.MockRoleRepository, authorizer *mocks.MockAuthorizer) {
+				authorizer.EXPECT().Authorize(gomock.Any(), domain.ActionManageRoles).Return(nil)
+				repo.EXPECT().
+					AssignRole(gomock.Any(), domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer, Tenant: domain.DefaultTenant}).
+					Return(domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer, Tenant: domain.DefaultTenant}, nil)
+			},
+			validate: func(t *testing.T, assignment domain.RoleAssignment, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.DefaultTenant, assignment.Tenant)
+			},
+		},
+		{
+			name:       "error - principal bound to another tenant",
+			principal:  acmeAdmin,
+			input:      domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer, Tenant: "globex"},
+			setupMocks: func(repo *mocks.MockRoleRepository, authorizer *mocks.MockAuthorizer) {},
+			validate: func(t *testing.T, assignment domain.RoleAssignment, err error) {
+				assert.True(t, domain.HasCode(err, domain.ForbiddenCode))
+			},
+		},
+		{
+			name:      "error - denied by the authorizer",
+			principal: admin,
+			input:     domain.RoleAssignment{Subject: "user-42", Role: domain.RoleAdmin},
+			setupMocks: func(repo *mocks.MockRoleRepository, authorizer *mocks.MockAuthorizer) {
+				authorizer.EXPECT().Authorize(gomock.Any(), domain.ActionManageRoles).
+					Return(domain.NewError(domain.ForbiddenCode))
+			},
+			validate: func(t *testing.T, assignment domain.RoleAssignment, err error) {
+				assert.True(t, domain.HasCode(err, domain.ForbiddenCode))
+			},
+		},
+		{
+			name:       "error - unknown role",
+			principal:  admin,
+			input:      domain.RoleAssignment{Subject: "user-42", Role: "owner"},
+			setupMocks: func(repo *mocks.MockRoleRepository, authorizer *mocks.MockAuthorizer) {},
+			validate: func(t *testing.T, assignment domain.RoleAssignment, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:       "error - missing subject",
+			principal:  admin,
+			input:      domain.RoleAssignment{Subject: " ", Role: domain.RoleViewer},
+			setupMocks: func(repo *mocks.MockRoleRepository, authorizer *mocks.MockAuthorizer) {},
+			validate: func(t *testing.T, assignment domain.RoleAssignment, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:      "error - repository failure",
+			principal: admin,
+			input:     domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer},
+			setupMocks: func(repo *mocks.MockRoleRepository, authorizer *mocks.MockAuthorizer) {
+				authorizer.EXPECT().Authorize(gomock.Any(), domain.ActionManageRoles).Return(nil)
+				repo.EXPECT().AssignRole(gomock.Any(), gomock.Any()).
+					Return(domain.RoleAssignment{}, domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			validate: func(t *testing.T, assignment domain.RoleAssignment, err error) {
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockRoleRepository(ctrl)
+			authorizer := mocks.NewMockAuthorizer(ctrl)
+			tt.setupMocks(repo, authorizer)
+
+			ctx := domain.ContextWithPrincipal(context.Background(), tt.principal)
+			ctx = domain.ContextWithAuthorizer(ctx, authorizer)
+
+			assignment, err := New(repo).Execute(ctx, tt.input)
+			tt.validate(t, assignment, err)
+		})
+	}
+}
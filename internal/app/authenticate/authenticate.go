@@ -0,0 +1,48 @@
+package authenticate
+
+import (
+	"context"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// APIKeyRepository defines the repository contract for looking up API keys
+type APIKeyRepository interface {
+	FindAPIKeyByHash(ctx context.Context, hash string) (domain.APIKey, error)
+}
+
+// UseCase handles the authentication of clients
+type UseCase struct {
+	repo APIKeyRepository
+}
+
+// New creates a new Authenticate use case
+func New(repo APIKeyRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute returns the principal authenticated by the API key. Unknown and
+// revoked keys are rejected alike, not to tell which keys existed.
+func (uc *UseCase) Execute(ctx context.Context, key string) (_ domain.Principal, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "authenticate.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	apiKey, err := uc.repo.FindAPIKeyByHash(ctx, domain.HashAPIKey(key))
+	if err != nil && !domain.HasCode(err, domain.NotFoundCode) {
+		return domain.Principal{}, domain.NewErrorFrom(err,
+			domain.WithDetails("error finding API key"),
+		)
+	}
+
+	if err != nil || apiKey.Revoked() {
+		return domain.Principal{}, domain.NewError(domain.UnauthenticatedCode,
+			domain.WithMessage("invalid API key"),
+			domain.WithDetails("the API key is unknown or revoked"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	return apiKey.Principal(), nil
+}
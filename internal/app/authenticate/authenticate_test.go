@@ -0,0 +1,92 @@
+package authenticate
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/authenticate APIKeyRepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/authenticate/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	key := "mk_activekey_0123456789"
+	revokedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.MockAPIKeyRepository)
+		validate  func(*testing.T, domain.Principal, error)
+	}{
+		{
+			name: "success - returns the principal of the key",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					FindAPIKeyByHash(ctx, domain.HashAPIKey(key)).
+					Return(domain.APIKey{
+						ID:     uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+						Scopes: []domain.Scope{domain.ScopeMediaRead},
+					}, nil)
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "api-key:11111111-1111-1111-1111-111111111111", principal.Subject)
+				assert.True(t, principal.HasScope(domain.ScopeMediaRead))
+				assert.False(t, principal.HasScope(domain.ScopeMediaWrite))
+			},
+		},
+		{
+			name: "error - unknown key",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					FindAPIKeyByHash(ctx, gomock.Any()).
+					Return(domain.APIKey{}, domain.NewError(domain.NotFoundCode))
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.True(t, domain.HasCode(err, domain.UnauthenticatedCode))
+			},
+		},
+		{
+			name: "error - revoked key",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					FindAPIKeyByHash(ctx, gomock.Any()).
+					Return(domain.APIKey{RevokedAt: &revokedAt, Scopes: []domain.Scope{domain.ScopeAdmin}}, nil)
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.True(t, domain.HasCode(err, domain.UnauthenticatedCode))
+				assert.Empty(t, principal.Scopes)
+			},
+		},
+		{
+			name: "error - repository failure",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					FindAPIKeyByHash(ctx, gomock.Any()).
+					Return(domain.APIKey{}, domain.NewError(domain.InternalCode))
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.setupMock(repo)
+
+			principal, err := New(repo).Execute(ctx, key)
+			tt.validate(t, principal, err)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package authenticatetoken
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// TokenVerifier defines the contract for verifying bearer tokens
+type TokenVerifier interface {
+	// Verify checks the token and returns its claims. Invalid tokens are
+	// reported with domain.UnauthenticatedCode.
+	Verify(ctx context.Context, token string) (domain.TokenClaims, error)
+}
+
+// UseCase handles the authentication of clients by bearer token
+type UseCase struct {
+	verifier TokenVerifier
+}
+
+// New creates a new AuthenticateToken use case
+func New(verifier TokenVerifier) *UseCase {
+	return &UseCase{verifier: verifier}
+}
+
+// Execute returns the principal authenticated by the token. Its subject is the
+// sub claim of the token prefixed by domain.TokenSubjectPrefix, the tokens
+// being issued by a single issuer. Its scopes are the scopes of the token
+// known by the service, the others are ignored. The principal is bound to the
+// tenant named by the token, domain.DefaultTenant when it names none.
+func (uc *UseCase) Execute(ctx context.Context, token string) (_ domain.Principal, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "authenticatetoken.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	claims, err := uc.verifier.Verify(ctx, token)
+	if err != nil {
+		return domain.Principal{}, domain.NewErrorFrom(err)
+	}
+
+	if claims.Subject == "" {
+		return domain.Principal{}, domain.NewError(domain.UnauthenticatedCode,
+			domain.WithMessage("invalid token"),
+			domain.WithDetails("the token has no subject"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if claims.Tenant != "" && domain.ValidateTenant(claims.Tenant) != nil {
+		return domain.Principal{}, domain.NewError(domain.UnauthenticatedCode,
+			domain.WithMessage("invalid token"),
+			domain.WithDetails("the token names an invalid tenant"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	tenant := claims.Tenant
+	if tenant == "" {
+		tenant = domain.DefaultTenant
+	}
+
+	principal := domain.Principal{Subject: domain.TokenSubjectPrefix + claims.Subject, Scopes: []domain.Scope{}, Tenant: tenant}
+	for _, scope := range claims.Scopes {
+		if slices.Contains(domain.Scopes, domain.Scope(scope)) && !principal.HasScope(domain.Scope(scope)) {
+			principal.Scopes = append(principal.Scopes, domain.Scope(scope))
+		}
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,120 @@
+package authenticatetoken
+
+//go:generate mockgen -destination=mocks/mock_token_verifier.go -package=mocks github.com/peano88/medias/internal/app/authenticatetoken TokenVerifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/peano88/medias/internal/app/authenticatetoken/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.MockTokenVerifier)
+		validate  func(*testing.T, domain.Principal, error)
+	}{
+		{
+			name: "success - keeps the known scopes",
+			setupMock: func(v *mocks.MockTokenVerifier) {
+				v.EXPECT().Verify(ctx, "token").Return(domain.TokenClaims{
+					Subject: "user-42",
+					Scopes:  []string{"media:read", "openid", "media:read", "tags:write"},
+				}, nil)
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt:user-42", principal.Subject)
+				assert.Equal(t, domain.DefaultTenant, principal.Tenant)
+				assert.Equal(t, []domain.Scope{domain.ScopeMediaRead, domain.ScopeTagsWrite}, principal.Scopes)
+			},
+		},
+		{
+			name: "success - subject of an API key stays a token subject",
+			setupMock: func(v *mocks.MockTokenVerifier) {
+				v.EXPECT().Verify(ctx, "token").Return(domain.TokenClaims{
+					Subject: "api-key:123e4567-e89b-12d3-a456-426614174000",
+				}, nil)
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "jwt:api-key:123e4567-e89b-12d3-a456-426614174000", principal.Subject)
+			},
+		},
+		{
+			name: "success - principal bound to the tenant of the token",
+			setupMock: func(v *mocks.MockTokenVerifier) {
+				v.EXPECT().Verify(ctx, "token").Return(domain.TokenClaims{
+					Subject: "user-42",
+					Scopes:  []string{"media:read"},
+					Tenant:  "acme",
+				}, nil)
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "acme", principal.Tenant)
+			},
+		},
+		{
+			name: "error - invalid tenant",
+			setupMock: func(v *mocks.MockTokenVerifier) {
+				v.EXPECT().Verify(ctx, "token").Return(domain.TokenClaims{Subject: "user-42", Tenant: "../acme"}, nil)
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.True(t, domain.HasCode(err, domain.UnauthenticatedCode))
+			},
+		},
+		{
+			name: "error - no subject",
+			setupMock: func(v *mocks.MockTokenVerifier) {
+				v.EXPECT().Verify(ctx, "token").Return(domain.TokenClaims{Scopes: []string{"media:read"}}, nil)
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.True(t, domain.HasCode(err, domain.UnauthenticatedCode))
+			},
+		},
+		{
+			name: "error - invalid token",
+			setupMock: func(v *mocks.MockTokenVerifier) {
+				v.EXPECT().Verify(ctx, "token").
+					Return(domain.TokenClaims{}, domain.NewError(domain.UnauthenticatedCode, domain.WithDetails("token is expired")))
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.UnauthenticatedCode, domainErr.Code)
+					assert.Equal(t, "token is expired", domainErr.Details)
+				}
+			},
+		},
+		{
+			name: "error - unexpected failure",
+			setupMock: func(v *mocks.MockTokenVerifier) {
+				v.EXPECT().Verify(ctx, "token").Return(domain.TokenClaims{}, errors.New("boom"))
+			},
+			validate: func(t *testing.T, principal domain.Principal, err error) {
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			verifier := mocks.NewMockTokenVerifier(ctrl)
+			tt.setupMock(verifier)
+
+			principal, err := New(verifier).Execute(ctx, "token")
+			tt.validate(t, principal, err)
+		})
+	}
+}
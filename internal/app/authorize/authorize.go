@@ -0,0 +1,91 @@
+package authorize
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// RoleRepository defines the repository contract for resolving roles
+type RoleRepository interface {
+	// FindRoleAssignment reports a subject without role in the tenant with
+	// domain.NotFoundCode
+	FindRoleAssignment(ctx context.Context, subject, tenant string) (domain.RoleAssignment, error)
+}
+
+// AuditRecorder defines the contract for writing the audit trail
+type AuditRecorder interface {
+	RecordAuditEvent(ctx context.Context, event domain.AuditEvent) error
+}
+
+// UseCase handles the authorization of the principals against domain.Policy.
+// It implements domain.Authorizer.
+type UseCase struct {
+	roles RoleRepository
+	audit AuditRecorder
+}
+
+// New creates a new Authorize use case
+func New(roles RoleRepository, audit AuditRecorder) *UseCase {
+	return &UseCase{roles: roles, audit: audit}
+}
+
+// Authorize checks that the role of the principal carried by ctx, in the
+// tenant carried by ctx, allows the action. A principal without role
+// assignment has the role matching its scopes. Every denial is written to the
+// audit trail; when it cannot be, the action is still denied with an internal
+// error.
+func (uc *UseCase) Authorize(ctx context.Context, action domain.Action) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "authorize.Authorize")
+	defer telemetry.EndSpan(span, &err)
+
+	principal, _ := domain.PrincipalFromContext(ctx)
+	tenant := domain.TenantFromContext(ctx)
+
+	role, err := uc.role(ctx, principal, tenant)
+	if err != nil {
+		return domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error resolving role: %s", err)))
+	}
+
+	if role.Allows(action) {
+		return nil
+	}
+
+	details := fmt.Sprintf("the role %s does not allow %s", role, action)
+	if err := uc.audit.RecordAuditEvent(ctx, domain.AuditEvent{
+		Tenant:  tenant,
+		Subject: principal.Subject,
+		Action:  action,
+		Outcome: domain.AuditOutcomeDenied,
+		Details: details,
+	}); err != nil {
+		return domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error recording denial: %s", err)))
+	}
+
+	return domain.NewError(domain.ForbiddenCode,
+		domain.WithMessage("action not allowed"),
+		domain.WithDetails(details),
+		domain.WithTS(time.Now()),
+	)
+}
+
+func (uc *UseCase) role(ctx context.Context, principal domain.Principal, tenant string) (domain.Role, error) {
+	if principal.Subject == "" {
+		return domain.RoleFromScopes(principal.Scopes), nil
+	}
+
+	assignment, err := uc.roles.FindRoleAssignment(ctx, principal.Subject, tenant)
+	if err != nil {
+		if domain.HasCode(err, domain.NotFoundCode) {
+			return domain.RoleFromScopes(principal.Scopes), nil
+		}
+		return "", err
+	}
+
+	return assignment.Role, nil
+}
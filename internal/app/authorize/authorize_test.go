@@ -0,0 +1,121 @@
+package authorize
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/authorize RoleRepository,AuditRecorder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/app/authorize/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Authorize(t *testing.T) {
+	ctx := domain.ContextWithTenant(
+		domain.ContextWithPrincipal(context.Background(), domain.Principal{
+			Subject: "user-42",
+			Scopes:  []domain.Scope{domain.ScopeMediaRead, domain.ScopeMediaWrite},
+		}),
+		"acme",
+	)
+
+	tests := []struct {
+		name       string
+		action     domain.Action
+		setupMocks func(*mocks.MockRoleRepository, *mocks.MockAuditRecorder)
+		validate   func(*testing.T, error)
+	}{
+		{
+			name:   "allowed - assigned role",
+			action: domain.ActionWriteTags,
+			setupMocks: func(roles *mocks.MockRoleRepository, audit *mocks.MockAuditRecorder) {
+				roles.EXPECT().FindRoleAssignment(ctx, "user-42", "acme").
+					Return(domain.RoleAssignment{Subject: "user-42", Role: domain.RoleAdmin}, nil)
+			},
+			validate: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "allowed - role matching the scopes without assignment",
+			action: domain.ActionWriteMedia,
+			setupMocks: func(roles *mocks.MockRoleRepository, audit *mocks.MockAuditRecorder) {
+				roles.EXPECT().FindRoleAssignment(ctx, "user-42", "acme").
+					Return(domain.RoleAssignment{}, domain.NewError(domain.NotFoundCode))
+			},
+			validate: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "denied - assigned role takes precedence over the scopes",
+			action: domain.ActionWriteMedia,
+			setupMocks: func(roles *mocks.MockRoleRepository, audit *mocks.MockAuditRecorder) {
+				roles.EXPECT().FindRoleAssignment(ctx, "user-42", "acme").
+					Return(domain.RoleAssignment{Subject: "user-42", Role: domain.RoleViewer}, nil)
+				audit.EXPECT().RecordAuditEvent(ctx, domain.AuditEvent{
+					Tenant:  "acme",
+					Subject: "user-42",
+					Action:  domain.ActionWriteMedia,
+					Outcome: domain.AuditOutcomeDenied,
+					Details: "the role viewer does not allow media.write",
+				}).Return(nil)
+			},
+			validate: func(t *testing.T, err error) {
+				assert.True(t, domain.HasCode(err, domain.ForbiddenCode))
+			},
+		},
+		{
+			name:   "denied - role matching the scopes",
+			action: domain.ActionManageRoles,
+			setupMocks: func(roles *mocks.MockRoleRepository, audit *mocks.MockAuditRecorder) {
+				roles.EXPECT().FindRoleAssignment(ctx, "user-42", "acme").
+					Return(domain.RoleAssignment{}, domain.NewError(domain.NotFoundCode))
+				audit.EXPECT().RecordAuditEvent(ctx, gomock.Any()).Return(nil)
+			},
+			validate: func(t *testing.T, err error) {
+				assert.True(t, domain.HasCode(err, domain.ForbiddenCode))
+			},
+		},
+		{
+			name:   "error - denial cannot be recorded",
+			action: domain.ActionManageRoles,
+			setupMocks: func(roles *mocks.MockRoleRepository, audit *mocks.MockAuditRecorder) {
+				roles.EXPECT().FindRoleAssignment(ctx, "user-42", "acme").
+					Return(domain.RoleAssignment{Subject: "user-42", Role: domain.RoleEditor}, nil)
+				audit.EXPECT().RecordAuditEvent(ctx, gomock.Any()).
+					Return(domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			validate: func(t *testing.T, err error) {
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+		{
+			name:   "error - role cannot be resolved",
+			action: domain.ActionReadMedia,
+			setupMocks: func(roles *mocks.MockRoleRepository, audit *mocks.MockAuditRecorder) {
+				roles.EXPECT().FindRoleAssignment(ctx, "user-42", "acme").
+					Return(domain.RoleAssignment{}, domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			validate: func(t *testing.T, err error) {
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			roles := mocks.NewMockRoleRepository(ctrl)
+			audit := mocks.NewMockAuditRecorder(ctrl)
+			tt.setupMocks(roles, audit)
+
+			err := New(roles, audit).Authorize(ctx, tt.action)
+			tt.validate(t, err)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package createapikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+const (
+	// keyPrefix marks the keys issued by the service, which helps secret scanners
+	keyPrefix = "mk_"
+	// keyEntropyBytes is the number of random bytes of a generated key
+	keyEntropyBytes = 32
+	// MinBootstrapKeyLength is the minimum length of a key provided by configuration
+	MinBootstrapKeyLength = 32
+	// BootstrapKeyName is the name of the admin key provided by configuration
+	BootstrapKeyName = "bootstrap"
+)
+
+// APIKeyRepository defines the repository contract for creating API keys
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key domain.APIKey) (domain.APIKey, error)
+}
+
+// UseCase handles the creation of API keys
+type UseCase struct {
+	repo APIKeyRepository
+}
+
+// New creates a new CreateAPIKey use case
+func New(repo APIKeyRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute generates a new API key with the name, scopes and tenant of input,
+// by default the tenant of the request. Only a principal granted
+// domain.ScopeAllTenants may create keys for other tenants, or keys acting on
+// every tenant. The returned key is the only one holding the key in clear.
+func (uc *UseCase) Execute(ctx context.Context, input domain.APIKey) (_ domain.APIKey, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "createapikey.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	name := strings.TrimSpace(input.Name)
+	if len(name) == 0 || len(name) > 100 {
+		return domain.APIKey{}, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid name"),
+			domain.WithDetails("name is mandatory and should be less than 100 characters"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	if err := domain.ValidateScopes(input.Scopes); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	tenant, err := domain.BindTenant(ctx, input.Tenant)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	if tenant == "" {
+		tenant = domain.TenantFromContext(ctx)
+	}
+
+	if slices.Contains(input.Scopes, domain.ScopeAllTenants) {
+		if principal, ok := domain.PrincipalFromContext(ctx); ok && !principal.HasScope(domain.ScopeAllTenants) {
+			return domain.APIKey{}, domain.NewError(domain.ForbiddenCode,
+				domain.WithMessage("scope not allowed"),
+				domain.WithDetails(fmt.Sprintf("only a principal granted %s may grant it", domain.ScopeAllTenants)),
+				domain.WithTS(time.Now()),
+			)
+		}
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return domain.APIKey{}, domain.NewError(domain.InternalCode,
+			domain.WithMessage("failed to generate API key"),
+			domain.WithDetails(err.Error()),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	created, err := uc.create(ctx, name, key, input.Scopes, tenant)
+	if err != nil {
+		return domain.APIKey{}, domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error creating API key: %s", err)))
+	}
+
+	return created, nil
+}
+
+// Bootstrap makes sure the admin key provided by configuration exists, so that
+// the other keys can be created through the API. The key belongs to the
+// default tenant and may act on every tenant.
+func (uc *UseCase) Bootstrap(ctx context.Context, key string) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "createapikey.Bootstrap")
+	defer telemetry.EndSpan(span, &err)
+
+	if len(key) < MinBootstrapKeyLength {
+		return domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid bootstrap key"),
+			domain.WithDetails(fmt.Sprintf("bootstrap key should be at least %d characters", MinBootstrapKeyLength)),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	_, err = uc.create(ctx, BootstrapKeyName, key, []domain.Scope{domain.ScopeAdmin, domain.ScopeAllTenants}, domain.DefaultTenant)
+	if err != nil && !domain.HasCode(err, domain.ConflictCode) {
+		return domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error creating bootstrap key: %s", err)))
+	}
+
+	return nil
+}
+
+func (uc *UseCase) create(ctx context.Context, name, key string, scopes []domain.Scope, tenant string) (domain.APIKey, error) {
+	created, err := uc.repo.CreateAPIKey(ctx, domain.APIKey{
+		Name:   name,
+		Prefix: key[:domain.APIKeyPrefixLength],
+		Hash:   domain.HashAPIKey(key),
+		Scopes: scopes,
+		Tenant: tenant,
+	})
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	created.Key = key
+	return created, nil
+}
+
+func generateKey() (string, error) {
+	b := make([]byte, keyEntropyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,294 @@
+package createapikey
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/createapikey APIKeyRepository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/createapikey/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		input     domain.APIKey
+		setupMock func(*mocks.MockAPIKeyRepository)
+		validate  func(*testing.T, domain.APIKey, error)
+	}{
+		{
+			name:  "success - returns the key in clear once",
+			input: domain.APIKey{Name: "  uploader  ", Scopes: []domain.Scope{domain.ScopeMediaWrite}},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, key domain.APIKey) (domain.APIKey, error) {
+						assert.Equal(t, "uploader", key.Name)
+						assert.Equal(t, domain.DefaultTenant, key.Tenant)
+						assert.Empty(t, key.Key)
+						assert.Len(t, key.Hash, 64)
+						assert.True(t, strings.HasPrefix(key.Prefix, keyPrefix))
+						key.ID = uuid.MustParse("11111111-1111-1111-1111-111111111111")
+						key.CreatedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+						return key, nil
+					})
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.NoError(t, err)
+				assert.True(t, strings.HasPrefix(key.Key, keyPrefix))
+				assert.Equal(t, domain.HashAPIKey(key.Key), key.Hash)
+				assert.Equal(t, key.Key[:domain.APIKeyPrefixLength], key.Prefix)
+			},
+		},
+		{
+			name:      "error - missing name",
+			input:     domain.APIKey{Name: " ", Scopes: []domain.Scope{domain.ScopeMediaWrite}},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:      "error - unknown scope",
+			input:     domain.APIKey{Name: "uploader", Scopes: []domain.Scope{"media:delete"}},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:  "success - key bound to a tenant",
+			input: domain.APIKey{Name: "acme uploader", Scopes: []domain.Scope{domain.ScopeMediaWrite}, Tenant: "acme"},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, key domain.APIKey) (domain.APIKey, error) {
+						assert.Equal(t, "acme", key.Tenant)
+						return key, nil
+					})
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "acme", key.Tenant)
+			},
+		},
+		{
+			name:      "error - invalid tenant",
+			input:     domain.APIKey{Name: "uploader", Scopes: []domain.Scope{domain.ScopeMediaWrite}, Tenant: "Acme Corp"},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:      "error - no scope",
+			input:     domain.APIKey{Name: "uploader"},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:  "error - repository failure",
+			input: domain.APIKey{Name: "uploader", Scopes: []domain.Scope{domain.ScopeMediaWrite}},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(ctx, gomock.Any()).
+					Return(domain.APIKey{}, domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+				assert.Empty(t, key.Key)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.setupMock(repo)
+
+			key, err := New(repo).Execute(ctx, tt.input)
+			tt.validate(t, key, err)
+		})
+	}
+}
+
+func TestUseCase_Execute_TenantPrincipal(t *testing.T) {
+	acmeAdmin := domain.Principal{
+		Subject: "acme-admin",
+		Scopes:  []domain.Scope{domain.ScopeAdmin},
+		Tenant:  "acme",
+	}
+	operator := domain.Principal{
+		Subject: "operator",
+		Scopes:  []domain.Scope{domain.ScopeAdmin, domain.ScopeAllTenants},
+		Tenant:  domain.DefaultTenant,
+	}
+
+	tests := []struct {
+		name      string
+		principal domain.Principal
+		tenant    string
+		scopes    []domain.Scope
+		setupMock func(*mocks.MockAPIKeyRepository)
+		validate  func(*testing.T, domain.APIKey, error)
+	}{
+		{
+			name:      "success - key bound to the tenant of the principal",
+			principal: acmeAdmin,
+			tenant:    "",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key domain.APIKey) (domain.APIKey, error) {
+						return key, nil
+					})
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "acme", key.Tenant)
+			},
+		},
+		{
+			name:      "error - another tenant",
+			principal: acmeAdmin,
+			tenant:    "globex",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.True(t, domain.HasCode(err, domain.ForbiddenCode))
+			},
+		},
+		{
+			name:      "error - granting every tenant",
+			principal: acmeAdmin,
+			scopes:    []domain.Scope{domain.ScopeMediaWrite, domain.ScopeAllTenants},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.True(t, domain.HasCode(err, domain.ForbiddenCode))
+			},
+		},
+		{
+			name:      "success - principal granted every tenant creates a key for another tenant",
+			principal: operator,
+			tenant:    "globex",
+			scopes:    []domain.Scope{domain.ScopeMediaWrite, domain.ScopeAllTenants},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, key domain.APIKey) (domain.APIKey, error) {
+						return key, nil
+					})
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "globex", key.Tenant)
+				assert.Contains(t, key.Scopes, domain.ScopeAllTenants)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.setupMock(repo)
+
+			scopes := tt.scopes
+			if scopes == nil {
+				scopes = []domain.Scope{domain.ScopeMediaWrite}
+			}
+
+			ctx := domain.ContextWithPrincipal(context.Background(), tt.principal)
+			key, err := New(repo).Execute(ctx, domain.APIKey{
+				Name:   "uploader",
+				Scopes: scopes,
+				Tenant: tt.tenant,
+			})
+			tt.validate(t, key, err)
+		})
+	}
+}
+
+func TestUseCase_Bootstrap(t *testing.T) {
+	ctx := context.Background()
+	bootstrapKey := strings.Repeat("b", MinBootstrapKeyLength)
+
+	tests := []struct {
+		name      string
+		key       string
+		setupMock func(*mocks.MockAPIKeyRepository)
+		wantCode  string
+	}{
+		{
+			name: "success - creates the admin key",
+			key:  bootstrapKey,
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(ctx, domain.APIKey{
+						Name:   BootstrapKeyName,
+						Prefix: bootstrapKey[:domain.APIKeyPrefixLength],
+						Hash:   domain.HashAPIKey(bootstrapKey),
+						Scopes: []domain.Scope{domain.ScopeAdmin, domain.ScopeAllTenants},
+						Tenant: domain.DefaultTenant,
+					}).
+					Return(domain.APIKey{}, nil)
+			},
+		},
+		{
+			name: "success - key already exists",
+			key:  bootstrapKey,
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(ctx, gomock.Any()).
+					Return(domain.APIKey{}, domain.NewError(domain.ConflictCode))
+			},
+		},
+		{
+			name:      "error - key too short",
+			key:       "short",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {},
+			wantCode:  domain.InvalidEntityCode,
+		},
+		{
+			name: "error - repository failure",
+			key:  bootstrapKey,
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					CreateAPIKey(ctx, gomock.Any()).
+					Return(domain.APIKey{}, domain.NewError(domain.InternalCode))
+			},
+			wantCode: domain.InternalCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.setupMock(repo)
+
+			err := New(repo).Bootstrap(ctx, tt.key)
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, domain.HasCode(err, tt.wantCode))
+		})
+	}
+}
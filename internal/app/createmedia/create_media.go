@@ -3,15 +3,20 @@ package createmedia
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"sync/atomic"
 
 	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
 )
 
 // MediaRepository defines the repository contract for creating media
 type MediaRepository interface {
 	FindByFilenameAndSHA256(ctx context.Context, filename, sha256 string) (domain.Media, error)
 	CreateMedia(ctx context.Context, media domain.Media, tagNames []string) (domain.Media, error)
+	FindByFilenamesAndSHA256s(ctx context.Context, medias []domain.Media) ([]domain.Media, error)
+	CreateMediaBatch(ctx context.Context, items []domain.MediaBatchItem) ([]domain.MediaBatchResult, error)
 }
 
 // MediaSaver defines the contract for generating media URLs
@@ -19,25 +24,81 @@ type MediaSaver interface {
 	GenerateUploadURL(ctx context.Context, media domain.Media) (string, error)
 }
 
+// Metrics defines the contract for recording the media lifecycle metrics
+type Metrics interface {
+	// MediaReserved records a media created reserved, waiting for its upload
+	MediaReserved(mediaType domain.MediaType)
+	// UploadURLReissued records an upload URL issued again for a reserved media
+	UploadURLReissued(mediaType domain.MediaType)
+}
+
 // UseCase handles creating new media records
 type UseCase struct {
 	mediaRepo MediaRepository
 	saver     MediaSaver
+	metrics   Metrics
+	// allowedMimeTypes restricts the accepted MIME types, see SetAllowedMimeTypes
+	allowedMimeTypes atomic.Pointer[[]string]
+}
+
+// Option configures the CreateMedia use case
+type Option func(*UseCase)
+
+// WithMetrics sets the recorder of the media lifecycle metrics, none are
+// recorded otherwise
+func WithMetrics(metrics Metrics) Option {
+	return func(uc *UseCase) {
+		uc.metrics = metrics
+	}
+}
+
+// WithAllowedMimeTypes restricts the accepted MIME types, see SetAllowedMimeTypes
+func WithAllowedMimeTypes(patterns []string) Option {
+	return func(uc *UseCase) {
+		uc.SetAllowedMimeTypes(patterns)
+	}
 }
 
 // New creates a new CreateMedia use case
-func New(mediaRepo MediaRepository, saver MediaSaver) *UseCase {
-	return &UseCase{
+func New(mediaRepo MediaRepository, saver MediaSaver, opts ...Option) *UseCase {
+	uc := &UseCase{
 		mediaRepo: mediaRepo,
 		saver:     saver,
+		metrics:   noMetrics{},
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
-// Execute creates a new media record with reserved status or returns existing one
-func (uc *UseCase) Execute(ctx context.Context, input domain.Media, tagNames []string) (domain.Media, error) {
-	if err := validateMedia(&input); err != nil {
+type noMetrics struct{}
+
+func (noMetrics) MediaReserved(domain.MediaType)     {}
+func (noMetrics) UploadURLReissued(domain.MediaType) {}
+
+// SetAllowedMimeTypes restricts the MIME types accepted from now on to
+// patterns, such as image/png or image/*. No pattern accepts every image and
+// video type.
+func (uc *UseCase) SetAllowedMimeTypes(patterns []string) {
+	normalized := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		normalized[i] = strings.ToLower(strings.TrimSpace(pattern))
+	}
+	uc.allowedMimeTypes.Store(&normalized)
+}
+
+// Execute creates a new media record with reserved status or returns existing
+// one. The media belongs to the principal and the tenant carried by ctx.
+func (uc *UseCase) Execute(ctx context.Context, input domain.Media, tagNames []string) (_ domain.Media, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "createmedia.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	if err := uc.validateMedia(&input); err != nil {
 		return domain.Media{}, err
 	}
+	input.Owner = domain.SubjectFromContext(ctx)
+	input.Tenant = domain.TenantFromContext(ctx)
 
 	existing, err := uc.mediaRepo.FindByFilenameAndSHA256(ctx, input.Filename, input.SHA256)
 	if err == nil {
@@ -68,10 +129,131 @@ func (uc *UseCase) Execute(ctx context.Context, input domain.Media, tagNames []s
 	}
 	createdMedia.URL = url
 	createdMedia.Operation = domain.MediaOperationCreate
+	uc.metrics.MediaReserved(createdMedia.Type)
 
 	return createdMedia, nil
 }
 
+// ExecuteBatch applies the semantics of Execute to each item of the batch,
+// looking up and creating the media with a constant number of repository
+// calls. Results are aligned with items; the returned error is only set when
+// the batch as a whole could not be processed.
+func (uc *UseCase) ExecuteBatch(ctx context.Context, items []domain.MediaBatchItem) (_ []domain.MediaBatchResult, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "createmedia.ExecuteBatch")
+	defer telemetry.EndSpan(span, &err)
+
+	if len(items) == 0 {
+		return nil, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid batch"),
+			domain.WithDetails("batch cannot be empty"),
+		)
+	}
+	if len(items) > domain.MaxBatchSize {
+		return nil, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid batch"),
+			domain.WithDetails(fmt.Sprintf("batch cannot exceed %d items", domain.MaxBatchSize)),
+		)
+	}
+
+	results := make([]domain.MediaBatchResult, len(items))
+	// Validation derives the media type, work on a copy to leave the caller's items untouched
+	items = slices.Clone(items)
+	owner := domain.SubjectFromContext(ctx)
+	tenant := domain.TenantFromContext(ctx)
+
+	// Validate items and reject duplicates within the batch
+	var valid []int
+	var lookup []domain.Media
+	seen := make(map[string]bool, len(items))
+	for i := range items {
+		if err := uc.validateMedia(&items[i].Media); err != nil {
+			results[i].Err = err
+			continue
+		}
+		items[i].Media.Owner = owner
+		items[i].Media.Tenant = tenant
+
+		key := mediaKey(items[i].Media)
+		if seen[key] {
+			results[i].Err = domain.NewError(domain.ConflictCode,
+				domain.WithMessage("duplicate media in batch"),
+				domain.WithDetails("a media file with this filename and sha256 appears earlier in the batch"),
+			)
+			continue
+		}
+		seen[key] = true
+
+		valid = append(valid, i)
+		lookup = append(lookup, items[i].Media)
+	}
+
+	existing, err := uc.mediaRepo.FindByFilenamesAndSHA256s(ctx, lookup)
+	if err != nil {
+		return nil, domain.NewErrorFrom(err,
+			domain.WithDetails("error checking for existing media"),
+		)
+	}
+	existingByKey := make(map[string]domain.Media, len(existing))
+	for _, media := range existing {
+		existingByKey[mediaKey(media)] = media
+	}
+
+	var toCreate []domain.MediaBatchItem
+	var toCreateIdx []int
+	var urls []string
+	for _, i := range valid {
+		input := items[i].Media
+		if media, ok := existingByKey[mediaKey(input)]; ok {
+			results[i].Media, results[i].Err = uc.handleExistingMedia(ctx, media, &input, items[i].TagNames)
+			continue
+		}
+
+		input.Status = domain.MediaStatusReserved
+
+		url, err := uc.saver.GenerateUploadURL(ctx, input)
+		if err != nil {
+			results[i].Err = domain.NewErrorFrom(err,
+				domain.WithDetails(fmt.Sprintf("failed to generate upload URL: %s", err)),
+			)
+			continue
+		}
+
+		toCreate = append(toCreate, domain.MediaBatchItem{Media: input, TagNames: items[i].TagNames})
+		toCreateIdx = append(toCreateIdx, i)
+		urls = append(urls, url)
+	}
+
+	if len(toCreate) == 0 {
+		return results, nil
+	}
+
+	created, err := uc.mediaRepo.CreateMediaBatch(ctx, toCreate)
+	if err != nil {
+		return nil, domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error creating media: %s", err)),
+		)
+	}
+
+	for j, result := range created {
+		i := toCreateIdx[j]
+		if result.Err != nil {
+			results[i].Err = result.Err
+			continue
+		}
+		result.Media.URL = urls[j]
+		result.Media.Operation = domain.MediaOperationCreate
+		results[i].Media = result.Media
+		uc.metrics.MediaReserved(result.Media.Type)
+	}
+
+	return results, nil
+}
+
+// mediaKey identifies a media by its unique filename and sha256 pair
+func mediaKey(media domain.Media) string {
+	return media.Filename + "\x00" + media.SHA256
+}
+
 func (uc *UseCase) handleExistingMedia(ctx context.Context, existing domain.Media, input *domain.Media, tagNames []string) (domain.Media, error) {
 	switch existing.Status {
 	case domain.MediaStatusReserved:
@@ -92,6 +274,7 @@ func (uc *UseCase) handleExistingMedia(ctx context.Context, existing domain.Medi
 		}
 		existing.URL = url
 		existing.Operation = domain.MediaOperationUpdate
+		uc.metrics.UploadURLReissued(existing.Type)
 		return existing, nil
 
 	case domain.MediaStatusFinalized:
@@ -114,7 +297,7 @@ func (uc *UseCase) handleExistingMedia(ctx context.Context, existing domain.Medi
 	}
 }
 
-func validateMedia(media *domain.Media) error {
+func (uc *UseCase) validateMedia(media *domain.Media) error {
 	// Validate filename
 	if strings.TrimSpace(media.Filename) == "" {
 		return domain.NewError(domain.InvalidEntityCode,
@@ -150,6 +333,9 @@ func validateMedia(media *domain.Media) error {
 		return err
 	}
 	media.Type = mediaType
+	if err := uc.checkMimeTypeAllowed(media.MimeType); err != nil {
+		return err
+	}
 
 	// Validate size
 	if media.Size <= 0 {
@@ -167,7 +353,7 @@ func validateMedia(media *domain.Media) error {
 		)
 	}
 
-	return nil
+	return domain.ValidateVisibility(&media.Visibility)
 }
 
 func deriveMediaType(mimeType string) (domain.MediaType, error) {
@@ -186,6 +372,31 @@ func deriveMediaType(mimeType string) (domain.MediaType, error) {
 	)
 }
 
+// checkMimeTypeAllowed checks mimeType, its parameters ignored, matches one of
+// the allowed patterns
+func (uc *UseCase) checkMimeTypeAllowed(mimeType string) error {
+	allowed := uc.allowedMimeTypes.Load()
+	if allowed == nil || len(*allowed) == 0 {
+		return nil
+	}
+
+	essence, _, _ := strings.Cut(mimeType, ";")
+	essence = strings.ToLower(strings.TrimSpace(essence))
+	for _, pattern := range *allowed {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(essence, prefix) {
+			return nil
+		}
+		if essence == pattern {
+			return nil
+		}
+	}
+
+	return domain.NewError(domain.InvalidEntityCode,
+		domain.WithMessage("unsupported media type"),
+		domain.WithDetails(fmt.Sprintf("mimeType must be one of %s, got: %s", strings.Join(*allowed, ", "), mimeType)),
+	)
+}
+
 // tagsMatch checks if the existing tags match the provided tag names
 func tagsMatch(existingTags []domain.Tag, newTagNames []string) bool {
 	if len(existingTags) != len(newTagNames) {
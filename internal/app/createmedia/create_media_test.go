@@ -2,6 +2,7 @@ package createmedia
 
 //go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/createmedia MediaRepository
 //go:generate mockgen -destination=mocks/mock_saver.go -package=mocks github.com/peano88/medias/internal/app/createmedia MediaSaver
+//go:generate mockgen -destination=mocks/mock_metrics.go -package=mocks github.com/peano88/medias/internal/app/createmedia Metrics
 
 import (
 	"context"
@@ -50,6 +51,8 @@ func TestUseCase_Execute(t *testing.T) {
 					Size:        2048000,
 					SHA256:      "a1b2c3d4e5f6",
 					Status:      domain.MediaStatusReserved,
+					Visibility:  domain.VisibilityPrivate,
+					Tenant:      domain.DefaultTenant,
 				}
 				saver.EXPECT().
 					GenerateUploadURL(ctx, expectedMedia).
@@ -525,6 +528,8 @@ func TestUseCase_Execute(t *testing.T) {
 					Size:        4000000,
 					SHA256:      "surf123",
 					Status:      domain.MediaStatusReserved,
+					Visibility:  domain.VisibilityPrivate,
+					Tenant:      domain.DefaultTenant,
 				}
 				saver.EXPECT().
 					GenerateUploadURL(ctx, expectedMedia).
@@ -564,12 +569,14 @@ func TestUseCase_Execute(t *testing.T) {
 
 				// Generate URL fails
 				expectedMedia := domain.Media{
-					Filename: "cycling-race.jpg",
-					MimeType: "image/jpeg",
-					Type:     domain.MediaTypeImage,
-					Size:     2500000,
-					SHA256:   "cycl3",
-					Status:   domain.MediaStatusReserved,
+					Filename:   "cycling-race.jpg",
+					MimeType:   "image/jpeg",
+					Type:       domain.MediaTypeImage,
+					Size:       2500000,
+					SHA256:     "cycl3",
+					Status:     domain.MediaStatusReserved,
+					Visibility: domain.VisibilityPrivate,
+					Tenant:     domain.DefaultTenant,
 				}
 				saver.EXPECT().
 					GenerateUploadURL(ctx, expectedMedia).
@@ -647,6 +654,386 @@ func TestUseCase_Execute(t *testing.T) {
 	}
 }
 
+func TestUseCase_Execute_Owner(t *testing.T) {
+	ctx := domain.ContextWithTenant(
+		domain.ContextWithPrincipal(context.Background(), domain.Principal{Subject: "user-42"}),
+		"acme",
+	)
+
+	tests := []struct {
+		name       string
+		visibility domain.Visibility
+		setupMocks func(*mocks.MockMediaRepository, *mocks.MockMediaSaver)
+		validate   func(*testing.T, domain.Media, error)
+	}{
+		{
+			name:       "success - media owned by the principal",
+			visibility: domain.VisibilityShared,
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver) {
+				repo.EXPECT().
+					FindByFilenameAndSHA256(ctx, "kick-off.jpg", "k1ck0ff").
+					Return(domain.Media{}, domain.NewError(domain.NotFoundCode))
+				saver.EXPECT().GenerateUploadURL(ctx, gomock.Any()).Return("http://localhost:8080/upload", nil)
+				repo.EXPECT().
+					CreateMedia(ctx, gomock.Any(), nil).
+					DoAndReturn(func(_ context.Context, m domain.Media, _ []string) (domain.Media, error) {
+						return m, nil
+					})
+			},
+			validate: func(t *testing.T, result domain.Media, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-42", result.Owner)
+				assert.Equal(t, "acme", result.Tenant)
+				assert.Equal(t, domain.VisibilityShared, result.Visibility)
+			},
+		},
+		{
+			name:       "validation error - unknown visibility",
+			visibility: "friends",
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver) {},
+			validate: func(t *testing.T, result domain.Media, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			saver := mocks.NewMockMediaSaver(ctrl)
+			tt.setupMocks(repo, saver)
+
+			uc := New(repo, saver)
+			result, err := uc.Execute(ctx, domain.Media{
+				Filename:   "kick-off.jpg",
+				MimeType:   "image/jpeg",
+				Size:       1000,
+				SHA256:     "k1ck0ff",
+				Visibility: tt.visibility,
+			}, nil)
+
+			tt.validate(t, result, err)
+		})
+	}
+}
+
+func TestUseCase_Execute_AllowedMimeTypes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		allowed  []string
+		mimeType string
+		wantErr  bool
+	}{
+		{
+			name:     "success - every image and video type by default",
+			mimeType: "video/webm",
+		},
+		{
+			name:     "success - exact type",
+			allowed:  []string{"image/png", "video/mp4"},
+			mimeType: "Video/MP4; codecs=avc1",
+		},
+		{
+			name:     "success - wildcard type",
+			allowed:  []string{"image/*"},
+			mimeType: "image/webp",
+		},
+		{
+			name:     "validation error - type not allowed",
+			allowed:  []string{"image/*"},
+			mimeType: "video/mp4",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			saver := mocks.NewMockMediaSaver(ctrl)
+			if !tt.wantErr {
+				repo.EXPECT().
+					FindByFilenameAndSHA256(ctx, "kick-off", "k1ck0ff").
+					Return(domain.Media{}, domain.NewError(domain.NotFoundCode))
+				saver.EXPECT().GenerateUploadURL(ctx, gomock.Any()).Return("http://localhost:8080/upload", nil)
+				repo.EXPECT().
+					CreateMedia(ctx, gomock.Any(), nil).
+					DoAndReturn(func(_ context.Context, m domain.Media, _ []string) (domain.Media, error) {
+						return m, nil
+					})
+			}
+
+			uc := New(repo, saver, WithAllowedMimeTypes(tt.allowed))
+			_, err := uc.Execute(ctx, domain.Media{
+				Filename: "kick-off",
+				MimeType: tt.mimeType,
+				Size:     1000,
+				SHA256:   "k1ck0ff",
+			}, nil)
+
+			if tt.wantErr {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUseCase_SetAllowedMimeTypes(t *testing.T) {
+	uc := New(nil, nil, WithAllowedMimeTypes([]string{"image/*"}))
+	assert.Error(t, uc.checkMimeTypeAllowed("video/mp4"))
+
+	uc.SetAllowedMimeTypes([]string{" VIDEO/* "})
+	assert.NoError(t, uc.checkMimeTypeAllowed("video/mp4"))
+	assert.Error(t, uc.checkMimeTypeAllowed("image/png"))
+}
+
+func TestUseCase_Execute_Metrics(t *testing.T) {
+	ctx := context.Background()
+	input := domain.Media{
+		Filename: "penalty.mp4",
+		MimeType: "video/mp4",
+		Size:     4096,
+		SHA256:   "p3n4lty",
+	}
+
+	tests := []struct {
+		name       string
+		setupMocks func(*mocks.MockMediaRepository, *mocks.MockMediaSaver, *mocks.MockMetrics)
+	}{
+		{
+			name: "new media is recorded as reserved",
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver, metrics *mocks.MockMetrics) {
+				repo.EXPECT().
+					FindByFilenameAndSHA256(ctx, "penalty.mp4", "p3n4lty").
+					Return(domain.Media{}, domain.NewError(domain.NotFoundCode))
+				saver.EXPECT().GenerateUploadURL(ctx, gomock.Any()).Return("http://localhost:8080/upload", nil)
+				repo.EXPECT().
+					CreateMedia(ctx, gomock.Any(), nil).
+					DoAndReturn(func(_ context.Context, m domain.Media, _ []string) (domain.Media, error) {
+						return m, nil
+					})
+				metrics.EXPECT().MediaReserved(domain.MediaTypeVideo)
+			},
+		},
+		{
+			name: "reserved media is recorded as re-issued",
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver, metrics *mocks.MockMetrics) {
+				existing := domain.Media{
+					ID:       uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+					Filename: "penalty.mp4",
+					Type:     domain.MediaTypeVideo,
+					Status:   domain.MediaStatusReserved,
+				}
+				repo.EXPECT().
+					FindByFilenameAndSHA256(ctx, "penalty.mp4", "p3n4lty").
+					Return(existing, nil)
+				saver.EXPECT().GenerateUploadURL(ctx, existing).Return("http://localhost:8080/upload", nil)
+				metrics.EXPECT().UploadURLReissued(domain.MediaTypeVideo)
+			},
+		},
+		{
+			name: "failed creation is not recorded",
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver, metrics *mocks.MockMetrics) {
+				repo.EXPECT().
+					FindByFilenameAndSHA256(ctx, "penalty.mp4", "p3n4lty").
+					Return(domain.Media{}, domain.NewError(domain.NotFoundCode))
+				saver.EXPECT().GenerateUploadURL(ctx, gomock.Any()).Return("http://localhost:8080/upload", nil)
+				repo.EXPECT().
+					CreateMedia(ctx, gomock.Any(), nil).
+					Return(domain.Media{}, domain.NewError(domain.InternalCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			saver := mocks.NewMockMediaSaver(ctrl)
+			metrics := mocks.NewMockMetrics(ctrl)
+			tt.setupMocks(repo, saver, metrics)
+
+			uc := New(repo, saver, WithMetrics(metrics))
+			_, _ = uc.Execute(ctx, input, nil)
+		})
+	}
+}
+
+func TestUseCase_ExecuteBatch(t *testing.T) {
+	ctx := context.Background()
+
+	reservedID := uuid.MustParse("dddddddd-dddd-dddd-dddd-dddddddddddd")
+	createdID := uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
+
+	tests := []struct {
+		name       string
+		items      []domain.MediaBatchItem
+		setupMocks func(*mocks.MockMediaRepository, *mocks.MockMediaSaver)
+		validate   func(*testing.T, []domain.MediaBatchResult, error)
+	}{
+		{
+			name: "success - mixed created, reissued and failed items",
+			items: []domain.MediaBatchItem{
+				{Media: domain.Media{Filename: "corner-kick.jpg", MimeType: "image/jpeg", Size: 1000, SHA256: "c0rn3r"}, TagNames: []string{"soccer"}},
+				{Media: domain.Media{Filename: "slam-dunk.mp4", MimeType: "video/mp4", Size: 2000, SHA256: "sl4md"}},
+				{Media: domain.Media{Filename: "", MimeType: "image/jpeg", Size: 1000, SHA256: "empty"}},
+				{Media: domain.Media{Filename: "corner-kick.jpg", MimeType: "image/jpeg", Size: 1000, SHA256: "c0rn3r"}},
+				{Media: domain.Media{Filename: "final-score.jpg", MimeType: "image/jpeg", Size: 3000, SHA256: "f1n4l"}},
+			},
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver) {
+				repo.EXPECT().
+					FindByFilenamesAndSHA256s(ctx, []domain.Media{
+						{Filename: "corner-kick.jpg", Type: domain.MediaTypeImage, MimeType: "image/jpeg", Size: 1000, SHA256: "c0rn3r", Visibility: domain.VisibilityPrivate, Tenant: domain.DefaultTenant},
+						{Filename: "slam-dunk.mp4", Type: domain.MediaTypeVideo, MimeType: "video/mp4", Size: 2000, SHA256: "sl4md", Visibility: domain.VisibilityPrivate, Tenant: domain.DefaultTenant},
+						{Filename: "final-score.jpg", Type: domain.MediaTypeImage, MimeType: "image/jpeg", Size: 3000, SHA256: "f1n4l", Visibility: domain.VisibilityPrivate, Tenant: domain.DefaultTenant},
+					}).
+					Return([]domain.Media{
+						{ID: reservedID, Filename: "slam-dunk.mp4", Status: domain.MediaStatusReserved, SHA256: "sl4md", Tags: []domain.Tag{}},
+						{Filename: "final-score.jpg", Status: domain.MediaStatusFinalized, SHA256: "f1n4l"},
+					}, nil)
+
+				saver.EXPECT().
+					GenerateUploadURL(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, m domain.Media) (string, error) {
+						return "http://localhost:8080/upload/" + m.SHA256 + "/" + m.Filename, nil
+					}).
+					Times(2)
+
+				repo.EXPECT().
+					CreateMediaBatch(ctx, []domain.MediaBatchItem{
+						{
+							Media:    domain.Media{Filename: "corner-kick.jpg", Status: domain.MediaStatusReserved, Type: domain.MediaTypeImage, MimeType: "image/jpeg", Size: 1000, SHA256: "c0rn3r", Visibility: domain.VisibilityPrivate, Tenant: domain.DefaultTenant},
+							TagNames: []string{"soccer"},
+						},
+					}).
+					Return([]domain.MediaBatchResult{
+						{Media: domain.Media{ID: createdID, Filename: "corner-kick.jpg", Status: domain.MediaStatusReserved, SHA256: "c0rn3r"}},
+					}, nil)
+			},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, results, 5)
+
+				assert.NoError(t, results[0].Err)
+				assert.Equal(t, createdID, results[0].Media.ID)
+				assert.Equal(t, domain.MediaOperationCreate, results[0].Media.Operation)
+				assert.Equal(t, "http://localhost:8080/upload/c0rn3r/corner-kick.jpg", results[0].Media.URL)
+
+				assert.NoError(t, results[1].Err)
+				assert.Equal(t, reservedID, results[1].Media.ID)
+				assert.Equal(t, domain.MediaOperationUpdate, results[1].Media.Operation)
+				assert.Equal(t, "http://localhost:8080/upload/sl4md/slam-dunk.mp4", results[1].Media.URL)
+
+				assert.True(t, domain.HasCode(results[2].Err, domain.InvalidEntityCode))
+				assert.True(t, domain.HasCode(results[3].Err, domain.ConflictCode))
+				assert.True(t, domain.HasCode(results[4].Err, domain.ConflictCode))
+			},
+		},
+		{
+			name: "success - repository reports item errors",
+			items: []domain.MediaBatchItem{
+				{Media: domain.Media{Filename: "corner-kick.jpg", MimeType: "image/jpeg", Size: 1000, SHA256: "c0rn3r"}, TagNames: []string{"unknown"}},
+			},
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver) {
+				repo.EXPECT().FindByFilenamesAndSHA256s(ctx, gomock.Any()).Return([]domain.Media{}, nil)
+				saver.EXPECT().GenerateUploadURL(ctx, gomock.Any()).Return("http://localhost:8080/upload", nil)
+				repo.EXPECT().
+					CreateMediaBatch(ctx, gomock.Any()).
+					Return([]domain.MediaBatchResult{
+						{Err: domain.NewError(domain.InvalidEntityCode, domain.WithMessage("some tags not found"))},
+					}, nil)
+			},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, results, 1)
+				assert.True(t, domain.HasCode(results[0].Err, domain.InvalidEntityCode))
+				assert.Empty(t, results[0].Media.URL)
+			},
+		},
+		{
+			name:       "error - empty batch",
+			items:      []domain.MediaBatchItem{},
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver) {},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.Nil(t, results)
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:       "error - batch too large",
+			items:      make([]domain.MediaBatchItem, domain.MaxBatchSize+1),
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver) {},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.Nil(t, results)
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name: "error - lookup fails",
+			items: []domain.MediaBatchItem{
+				{Media: domain.Media{Filename: "corner-kick.jpg", MimeType: "image/jpeg", Size: 1000, SHA256: "c0rn3r"}},
+			},
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver) {
+				repo.EXPECT().
+					FindByFilenamesAndSHA256s(ctx, gomock.Any()).
+					Return(nil, domain.NewError(domain.InternalCode, domain.WithMessage("database error")))
+			},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.Nil(t, results)
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+		{
+			name: "error - batch creation fails",
+			items: []domain.MediaBatchItem{
+				{Media: domain.Media{Filename: "corner-kick.jpg", MimeType: "image/jpeg", Size: 1000, SHA256: "c0rn3r"}},
+			},
+			setupMocks: func(repo *mocks.MockMediaRepository, saver *mocks.MockMediaSaver) {
+				repo.EXPECT().FindByFilenamesAndSHA256s(ctx, gomock.Any()).Return([]domain.Media{}, nil)
+				saver.EXPECT().GenerateUploadURL(ctx, gomock.Any()).Return("http://localhost:8080/upload", nil)
+				repo.EXPECT().
+					CreateMediaBatch(ctx, gomock.Any()).
+					Return(nil, domain.NewError(domain.InternalCode, domain.WithMessage("failed to commit transaction")))
+			},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.Nil(t, results)
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InternalCode, domainErr.Code)
+					assert.Contains(t, domainErr.Details, "error creating media")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			saver := mocks.NewMockMediaSaver(ctrl)
+			tt.setupMocks(repo, saver)
+
+			uc := New(repo, saver)
+			results, err := uc.ExecuteBatch(ctx, tt.items)
+
+			tt.validate(t, results, err)
+		})
+	}
+}
+
 // Helper function
 func stringPtr(s string) *string {
 	return &s
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
 )
 
 // TagRepository defines the interface for tag persistence operations
@@ -56,7 +57,10 @@ func normalizeName(original string) string {
 }
 
 // Execute creates a new tag with the given input
-func (uc *UseCase) Execute(ctx context.Context, input domain.Tag) (domain.Tag, error) {
+func (uc *UseCase) Execute(ctx context.Context, input domain.Tag) (_ domain.Tag, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "createtag.Execute")
+	defer telemetry.EndSpan(span, &err)
+
 	if err := validateInput(input); err != nil {
 		return domain.Tag{}, err
 	}
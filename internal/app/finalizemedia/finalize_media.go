@@ -2,11 +2,17 @@ package finalizemedia
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+	"golang.org/x/sync/errgroup"
 )
 
+// DefaultBatchConcurrency is the default number of media verified in parallel by ExecuteBatch
+const DefaultBatchConcurrency = 8
+
 // MediaRepository defines the repository contract for finalizing media
 type MediaRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (domain.Media, error)
@@ -18,45 +24,107 @@ type MediaVerifier interface {
 	VerifyMediaExists(ctx context.Context, media domain.Media) (bool, error)
 }
 
+// Metrics defines the contract for recording the media lifecycle metrics
+type Metrics interface {
+	// MediaFinalized records a media finalized along with its size in bytes
+	MediaFinalized(mediaType domain.MediaType, size int64)
+	// FinalizeFailed records a media which could not be finalized
+	FinalizeFailed(reason domain.FinalizeFailureReason)
+}
+
 // UseCase handles finalizing media records after successful upload
 type UseCase struct {
-	mediaRepo MediaRepository
-	verifier  MediaVerifier
+	mediaRepo        MediaRepository
+	verifier         MediaVerifier
+	metrics          Metrics
+	batchConcurrency int
+}
+
+// Option configures the FinalizeMedia use case
+type Option func(*UseCase)
+
+// WithBatchConcurrency sets the number of media verified in parallel by
+// ExecuteBatch. Values lower than 1 are ignored.
+func WithBatchConcurrency(n int) Option {
+	return func(uc *UseCase) {
+		if n > 0 {
+			uc.batchConcurrency = n
+		}
+	}
+}
+
+// WithMetrics sets the recorder of the media lifecycle metrics, none are
+// recorded otherwise
+func WithMetrics(metrics Metrics) Option {
+	return func(uc *UseCase) {
+		uc.metrics = metrics
+	}
 }
 
 // New creates a new FinalizeMedia use case
-func New(mediaRepo MediaRepository, verifier MediaVerifier) *UseCase {
-	return &UseCase{
-		mediaRepo: mediaRepo,
-		verifier:  verifier,
+func New(mediaRepo MediaRepository, verifier MediaVerifier, opts ...Option) *UseCase {
+	uc := &UseCase{
+		mediaRepo:        mediaRepo,
+		verifier:         verifier,
+		metrics:          noMetrics{},
+		batchConcurrency: DefaultBatchConcurrency,
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
+type noMetrics struct{}
+
+func (noMetrics) MediaFinalized(domain.MediaType, int64)      {}
+func (noMetrics) FinalizeFailed(domain.FinalizeFailureReason) {}
+
 // Execute finalizes a media record after successful upload to file storage
-func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID) (domain.Media, error) {
+func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID) (_ domain.Media, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "finalizemedia.Execute")
+	defer telemetry.EndSpan(span, &err)
+
 	// Find media by ID
 	media, err := uc.mediaRepo.FindByID(ctx, id)
 	if err != nil {
+		if domain.HasCode(err, domain.NotFoundCode) {
+			uc.metrics.FinalizeFailed(domain.FinalizeFailureNotFound)
+		} else {
+			uc.metrics.FinalizeFailed(domain.FinalizeFailureInternal)
+		}
 		return domain.Media{}, domain.NewErrorFrom(err,
 			domain.WithDetails("error finding media"),
 		)
 	}
 
+	// Only the owner uploads the file of a media
+	if !media.OwnedBy(domain.SubjectFromContext(ctx)) {
+		uc.metrics.FinalizeFailed(domain.FinalizeFailureForbidden)
+		return domain.Media{}, domain.NewError(domain.ForbiddenCode,
+			domain.WithMessage("media owned by another client"),
+			domain.WithDetails("only the owner of a media can finalize it"),
+		)
+	}
+
 	// Check current status
 	switch media.Status {
 	case domain.MediaStatusReserved:
 		// OK - can finalize
 	case domain.MediaStatusFinalized:
+		uc.metrics.FinalizeFailed(domain.FinalizeFailureAlreadyFinalized)
 		return domain.Media{}, domain.NewError(domain.ConflictCode,
 			domain.WithMessage("media already finalized"),
 			domain.WithDetails("cannot finalize a media that is already finalized"),
 		)
 	case domain.MediaStatusFailed:
+		uc.metrics.FinalizeFailed(domain.FinalizeFailurePreviouslyFailed)
 		return domain.Media{}, domain.NewError(domain.ConflictCode,
 			domain.WithMessage("media upload failed"),
 			domain.WithDetails("cannot finalize a media that previously failed"),
 		)
 	default:
+		uc.metrics.FinalizeFailed(domain.FinalizeFailureInternal)
 		return domain.Media{}, domain.NewError(domain.InternalCode,
 			domain.WithMessage("unknown media status"),
 			domain.WithDetails("unexpected media status"),
@@ -66,6 +134,7 @@ func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID) (domain.Media, err
 	// Verify file exists in file storage
 	exists, err := uc.verifier.VerifyMediaExists(ctx, media)
 	if err != nil {
+		uc.metrics.FinalizeFailed(domain.FinalizeFailureStorage)
 		return domain.Media{}, domain.NewErrorFrom(err,
 			domain.WithDetails("error verifying media in file storage"),
 		)
@@ -75,10 +144,12 @@ func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID) (domain.Media, err
 		// Mark as failed if file doesn't exist
 		updatedMedia, err := uc.mediaRepo.UpdateStatus(ctx, media, domain.MediaStatusFailed)
 		if err != nil {
+			uc.metrics.FinalizeFailed(domain.FinalizeFailureInternal)
 			return domain.Media{}, domain.NewErrorFrom(err,
 				domain.WithDetails("error marking media as failed"),
 			)
 		}
+		uc.metrics.FinalizeFailed(domain.FinalizeFailureMissingFile)
 		return updatedMedia, domain.NewError(domain.InvalidEntityCode,
 			domain.WithMessage("media file not found in file storage"),
 			domain.WithDetails("upload was not completed or file was deleted"),
@@ -88,10 +159,61 @@ func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID) (domain.Media, err
 	// Update status to finalized
 	updatedMedia, err := uc.mediaRepo.UpdateStatus(ctx, media, domain.MediaStatusFinalized)
 	if err != nil {
+		uc.metrics.FinalizeFailed(domain.FinalizeFailureInternal)
 		return domain.Media{}, domain.NewErrorFrom(err,
 			domain.WithDetails("error finalizing media"),
 		)
 	}
+	uc.metrics.MediaFinalized(updatedMedia.Type, updatedMedia.Size)
 
 	return updatedMedia, nil
 }
+
+// ExecuteBatch finalizes several media, applying the semantics of Execute to
+// each of them with at most batchConcurrency verifications in flight.
+// Results are aligned with ids: a media whose file is missing is returned
+// marked as failed along with its error, like Execute does. The returned error
+// is only set when the batch itself is invalid.
+func (uc *UseCase) ExecuteBatch(ctx context.Context, ids []uuid.UUID) (_ []domain.MediaBatchResult, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "finalizemedia.ExecuteBatch")
+	defer telemetry.EndSpan(span, &err)
+
+	if len(ids) == 0 {
+		return nil, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid batch"),
+			domain.WithDetails("batch cannot be empty"),
+		)
+	}
+	if len(ids) > domain.MaxBatchSize {
+		return nil, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid batch"),
+			domain.WithDetails(fmt.Sprintf("batch cannot exceed %d items", domain.MaxBatchSize)),
+		)
+	}
+
+	results := make([]domain.MediaBatchResult, len(ids))
+
+	g := new(errgroup.Group)
+	g.SetLimit(uc.batchConcurrency)
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for i, id := range ids {
+		if seen[id] {
+			results[i].Err = domain.NewError(domain.ConflictCode,
+				domain.WithMessage("duplicate media in batch"),
+				domain.WithDetails("this media id appears earlier in the batch"),
+			)
+			continue
+		}
+		seen[id] = true
+
+		g.Go(func() error {
+			// Each goroutine writes its own slot, item errors are part of the results
+			results[i].Media, results[i].Err = uc.Execute(ctx, id)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results, nil
+}
@@ -2,9 +2,11 @@ package finalizemedia
 
 //go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/finalizemedia MediaRepository
 //go:generate mockgen -destination=mocks/mock_verifier.go -package=mocks github.com/peano88/medias/internal/app/finalizemedia MediaVerifier
+//go:generate mockgen -destination=mocks/mock_metrics.go -package=mocks github.com/peano88/medias/internal/app/finalizemedia Metrics
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -58,6 +60,25 @@ func TestUseCase_Execute(t *testing.T) {
 				assert.Equal(t, "world-cup-final.jpg", result.Filename)
 			},
 		},
+		{
+			name: "forbidden error - media owned by another client",
+			id:   uuid.MustParse("44444444-4444-4444-4444-444444444444"),
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier) {
+				repo.EXPECT().
+					FindByID(ctx, uuid.MustParse("44444444-4444-4444-4444-444444444444")).
+					Return(domain.Media{
+						ID:         uuid.MustParse("44444444-4444-4444-4444-444444444444"),
+						Filename:   "penalty-shootout.jpg",
+						Status:     domain.MediaStatusReserved,
+						Owner:      "user-42",
+						Visibility: domain.VisibilityShared,
+					}, nil)
+			},
+			validate: func(t *testing.T, result domain.Media, err error) {
+				assert.True(t, domain.HasCode(err, domain.ForbiddenCode))
+				assert.Equal(t, domain.Media{}, result)
+			},
+		},
 		{
 			name: "conflict error - media already finalized",
 			id:   uuid.MustParse("22222222-2222-2222-2222-222222222222"),
@@ -254,3 +275,274 @@ func TestUseCase_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestUseCase_Execute_Metrics(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	reserved := domain.Media{
+		ID:     id,
+		Status: domain.MediaStatusReserved,
+		Type:   domain.MediaTypeImage,
+		Size:   2048,
+	}
+
+	tests := []struct {
+		name       string
+		setupMocks func(*mocks.MockMediaRepository, *mocks.MockMediaVerifier, *mocks.MockMetrics)
+	}{
+		{
+			name: "finalized media and its size",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				repo.EXPECT().FindByID(ctx, id).Return(reserved, nil)
+				verifier.EXPECT().VerifyMediaExists(ctx, reserved).Return(true, nil)
+				finalized := reserved
+				finalized.Status = domain.MediaStatusFinalized
+				repo.EXPECT().UpdateStatus(ctx, reserved, domain.MediaStatusFinalized).Return(finalized, nil)
+				metrics.EXPECT().MediaFinalized(domain.MediaTypeImage, int64(2048))
+			},
+		},
+		{
+			name: "not found",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				repo.EXPECT().FindByID(ctx, id).Return(domain.Media{}, domain.NewError(domain.NotFoundCode))
+				metrics.EXPECT().FinalizeFailed(domain.FinalizeFailureNotFound)
+			},
+		},
+		{
+			name: "repository failure",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				repo.EXPECT().FindByID(ctx, id).Return(domain.Media{}, domain.NewError(domain.InternalCode))
+				metrics.EXPECT().FinalizeFailed(domain.FinalizeFailureInternal)
+			},
+		},
+		{
+			name: "owned by another client",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				owned := reserved
+				owned.Owner = "user-42"
+				repo.EXPECT().FindByID(ctx, id).Return(owned, nil)
+				metrics.EXPECT().FinalizeFailed(domain.FinalizeFailureForbidden)
+			},
+		},
+		{
+			name: "already finalized",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				finalized := reserved
+				finalized.Status = domain.MediaStatusFinalized
+				repo.EXPECT().FindByID(ctx, id).Return(finalized, nil)
+				metrics.EXPECT().FinalizeFailed(domain.FinalizeFailureAlreadyFinalized)
+			},
+		},
+		{
+			name: "previously failed",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				failed := reserved
+				failed.Status = domain.MediaStatusFailed
+				repo.EXPECT().FindByID(ctx, id).Return(failed, nil)
+				metrics.EXPECT().FinalizeFailed(domain.FinalizeFailurePreviouslyFailed)
+			},
+		},
+		{
+			name: "storage error",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				repo.EXPECT().FindByID(ctx, id).Return(reserved, nil)
+				verifier.EXPECT().VerifyMediaExists(ctx, reserved).Return(false, domain.NewError(domain.InternalCode))
+				metrics.EXPECT().FinalizeFailed(domain.FinalizeFailureStorage)
+			},
+		},
+		{
+			name: "missing file",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				repo.EXPECT().FindByID(ctx, id).Return(reserved, nil)
+				verifier.EXPECT().VerifyMediaExists(ctx, reserved).Return(false, nil)
+				failed := reserved
+				failed.Status = domain.MediaStatusFailed
+				repo.EXPECT().UpdateStatus(ctx, reserved, domain.MediaStatusFailed).Return(failed, nil)
+				metrics.EXPECT().FinalizeFailed(domain.FinalizeFailureMissingFile)
+			},
+		},
+		{
+			name: "update failure",
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier, metrics *mocks.MockMetrics) {
+				repo.EXPECT().FindByID(ctx, id).Return(reserved, nil)
+				verifier.EXPECT().VerifyMediaExists(ctx, reserved).Return(true, nil)
+				repo.EXPECT().
+					UpdateStatus(ctx, reserved, domain.MediaStatusFinalized).
+					Return(domain.Media{}, domain.NewError(domain.InternalCode))
+				metrics.EXPECT().FinalizeFailed(domain.FinalizeFailureInternal)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			verifier := mocks.NewMockMediaVerifier(ctrl)
+			metrics := mocks.NewMockMetrics(ctrl)
+			tt.setupMocks(repo, verifier, metrics)
+
+			uc := New(repo, verifier, WithMetrics(metrics))
+			_, _ = uc.Execute(ctx, id)
+		})
+	}
+}
+
+func TestUseCase_ExecuteBatch(t *testing.T) {
+	ctx := context.Background()
+
+	finalizedID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	missingFileID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	unknownID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	reserved := func(id uuid.UUID) domain.Media {
+		return domain.Media{ID: id, Filename: id.String() + ".jpg", Status: domain.MediaStatusReserved}
+	}
+
+	tests := []struct {
+		name        string
+		ids         []uuid.UUID
+		concurrency int
+		setupMocks  func(*mocks.MockMediaRepository, *mocks.MockMediaVerifier)
+		validate    func(*testing.T, []domain.MediaBatchResult, error)
+	}{
+		{
+			name:        "success - per id outcomes",
+			ids:         []uuid.UUID{finalizedID, missingFileID, unknownID, finalizedID},
+			concurrency: 2,
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier) {
+				repo.EXPECT().FindByID(ctx, finalizedID).Return(reserved(finalizedID), nil)
+				repo.EXPECT().FindByID(ctx, missingFileID).Return(reserved(missingFileID), nil)
+				repo.EXPECT().
+					FindByID(ctx, unknownID).
+					Return(domain.Media{}, domain.NewError(domain.NotFoundCode, domain.WithMessage("media not found")))
+
+				verifier.EXPECT().VerifyMediaExists(ctx, reserved(finalizedID)).Return(true, nil)
+				verifier.EXPECT().VerifyMediaExists(ctx, reserved(missingFileID)).Return(false, nil)
+
+				repo.EXPECT().
+					UpdateStatus(ctx, reserved(finalizedID), domain.MediaStatusFinalized).
+					DoAndReturn(func(_ context.Context, m domain.Media, s domain.MediaStatus) (domain.Media, error) {
+						m.Status = s
+						return m, nil
+					})
+				repo.EXPECT().
+					UpdateStatus(ctx, reserved(missingFileID), domain.MediaStatusFailed).
+					DoAndReturn(func(_ context.Context, m domain.Media, s domain.MediaStatus) (domain.Media, error) {
+						m.Status = s
+						return m, nil
+					})
+			},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.NoError(t, err)
+				if !assert.Len(t, results, 4) {
+					return
+				}
+
+				assert.NoError(t, results[0].Err)
+				assert.Equal(t, domain.MediaStatusFinalized, results[0].Media.Status)
+
+				assert.True(t, domain.HasCode(results[1].Err, domain.InvalidEntityCode))
+				assert.Equal(t, domain.MediaStatusFailed, results[1].Media.Status)
+
+				assert.True(t, domain.HasCode(results[2].Err, domain.NotFoundCode))
+				assert.Equal(t, uuid.Nil, results[2].Media.ID)
+
+				assert.True(t, domain.HasCode(results[3].Err, domain.ConflictCode))
+			},
+		},
+		{
+			name:       "error - empty batch",
+			ids:        []uuid.UUID{},
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier) {},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.Nil(t, results)
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:       "error - batch too large",
+			ids:        make([]uuid.UUID, domain.MaxBatchSize+1),
+			setupMocks: func(repo *mocks.MockMediaRepository, verifier *mocks.MockMediaVerifier) {},
+			validate: func(t *testing.T, results []domain.MediaBatchResult, err error) {
+				assert.Nil(t, results)
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			verifier := mocks.NewMockMediaVerifier(ctrl)
+			tt.setupMocks(repo, verifier)
+
+			uc := New(repo, verifier, WithBatchConcurrency(tt.concurrency))
+			results, err := uc.ExecuteBatch(ctx, tt.ids)
+
+			tt.validate(t, results, err)
+		})
+	}
+}
+
+func TestUseCase_ExecuteBatch_BoundedConcurrency(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockMediaRepository(ctrl)
+	verifier := mocks.NewMockMediaVerifier(ctrl)
+
+	const concurrency = 3
+	var inFlight, maxInFlight atomic.Int32
+
+	repo.EXPECT().
+		FindByID(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, id uuid.UUID) (domain.Media, error) {
+			return domain.Media{ID: id, Status: domain.MediaStatusReserved}, nil
+		}).
+		AnyTimes()
+	verifier.EXPECT().
+		VerifyMediaExists(ctx, gomock.Any()).
+		DoAndReturn(func(context.Context, domain.Media) (bool, error) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				observed := maxInFlight.Load()
+				if current <= observed || maxInFlight.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return true, nil
+		}).
+		AnyTimes()
+	repo.EXPECT().
+		UpdateStatus(ctx, gomock.Any(), domain.MediaStatusFinalized).
+		DoAndReturn(func(_ context.Context, m domain.Media, s domain.MediaStatus) (domain.Media, error) {
+			m.Status = s
+			return m, nil
+		}).
+		AnyTimes()
+
+	ids := make([]uuid.UUID, 20)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	uc := New(repo, verifier, WithBatchConcurrency(concurrency))
+	results, err := uc.ExecuteBatch(ctx, ids)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, len(ids))
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+	}
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(concurrency))
+	assert.Greater(t, maxInFlight.Load(), int32(1))
+}
@@ -2,9 +2,11 @@ package getmedia
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
 )
 
 // MediaRepository defines the repository contract for getting media
@@ -15,12 +17,15 @@ type MediaRepository interface {
 // URLGenerator defines the contract for generating download URLs
 type URLGenerator interface {
 	GenerateDownloadURL(ctx context.Context, media domain.Media) (string, error)
+	// DownloadURLExpiry is the validity of the generated download URLs
+	DownloadURLExpiry() time.Duration
 }
 
 // UseCase handles retrieving media records by ID
 type UseCase struct {
 	mediaRepo    MediaRepository
 	urlGenerator URLGenerator
+	now          func() time.Time
 }
 
 // New creates a new GetMedia use case
@@ -28,26 +33,58 @@ func New(mediaRepo MediaRepository, urlGenerator URLGenerator) *UseCase {
 	return &UseCase{
 		mediaRepo:    mediaRepo,
 		urlGenerator: urlGenerator,
+		now:          time.Now,
 	}
 }
 
-// Execute retrieves a media record by ID and generates a download URL
-func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID) (domain.Media, error) {
-	media, err := uc.mediaRepo.FindByID(ctx, id)
+// Execute retrieves a media record by ID and generates a download URL.
+// When cached is given and still current, i.e. the metadata did not change
+// since and its URL is valid for at least domain.MinDownloadURLLifetime, no
+// URL is generated: the returned media carries the validity of the cached URL
+// and notModified is true.
+func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID, cached *domain.CachedMedia) (media domain.Media, notModified bool, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "getmedia.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	media, err = uc.mediaRepo.FindByID(ctx, id)
 	if err != nil {
-		return domain.Media{}, domain.NewErrorFrom(err,
+		return domain.Media{}, false, domain.NewErrorFrom(err,
 			domain.WithDetails("error finding media"),
 		)
 	}
 
+	now := uc.now()
+	expiry := uc.urlGenerator.DownloadURLExpiry()
+
+	if cached != nil && isCurrent(media, *cached, now, expiry) {
+		media.URLIssuedAt = cached.URLIssuedAt
+		media.URLExpiresAt = cached.URLIssuedAt.Add(expiry)
+		return media, true, nil
+	}
+
 	// Generate presigned download URL
 	downloadURL, err := uc.urlGenerator.GenerateDownloadURL(ctx, media)
 	if err != nil {
-		return domain.Media{}, domain.NewErrorFrom(err,
+		return domain.Media{}, false, domain.NewErrorFrom(err,
 			domain.WithDetails("error generating download URL"),
 		)
 	}
 
 	media.URL = downloadURL
-	return media, nil
+	media.URLIssuedAt = now
+	media.URLExpiresAt = now.Add(expiry)
+	return media, false, nil
+}
+
+func isCurrent(media domain.Media, cached domain.CachedMedia, now time.Time, expiry time.Duration) bool {
+	if media.UpdatedAt.After(cached.UpdatedAt) {
+		return false
+	}
+
+	// a URL issued in the future was not issued by us
+	if cached.URLIssuedAt.After(now) {
+		return false
+	}
+
+	return cached.URLIssuedAt.Add(expiry).Sub(now) >= domain.MinDownloadURLLifetime
 }
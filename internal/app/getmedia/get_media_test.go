@@ -17,12 +17,28 @@ import (
 
 func TestUseCase_Execute(t *testing.T) {
 	ctx := context.Background()
+	now := time.Date(2024, 1, 16, 10, 0, 0, 0, time.UTC)
+	expiry := time.Hour
+
+	cachedMedia := domain.Media{
+		ID:        uuid.MustParse("44444444-4444-4444-4444-444444444444"),
+		Filename:  "hockey-goal.jpg",
+		Status:    domain.MediaStatusFinalized,
+		Type:      domain.MediaTypeImage,
+		MimeType:  "image/jpeg",
+		Size:      1024,
+		SHA256:    "h0ck3y",
+		Tags:      []domain.Tag{},
+		CreatedAt: time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC),
+	}
 
 	tests := []struct {
 		name       string
 		id         uuid.UUID
+		cached     *domain.CachedMedia
 		setupMocks func(*mocks.MockMediaRepository, *mocks.MockURLGenerator)
-		validate   func(*testing.T, domain.Media, error)
+		validate   func(*testing.T, domain.Media, bool, error)
 	}{
 		{
 			name: "success - returns media with download URL",
@@ -45,12 +61,16 @@ func TestUseCase_Execute(t *testing.T) {
 					FindByID(ctx, uuid.MustParse("11111111-1111-1111-1111-111111111111")).
 					Return(media, nil)
 
+				urlGen.EXPECT().DownloadURLExpiry().Return(expiry)
 				urlGen.EXPECT().
 					GenerateDownloadURL(ctx, media).
 					Return("https://s3.example.com/bucket/w0rldcup2023/world-cup-final.jpg?X-Amz-Signature=...", nil)
 			},
-			validate: func(t *testing.T, result domain.Media, err error) {
+			validate: func(t *testing.T, result domain.Media, notModified bool, err error) {
 				assert.NoError(t, err)
+				assert.False(t, notModified)
+				assert.Equal(t, now, result.URLIssuedAt)
+				assert.Equal(t, now.Add(expiry), result.URLExpiresAt)
 				assert.Equal(t, uuid.MustParse("11111111-1111-1111-1111-111111111111"), result.ID)
 				assert.Equal(t, "world-cup-final.jpg", result.Filename)
 				assert.Equal(t, domain.MediaStatusFinalized, result.Status)
@@ -68,7 +88,7 @@ func TestUseCase_Execute(t *testing.T) {
 						domain.WithMessage("media not found"),
 					))
 			},
-			validate: func(t *testing.T, result domain.Media, err error) {
+			validate: func(t *testing.T, result domain.Media, notModified bool, err error) {
 				assert.Error(t, err)
 				var domainErr *domain.Error
 				if assert.ErrorAs(t, err, &domainErr) {
@@ -95,13 +115,14 @@ func TestUseCase_Execute(t *testing.T) {
 					FindByID(ctx, uuid.MustParse("33333333-3333-3333-3333-333333333333")).
 					Return(media, nil)
 
+				urlGen.EXPECT().DownloadURLExpiry().Return(expiry)
 				urlGen.EXPECT().
 					GenerateDownloadURL(ctx, media).
 					Return("", domain.NewError(domain.InternalCode,
 						domain.WithMessage("S3 service unavailable"),
 					))
 			},
-			validate: func(t *testing.T, result domain.Media, err error) {
+			validate: func(t *testing.T, result domain.Media, notModified bool, err error) {
 				assert.Error(t, err)
 				var domainErr *domain.Error
 				if assert.ErrorAs(t, err, &domainErr) {
@@ -110,6 +131,79 @@ func TestUseCase_Execute(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "not modified - cached representation is current",
+			id:   cachedMedia.ID,
+			cached: &domain.CachedMedia{
+				UpdatedAt:   cachedMedia.UpdatedAt,
+				URLIssuedAt: now.Add(-10 * time.Minute),
+			},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().FindByID(ctx, cachedMedia.ID).Return(cachedMedia, nil)
+				urlGen.EXPECT().DownloadURLExpiry().Return(expiry)
+			},
+			validate: func(t *testing.T, result domain.Media, notModified bool, err error) {
+				assert.NoError(t, err)
+				assert.True(t, notModified)
+				assert.Empty(t, result.URL)
+				assert.Equal(t, now.Add(-10*time.Minute), result.URLIssuedAt)
+				assert.Equal(t, now.Add(50*time.Minute), result.URLExpiresAt)
+			},
+		},
+		{
+			name: "modified - metadata updated since",
+			id:   cachedMedia.ID,
+			cached: &domain.CachedMedia{
+				UpdatedAt:   cachedMedia.UpdatedAt.Add(-time.Microsecond),
+				URLIssuedAt: now.Add(-10 * time.Minute),
+			},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().FindByID(ctx, cachedMedia.ID).Return(cachedMedia, nil)
+				urlGen.EXPECT().DownloadURLExpiry().Return(expiry)
+				urlGen.EXPECT().GenerateDownloadURL(ctx, cachedMedia).Return("https://s3.example.com/fresh", nil)
+			},
+			validate: func(t *testing.T, result domain.Media, notModified bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, notModified)
+				assert.Equal(t, "https://s3.example.com/fresh", result.URL)
+				assert.Equal(t, now, result.URLIssuedAt)
+			},
+		},
+		{
+			name: "modified - cached URL about to expire",
+			id:   cachedMedia.ID,
+			cached: &domain.CachedMedia{
+				UpdatedAt:   cachedMedia.UpdatedAt,
+				URLIssuedAt: now.Add(-expiry + domain.MinDownloadURLLifetime - time.Second),
+			},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().FindByID(ctx, cachedMedia.ID).Return(cachedMedia, nil)
+				urlGen.EXPECT().DownloadURLExpiry().Return(expiry)
+				urlGen.EXPECT().GenerateDownloadURL(ctx, cachedMedia).Return("https://s3.example.com/fresh", nil)
+			},
+			validate: func(t *testing.T, result domain.Media, notModified bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, notModified)
+				assert.Equal(t, "https://s3.example.com/fresh", result.URL)
+			},
+		},
+		{
+			name: "modified - cached URL issued in the future",
+			id:   cachedMedia.ID,
+			cached: &domain.CachedMedia{
+				UpdatedAt:   cachedMedia.UpdatedAt,
+				URLIssuedAt: now.Add(time.Minute),
+			},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().FindByID(ctx, cachedMedia.ID).Return(cachedMedia, nil)
+				urlGen.EXPECT().DownloadURLExpiry().Return(expiry)
+				urlGen.EXPECT().GenerateDownloadURL(ctx, cachedMedia).Return("https://s3.example.com/fresh", nil)
+			},
+			validate: func(t *testing.T, result domain.Media, notModified bool, err error) {
+				assert.NoError(t, err)
+				assert.False(t, notModified)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -122,9 +216,10 @@ func TestUseCase_Execute(t *testing.T) {
 			tt.setupMocks(repo, urlGen)
 
 			uc := New(repo, urlGen)
-			result, err := uc.Execute(ctx, tt.id)
+			uc.now = func() time.Time { return now }
+			result, notModified, err := uc.Execute(ctx, tt.id, tt.cached)
 
-			tt.validate(t, result, err)
+			tt.validate(t, result, notModified, err)
 		})
 	}
 }
@@ -0,0 +1,64 @@
+package getrelatedtags
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// TagRepository defines the repository contract for retrieving related tags
+type TagRepository interface {
+	FindRelatedTags(ctx context.Context, name string, limit int) ([]domain.RelatedTag, error)
+}
+
+// UseCase handles retrieving the tags co-occurring with a given tag
+type UseCase struct {
+	repo TagRepository
+}
+
+// New creates a new GetRelatedTags use case
+func New(repo TagRepository) *UseCase {
+	return &UseCase{
+		repo: repo,
+	}
+}
+
+// Execute retrieves the tags most often found on the same media as the named tag
+func (uc *UseCase) Execute(ctx context.Context, name string, limit int) (_ []domain.RelatedTag, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "getrelatedtags.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid name"),
+			domain.WithDetails("name is mandatory"),
+		)
+	}
+
+	if limit < 0 || limit > MaxLimit {
+		return nil, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid limit"),
+			domain.WithDetails(fmt.Sprintf("limit must be between 0 and %d", MaxLimit)),
+		)
+	}
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+
+	related, err := uc.repo.FindRelatedTags(ctx, name, limit)
+	if err != nil {
+		return nil, domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error retrieving related tags: %s", err)))
+	}
+
+	return related, nil
+}
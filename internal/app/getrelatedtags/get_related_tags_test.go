@@ -0,0 +1,110 @@
+package getrelatedtags
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/getrelatedtags TagRepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/getrelatedtags/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		tagName   string
+		limit     int
+		setupMock func(*mocks.MockTagRepository)
+		validate  func(*testing.T, []domain.RelatedTag, error)
+	}{
+		{
+			name:    "success - normalizes name and applies default limit",
+			tagName: "  Soccer ",
+			setupMock: func(repo *mocks.MockTagRepository) {
+				repo.EXPECT().
+					FindRelatedTags(ctx, "soccer", DefaultLimit).
+					Return([]domain.RelatedTag{
+						{Tag: domain.Tag{ID: uuid.New(), Name: "football"}, Cooccurrences: 3, Lift: 1.5},
+					}, nil)
+			},
+			validate: func(t *testing.T, related []domain.RelatedTag, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, related, 1)
+				assert.Equal(t, "football", related[0].Tag.Name)
+			},
+		},
+		{
+			name:    "success - custom limit",
+			tagName: "soccer",
+			limit:   3,
+			setupMock: func(repo *mocks.MockTagRepository) {
+				repo.EXPECT().
+					FindRelatedTags(ctx, "soccer", 3).
+					Return([]domain.RelatedTag{}, nil)
+			},
+			validate: func(t *testing.T, related []domain.RelatedTag, err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, related)
+			},
+		},
+		{
+			name:      "error - empty name",
+			tagName:   "   ",
+			setupMock: func(repo *mocks.MockTagRepository) {},
+			validate: func(t *testing.T, related []domain.RelatedTag, err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+				}
+			},
+		},
+		{
+			name:      "error - limit too high",
+			tagName:   "soccer",
+			limit:     MaxLimit + 1,
+			setupMock: func(repo *mocks.MockTagRepository) {},
+			validate: func(t *testing.T, related []domain.RelatedTag, err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+				}
+			},
+		},
+		{
+			name:    "error - tag not found",
+			tagName: "curling",
+			setupMock: func(repo *mocks.MockTagRepository) {
+				repo.EXPECT().
+					FindRelatedTags(ctx, "curling", DefaultLimit).
+					Return(nil, domain.NewError(domain.NotFoundCode, domain.WithMessage("tag not found")))
+			},
+			validate: func(t *testing.T, related []domain.RelatedTag, err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.NotFoundCode, domainErr.Code)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockTagRepository(ctrl)
+			tt.setupMock(repo)
+
+			uc := New(repo)
+			related, err := uc.Execute(ctx, tt.tagName, tt.limit)
+
+			tt.validate(t, related, err)
+		})
+	}
+}
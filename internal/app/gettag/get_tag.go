@@ -0,0 +1,39 @@
+package gettag
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// TagRepository defines the repository contract for getting a tag
+type TagRepository interface {
+	FindTagByID(ctx context.Context, id uuid.UUID) (domain.Tag, error)
+}
+
+// UseCase handles retrieving tags by ID
+type UseCase struct {
+	repo TagRepository
+}
+
+// New creates a new GetTag use case
+func New(repo TagRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute retrieves a tag by ID
+func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID) (_ domain.Tag, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "gettag.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	tag, err := uc.repo.FindTagByID(ctx, id)
+	if err != nil {
+		return domain.Tag{}, domain.NewErrorFrom(err,
+			domain.WithDetails("error finding tag"),
+		)
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,75 @@
+package gettag
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/gettag TagRepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/gettag/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tagID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.MockTagRepository)
+		validate  func(*testing.T, domain.Tag, error)
+	}{
+		{
+			name: "success",
+			setupMock: func(repo *mocks.MockTagRepository) {
+				repo.EXPECT().
+					FindTagByID(ctx, tagID).
+					Return(domain.Tag{
+						ID:        tagID,
+						Name:      "soccer",
+						CreatedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+						UpdatedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+					}, nil)
+			},
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, tagID, tag.ID)
+				assert.Equal(t, "soccer", tag.Name)
+			},
+		},
+		{
+			name: "not found",
+			setupMock: func(repo *mocks.MockTagRepository) {
+				repo.EXPECT().
+					FindTagByID(ctx, tagID).
+					Return(domain.Tag{}, domain.NewError(domain.NotFoundCode, domain.WithMessage("tag not found")))
+			},
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.NotFoundCode, domainErr.Code)
+					assert.Equal(t, "error finding tag", domainErr.Details)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockTagRepository(ctrl)
+			tt.setupMock(repo)
+
+			uc := New(repo)
+			tag, err := uc.Execute(ctx, tagID)
+
+			tt.validate(t, tag, err)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
 )
 
 // TagRepository defines the repository contract for retrieving tags
@@ -25,9 +26,12 @@ func New(repo TagRepository) *UseCase {
 }
 
 // Execute retrieves paginated tags from the repository
-func (uc *UseCase) Execute(ctx context.Context, params domain.PaginationParams) (*domain.PaginatedResult[domain.Tag], error) {
+func (uc *UseCase) Execute(ctx context.Context, params domain.PaginationParams) (_ *domain.PaginatedResult[domain.Tag], err error) {
+	ctx, span := telemetry.StartSpan(ctx, "gettags.Execute")
+	defer telemetry.EndSpan(span, &err)
+
 	// Validate and apply defaults
-	if err := validatePaginationParams(&params); err != nil {
+	if err := domain.ValidatePaginationParams(&params); err != nil {
 		return nil, err
 	}
 
@@ -44,36 +48,3 @@ func (uc *UseCase) Execute(ctx context.Context, params domain.PaginationParams)
 		Offset: params.Offset,
 	}, nil
 }
-
-func validatePaginationParams(params *domain.PaginationParams) error {
-	// Validate offset
-	if params.Offset < 0 {
-		return domain.NewError(domain.InvalidEntityCode,
-			domain.WithMessage("invalid pagination parameters"),
-			domain.WithDetails("offset cannot be negative"),
-		)
-	}
-
-	// Validate limit
-	if params.Limit < 0 {
-		return domain.NewError(domain.InvalidEntityCode,
-			domain.WithMessage("invalid pagination parameters"),
-			domain.WithDetails("limit cannot be negative"),
-		)
-	}
-
-	// Apply default limit if not provided
-	if params.Limit == 0 {
-		params.Limit = domain.DefaultLimit
-	}
-
-	// Validate max limit
-	if params.Limit > domain.MaxLimit {
-		return domain.NewError(domain.InvalidEntityCode,
-			domain.WithMessage("invalid pagination parameters"),
-			domain.WithDetails(fmt.Sprintf("limit cannot exceed %d", domain.MaxLimit)),
-		)
-	}
-
-	return nil
-}
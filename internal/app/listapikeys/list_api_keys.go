@@ -0,0 +1,47 @@
+package listapikeys
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// APIKeyRepository defines the repository contract for listing API keys
+type APIKeyRepository interface {
+	FindAllAPIKeys(ctx context.Context, params domain.PaginationParams) ([]domain.APIKey, int, error)
+}
+
+// UseCase handles listing the API keys
+type UseCase struct {
+	repo APIKeyRepository
+}
+
+// New creates a new ListAPIKeys use case
+func New(repo APIKeyRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute retrieves paginated API keys, revoked ones included
+func (uc *UseCase) Execute(ctx context.Context, params domain.PaginationParams) (_ *domain.PaginatedResult[domain.APIKey], err error) {
+	ctx, span := telemetry.StartSpan(ctx, "listapikeys.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	if err := domain.ValidatePaginationParams(&params); err != nil {
+		return nil, err
+	}
+
+	keys, total, err := uc.repo.FindAllAPIKeys(ctx, params)
+	if err != nil {
+		return nil, domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error retrieving API keys: %s", err)))
+	}
+
+	return &domain.PaginatedResult[domain.APIKey]{
+		Items:  keys,
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}, nil
+}
@@ -0,0 +1,75 @@
+package listapikeys
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/listapikeys APIKeyRepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/app/listapikeys/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		params    domain.PaginationParams
+		setupMock func(*mocks.MockAPIKeyRepository)
+		validate  func(*testing.T, *domain.PaginatedResult[domain.APIKey], error)
+	}{
+		{
+			name:   "success with default pagination",
+			params: domain.PaginationParams{},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					FindAllAPIKeys(ctx, domain.PaginationParams{Limit: 50}).
+					Return([]domain.APIKey{{Name: "reader"}}, 1, nil)
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.APIKey], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, result.Total)
+				assert.Equal(t, 50, result.Limit)
+				assert.Len(t, result.Items, 1)
+			},
+		},
+		{
+			name:      "error - negative offset",
+			params:    domain.PaginationParams{Offset: -1},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.APIKey], err error) {
+				assert.Nil(t, result)
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:   "error - repository failure",
+			params: domain.PaginationParams{Limit: 10},
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().
+					FindAllAPIKeys(ctx, domain.PaginationParams{Limit: 10}).
+					Return(nil, 0, domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.APIKey], err error) {
+				assert.Nil(t, result)
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.setupMock(repo)
+
+			result, err := New(repo).Execute(ctx, tt.params)
+			tt.validate(t, result, err)
+		})
+	}
+}
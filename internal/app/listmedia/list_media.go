@@ -0,0 +1,71 @@
+package listmedia
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// MediaRepository defines the repository contract for listing media
+type MediaRepository interface {
+	FindAllMedia(ctx context.Context, filter domain.MediaFilter, params domain.PaginationParams) ([]domain.Media, int, error)
+}
+
+// URLGenerator defines the contract for generating download URLs
+type URLGenerator interface {
+	GenerateDownloadURL(ctx context.Context, media domain.Media) (string, error)
+}
+
+// UseCase handles listing media records
+type UseCase struct {
+	mediaRepo    MediaRepository
+	urlGenerator URLGenerator
+}
+
+// New creates a new ListMedia use case
+func New(mediaRepo MediaRepository, urlGenerator URLGenerator) *UseCase {
+	return &UseCase{
+		mediaRepo:    mediaRepo,
+		urlGenerator: urlGenerator,
+	}
+}
+
+// Execute retrieves paginated media matching the filter and generates a
+// download URL for each of them
+func (uc *UseCase) Execute(ctx context.Context, filter domain.MediaFilter, params domain.PaginationParams) (_ *domain.PaginatedResult[domain.Media], err error) {
+	ctx, span := telemetry.StartSpan(ctx, "listmedia.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	if err := domain.ValidateMediaFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidatePaginationParams(&params); err != nil {
+		return nil, err
+	}
+
+	medias, total, err := uc.mediaRepo.FindAllMedia(ctx, filter, params)
+	if err != nil {
+		return nil, domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error retrieving media: %s", err)))
+	}
+
+	for i := range medias {
+		downloadURL, err := uc.urlGenerator.GenerateDownloadURL(ctx, medias[i])
+		if err != nil {
+			return nil, domain.NewErrorFrom(err,
+				domain.WithDetails("error generating download URL"),
+			)
+		}
+		medias[i].URL = downloadURL
+	}
+
+	return &domain.PaginatedResult[domain.Media]{
+		Items:  medias,
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}, nil
+}
@@ -0,0 +1,133 @@
+package listmedia
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/listmedia MediaRepository
+//go:generate mockgen -destination=mocks/mock_url_generator.go -package=mocks github.com/peano88/medias/internal/app/listmedia URLGenerator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/listmedia/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	media := domain.Media{
+		ID:       uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Filename: "beach-sunset.jpg",
+		Status:   domain.MediaStatusFinalized,
+		Type:     domain.MediaTypeImage,
+		MimeType: "image/jpeg",
+		Size:     2048000,
+		SHA256:   "b34chsuns3t",
+		Tags:     []domain.Tag{},
+	}
+	tagsQuery := domain.TagExprAnd{
+		Left:  domain.TagExprName{Name: "beach"},
+		Right: domain.TagExprNot{Expr: domain.TagExprName{Name: "night"}},
+	}
+
+	tests := []struct {
+		name       string
+		filter     domain.MediaFilter
+		params     domain.PaginationParams
+		setupMocks func(*mocks.MockMediaRepository, *mocks.MockURLGenerator)
+		validate   func(*testing.T, *domain.PaginatedResult[domain.Media], error)
+	}{
+		{
+			name:   "success - forwards tags query and generates URLs",
+			filter: domain.MediaFilter{TagsQuery: tagsQuery},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					FindAllMedia(ctx, domain.MediaFilter{TagsQuery: tagsQuery}, domain.PaginationParams{Limit: domain.DefaultLimit}).
+					Return([]domain.Media{media}, 1, nil)
+				urlGen.EXPECT().
+					GenerateDownloadURL(ctx, media).
+					Return("https://s3.example.com/bucket/b34chsuns3t/beach-sunset.jpg?X-Amz-Signature=...", nil)
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				assert.NoError(t, err)
+				assert.Len(t, result.Items, 1)
+				assert.Contains(t, result.Items[0].URL, "X-Amz-Signature")
+				assert.Equal(t, 1, result.Total)
+				assert.Equal(t, domain.DefaultLimit, result.Limit)
+			},
+		},
+		{
+			name:       "error - invalid filter",
+			filter:     domain.MediaFilter{Status: "deleted"},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				assert.Nil(t, result)
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+				}
+			},
+		},
+		{
+			name:       "error - invalid pagination",
+			params:     domain.PaginationParams{Offset: -1},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+				}
+			},
+		},
+		{
+			name: "error - repository failure",
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					FindAllMedia(ctx, domain.MediaFilter{}, domain.PaginationParams{Limit: domain.DefaultLimit}).
+					Return(nil, 0, domain.NewError(domain.InternalCode, domain.WithMessage("failed to retrieve media")))
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InternalCode, domainErr.Code)
+				}
+			},
+		},
+		{
+			name: "error - URL generation fails",
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					FindAllMedia(ctx, domain.MediaFilter{}, domain.PaginationParams{Limit: domain.DefaultLimit}).
+					Return([]domain.Media{media}, 1, nil)
+				urlGen.EXPECT().
+					GenerateDownloadURL(ctx, media).
+					Return("", domain.NewError(domain.InternalCode, domain.WithMessage("S3 service unavailable")))
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InternalCode, domainErr.Code)
+					assert.Contains(t, domainErr.Details, "error generating download URL")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			urlGen := mocks.NewMockURLGenerator(ctrl)
+			tt.setupMocks(repo, urlGen)
+
+			uc := New(repo, urlGen)
+			result, err := uc.Execute(ctx, tt.filter, tt.params)
+
+			tt.validate(t, result, err)
+		})
+	}
+}
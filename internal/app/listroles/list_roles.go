@@ -0,0 +1,53 @@
+package listroles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// RoleRepository defines the repository contract for listing role assignments
+type RoleRepository interface {
+	FindAllRoleAssignments(ctx context.Context, tenant string, params domain.PaginationParams) ([]domain.RoleAssignment, int, error)
+}
+
+// UseCase handles listing the role assignments
+type UseCase struct {
+	repo RoleRepository
+}
+
+// New creates a new ListRoles use case
+func New(repo RoleRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute retrieves paginated role assignments. A principal which was not
+// granted domain.ScopeAllTenants only retrieves the assignments in its tenant.
+func (uc *UseCase) Execute(ctx context.Context, params domain.PaginationParams) (_ *domain.PaginatedResult[domain.RoleAssignment], err error) {
+	ctx, span := telemetry.StartSpan(ctx, "listroles.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	if err := domain.ValidatePaginationParams(&params); err != nil {
+		return nil, err
+	}
+
+	var tenant string
+	if principal, ok := domain.PrincipalFromContext(ctx); ok && !principal.HasScope(domain.ScopeAllTenants) {
+		tenant = principal.Tenant
+	}
+
+	assignments, total, err := uc.repo.FindAllRoleAssignments(ctx, tenant, params)
+	if err != nil {
+		return nil, domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error retrieving role assignments: %s", err)))
+	}
+
+	return &domain.PaginatedResult[domain.RoleAssignment]{
+		Items:  assignments,
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package listroles
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/listroles RoleRepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/app/listroles/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	acmeCtx := domain.ContextWithPrincipal(context.Background(), domain.Principal{Subject: "acme-admin", Tenant: "acme"})
+	operatorCtx := domain.ContextWithPrincipal(context.Background(), domain.Principal{
+		Subject: "operator",
+		Scopes:  []domain.Scope{domain.ScopeAdmin, domain.ScopeAllTenants},
+		Tenant:  domain.DefaultTenant,
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		params    domain.PaginationParams
+		setupMock func(*mocks.MockRoleRepository)
+		validate  func(*testing.T, *domain.PaginatedResult[domain.RoleAssignment], error)
+	}{
+		{
+			name:   "success with default pagination",
+			ctx:    context.Background(),
+			params: domain.PaginationParams{},
+			setupMock: func(repo *mocks.MockRoleRepository) {
+				repo.EXPECT().
+					FindAllRoleAssignments(gomock.Any(), "", domain.PaginationParams{Limit: 50}).
+					Return([]domain.RoleAssignment{{Subject: "user-42", Role: domain.RoleEditor}}, 1, nil)
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.RoleAssignment], err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, result.Total)
+				assert.Equal(t, 50, result.Limit)
+				assert.Len(t, result.Items, 1)
+			},
+		},
+		{
+			name:   "success - principal bound to a tenant",
+			ctx:    acmeCtx,
+			params: domain.PaginationParams{Limit: 10},
+			setupMock: func(repo *mocks.MockRoleRepository) {
+				repo.EXPECT().
+					FindAllRoleAssignments(gomock.Any(), "acme", domain.PaginationParams{Limit: 10}).
+					Return([]domain.RoleAssignment{}, 0, nil)
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.RoleAssignment], err error) {
+				assert.NoError(t, err)
+				assert.Empty(t, result.Items)
+			},
+		},
+		{
+			name:   "success - principal granted every tenant",
+			ctx:    operatorCtx,
+			params: domain.PaginationParams{Limit: 10},
+			setupMock: func(repo *mocks.MockRoleRepository) {
+				repo.EXPECT().
+					FindAllRoleAssignments(gomock.Any(), "", domain.PaginationParams{Limit: 10}).
+					Return([]domain.RoleAssignment{}, 0, nil)
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.RoleAssignment], err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:      "error - negative offset",
+			ctx:       context.Background(),
+			params:    domain.PaginationParams{Offset: -1},
+			setupMock: func(repo *mocks.MockRoleRepository) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.RoleAssignment], err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:   "error - repository failure",
+			ctx:    context.Background(),
+			params: domain.PaginationParams{},
+			setupMock: func(repo *mocks.MockRoleRepository) {
+				repo.EXPECT().FindAllRoleAssignments(gomock.Any(), "", gomock.Any()).
+					Return(nil, 0, domain.NewError(domain.InternalCode, domain.WithMessage("db down")))
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.RoleAssignment], err error) {
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockRoleRepository(ctrl)
+			tt.setupMock(repo)
+
+			result, err := New(repo).Execute(tt.ctx, tt.params)
+			tt.validate(t, result, err)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package revokeapikey
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// APIKeyRepository defines the repository contract for revoking API keys
+type APIKeyRepository interface {
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) (domain.APIKey, error)
+}
+
+// UseCase handles the revocation of API keys
+type UseCase struct {
+	repo APIKeyRepository
+}
+
+// New creates a new RevokeAPIKey use case
+func New(repo APIKeyRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute revokes the API key, which is rejected from then on. Revoking an
+// already revoked key succeeds.
+func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID) (_ domain.APIKey, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "revokeapikey.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	key, err := uc.repo.RevokeAPIKey(ctx, id)
+	if err != nil {
+		return domain.APIKey{}, domain.NewErrorFrom(err,
+			domain.WithDetails("error revoking API key"),
+		)
+	}
+
+	return key, nil
+}
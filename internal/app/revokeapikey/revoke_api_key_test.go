@@ -0,0 +1,65 @@
+package revokeapikey
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/revokeapikey APIKeyRepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/revokeapikey/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	revokedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.MockAPIKeyRepository)
+		validate  func(*testing.T, domain.APIKey, error)
+	}{
+		{
+			name: "success",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().RevokeAPIKey(ctx, id).Return(domain.APIKey{ID: id, RevokedAt: &revokedAt}, nil)
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				assert.NoError(t, err)
+				assert.True(t, key.Revoked())
+			},
+		},
+		{
+			name: "error - not found",
+			setupMock: func(repo *mocks.MockAPIKeyRepository) {
+				repo.EXPECT().RevokeAPIKey(ctx, id).
+					Return(domain.APIKey{}, domain.NewError(domain.NotFoundCode, domain.WithMessage("API key not found")))
+			},
+			validate: func(t *testing.T, key domain.APIKey, err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.NotFoundCode, domainErr.Code)
+					assert.Contains(t, domainErr.Details, "error revoking API key")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.setupMock(repo)
+
+			key, err := New(repo).Execute(ctx, id)
+			tt.validate(t, key, err)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package searchmedia
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+const maxQueryLength = 255
+
+// MediaRepository defines the repository contract for searching media
+type MediaRepository interface {
+	SearchMedia(ctx context.Context, query string, filter domain.MediaFilter, params domain.PaginationParams) ([]domain.Media, int, error)
+}
+
+// URLGenerator defines the contract for generating download URLs
+type URLGenerator interface {
+	GenerateDownloadURL(ctx context.Context, media domain.Media) (string, error)
+}
+
+// UseCase handles full-text search of media records
+type UseCase struct {
+	mediaRepo    MediaRepository
+	urlGenerator URLGenerator
+}
+
+// New creates a new SearchMedia use case
+func New(mediaRepo MediaRepository, urlGenerator URLGenerator) *UseCase {
+	return &UseCase{
+		mediaRepo:    mediaRepo,
+		urlGenerator: urlGenerator,
+	}
+}
+
+// Execute searches media matching the query and the filter, ranked by relevance,
+// and generates a download URL for each of them
+func (uc *UseCase) Execute(ctx context.Context, query string, filter domain.MediaFilter, params domain.PaginationParams) (_ *domain.PaginatedResult[domain.Media], err error) {
+	ctx, span := telemetry.StartSpan(ctx, "searchmedia.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	query = strings.TrimSpace(query)
+	if err := validateQuery(query); err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidateMediaFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidatePaginationParams(&params); err != nil {
+		return nil, err
+	}
+
+	medias, total, err := uc.mediaRepo.SearchMedia(ctx, query, filter, params)
+	if err != nil {
+		return nil, domain.NewErrorFrom(err,
+			domain.WithDetails(fmt.Sprintf("error searching media: %s", err)))
+	}
+
+	for i := range medias {
+		downloadURL, err := uc.urlGenerator.GenerateDownloadURL(ctx, medias[i])
+		if err != nil {
+			return nil, domain.NewErrorFrom(err,
+				domain.WithDetails("error generating download URL"),
+			)
+		}
+		medias[i].URL = downloadURL
+	}
+
+	return &domain.PaginatedResult[domain.Media]{
+		Items:  medias,
+		Total:  total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}, nil
+}
+
+func validateQuery(query string) error {
+	if query == "" {
+		return domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid search query"),
+			domain.WithDetails("query cannot be empty"),
+		)
+	}
+	if len(query) > maxQueryLength {
+		return domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid search query"),
+			domain.WithDetails(fmt.Sprintf("query cannot exceed %d characters", maxQueryLength)),
+		)
+	}
+	return nil
+}
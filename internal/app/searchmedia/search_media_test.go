@@ -0,0 +1,198 @@
+package searchmedia
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/searchmedia MediaRepository
+//go:generate mockgen -destination=mocks/mock_url_generator.go -package=mocks github.com/peano88/medias/internal/app/searchmedia URLGenerator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/searchmedia/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	media := domain.Media{
+		ID:       uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Filename: "world-cup-final.jpg",
+		Status:   domain.MediaStatusFinalized,
+		Type:     domain.MediaTypeImage,
+		MimeType: "image/jpeg",
+		Size:     2048000,
+		SHA256:   "w0rldcup2023",
+		Tags:     []domain.Tag{},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		filter     domain.MediaFilter
+		params     domain.PaginationParams
+		setupMocks func(*mocks.MockMediaRepository, *mocks.MockURLGenerator)
+		validate   func(*testing.T, *domain.PaginatedResult[domain.Media], error)
+	}{
+		{
+			name:  "success - returns media with download URLs and default pagination",
+			query: "  goal ",
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					SearchMedia(ctx, "goal", domain.MediaFilter{}, domain.PaginationParams{Limit: domain.DefaultLimit}).
+					Return([]domain.Media{media}, 1, nil)
+				urlGen.EXPECT().
+					GenerateDownloadURL(ctx, media).
+					Return("https://s3.example.com/bucket/w0rldcup2023/world-cup-final.jpg?X-Amz-Signature=...", nil)
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				assert.NoError(t, err)
+				assert.Len(t, result.Items, 1)
+				assert.Contains(t, result.Items[0].URL, "X-Amz-Signature")
+				assert.Equal(t, 1, result.Total)
+				assert.Equal(t, domain.DefaultLimit, result.Limit)
+			},
+		},
+		{
+			name:  "success - normalizes tag filter",
+			query: "goal",
+			filter: domain.MediaFilter{
+				Status: domain.MediaStatusFinalized,
+				Type:   domain.MediaTypeImage,
+				Tags:   []string{" Soccer", "soccer", "", "football"},
+			},
+			params: domain.PaginationParams{Limit: 10, Offset: 20},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				expectedFilter := domain.MediaFilter{
+					Status: domain.MediaStatusFinalized,
+					Type:   domain.MediaTypeImage,
+					Tags:   []string{"soccer", "football"},
+				}
+				repo.EXPECT().
+					SearchMedia(ctx, "goal", expectedFilter, domain.PaginationParams{Limit: 10, Offset: 20}).
+					Return([]domain.Media{}, 0, nil)
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				assert.NoError(t, err)
+				assert.Len(t, result.Items, 0)
+				assert.Equal(t, 10, result.Limit)
+				assert.Equal(t, 20, result.Offset)
+			},
+		},
+		{
+			name:       "error - empty query",
+			query:      "   ",
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				assert.Nil(t, result)
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+					assert.Equal(t, "query cannot be empty", domainErr.Details)
+				}
+			},
+		},
+		{
+			name:       "error - query too long",
+			query:      strings.Repeat("a", maxQueryLength+1),
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+				}
+			},
+		},
+		{
+			name:       "error - unknown status",
+			query:      "goal",
+			filter:     domain.MediaFilter{Status: "deleted"},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+					assert.Equal(t, "invalid status filter", domainErr.Message)
+				}
+			},
+		},
+		{
+			name:       "error - unknown type",
+			query:      "goal",
+			filter:     domain.MediaFilter{Type: "audio"},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+					assert.Equal(t, "invalid type filter", domainErr.Message)
+				}
+			},
+		},
+		{
+			name:       "error - invalid pagination",
+			query:      "goal",
+			params:     domain.PaginationParams{Limit: domain.MaxLimit + 1},
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InvalidEntityCode, domainErr.Code)
+				}
+			},
+		},
+		{
+			name:  "error - repository failure",
+			query: "goal",
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					SearchMedia(ctx, "goal", domain.MediaFilter{}, domain.PaginationParams{Limit: domain.DefaultLimit}).
+					Return(nil, 0, domain.NewError(domain.InternalCode, domain.WithMessage("failed to search media")))
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InternalCode, domainErr.Code)
+				}
+			},
+		},
+		{
+			name:  "error - URL generation fails",
+			query: "goal",
+			setupMocks: func(repo *mocks.MockMediaRepository, urlGen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					SearchMedia(ctx, "goal", domain.MediaFilter{}, domain.PaginationParams{Limit: domain.DefaultLimit}).
+					Return([]domain.Media{media}, 1, nil)
+				urlGen.EXPECT().
+					GenerateDownloadURL(ctx, media).
+					Return("", domain.NewError(domain.InternalCode, domain.WithMessage("S3 service unavailable")))
+			},
+			validate: func(t *testing.T, result *domain.PaginatedResult[domain.Media], err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.InternalCode, domainErr.Code)
+					assert.Contains(t, domainErr.Details, "error generating download URL")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			urlGen := mocks.NewMockURLGenerator(ctrl)
+			tt.setupMocks(repo, urlGen)
+
+			uc := New(repo, urlGen)
+			result, err := uc.Execute(ctx, tt.query, tt.filter, tt.params)
+
+			tt.validate(t, result, err)
+		})
+	}
+}
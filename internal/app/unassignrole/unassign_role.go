@@ -0,0 +1,44 @@
+package unassignrole
+
+import (
+	"context"
+
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// RoleRepository defines the repository contract for unassigning roles
+type RoleRepository interface {
+	UnassignRole(ctx context.Context, subject, tenant string) error
+}
+
+// UseCase handles the removal of the roles assigned to principals
+type UseCase struct {
+	repo RoleRepository
+}
+
+// New creates a new UnassignRole use case
+func New(repo RoleRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute removes the role of the subject in the tenant, empty meaning every
+// tenant. The subject has the role matching its scopes from then on, unless
+// it holds another role in every tenant.
+func (uc *UseCase) Execute(ctx context.Context, subject, tenant string) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "unassignrole.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	tenant, err = domain.BindTenant(ctx, tenant)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.UnassignRole(ctx, subject, tenant); err != nil {
+		return domain.NewErrorFrom(err,
+			domain.WithDetails("error unassigning role"),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package unassignrole
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/unassignrole RoleRepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/peano88/medias/internal/app/unassignrole/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	acmeAdmin := domain.Principal{Subject: "acme-admin", Scopes: []domain.Scope{domain.ScopeAdmin}, Tenant: "acme"}
+
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		tenant    string
+		setupMock func(*mocks.MockRoleRepository)
+		wantCode  string
+	}{
+		{
+			name: "success - role in every tenant",
+			setupMock: func(repo *mocks.MockRoleRepository) {
+				repo.EXPECT().UnassignRole(gomock.Any(), "user-42", "").Return(nil)
+			},
+		},
+		{
+			name:      "success - principal bound to a tenant unassigns in its tenant",
+			principal: &acmeAdmin,
+			setupMock: func(repo *mocks.MockRoleRepository) {
+				repo.EXPECT().UnassignRole(gomock.Any(), "user-42", "acme").Return(nil)
+			},
+		},
+		{
+			name:      "error - principal bound to another tenant",
+			principal: &acmeAdmin,
+			tenant:    "globex",
+			setupMock: func(repo *mocks.MockRoleRepository) {},
+			wantCode:  domain.ForbiddenCode,
+		},
+		{
+			name:   "error - not found",
+			tenant: "acme",
+			setupMock: func(repo *mocks.MockRoleRepository) {
+				repo.EXPECT().UnassignRole(gomock.Any(), "user-42", "acme").
+					Return(domain.NewError(domain.NotFoundCode, domain.WithMessage("role assignment not found")))
+			},
+			wantCode: domain.NotFoundCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockRoleRepository(ctrl)
+			tt.setupMock(repo)
+
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = domain.ContextWithPrincipal(ctx, *tt.principal)
+			}
+
+			err := New(repo).Execute(ctx, "user-42", tt.tenant)
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, domain.HasCode(err, tt.wantCode), "unexpected error: %v", err)
+		})
+	}
+}
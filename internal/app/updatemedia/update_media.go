@@ -0,0 +1,66 @@
+package updatemedia
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// MediaRepository defines the repository contract for updating media
+type MediaRepository interface {
+	UpdateMediaDescription(ctx context.Context, id uuid.UUID, description *string, expectedUpdatedAt time.Time) (domain.Media, error)
+}
+
+// URLGenerator defines the contract for generating download URLs
+type URLGenerator interface {
+	GenerateDownloadURL(ctx context.Context, media domain.Media) (string, error)
+}
+
+// UseCase handles updating media records
+type UseCase struct {
+	mediaRepo    MediaRepository
+	urlGenerator URLGenerator
+}
+
+// New creates a new UpdateMedia use case
+func New(mediaRepo MediaRepository, urlGenerator URLGenerator) *UseCase {
+	return &UseCase{
+		mediaRepo:    mediaRepo,
+		urlGenerator: urlGenerator,
+	}
+}
+
+// Execute sets the description of a media, provided the media was not
+// modified since expectedUpdatedAt. A nil description clears it.
+func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID, description *string, expectedUpdatedAt time.Time) (_ domain.Media, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "updatemedia.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	if description != nil && len(*description) > 1000 {
+		return domain.Media{}, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid description"),
+			domain.WithDetails("description cannot exceed 1000 characters"),
+		)
+	}
+
+	media, err := uc.mediaRepo.UpdateMediaDescription(ctx, id, description, expectedUpdatedAt)
+	if err != nil {
+		return domain.Media{}, domain.NewErrorFrom(err,
+			domain.WithDetails("error updating media"),
+		)
+	}
+
+	// Respond with the same representation as a read
+	downloadURL, err := uc.urlGenerator.GenerateDownloadURL(ctx, media)
+	if err != nil {
+		return domain.Media{}, domain.NewErrorFrom(err,
+			domain.WithDetails("error generating download URL"),
+		)
+	}
+
+	media.URL = downloadURL
+	return media, nil
+}
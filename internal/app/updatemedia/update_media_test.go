@@ -0,0 +1,117 @@
+package updatemedia
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/updatemedia MediaRepository
+//go:generate mockgen -destination=mocks/mock_url_generator.go -package=mocks github.com/peano88/medias/internal/app/updatemedia URLGenerator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/updatemedia/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	mediaID := uuid.MustParse("111e1111-e11b-11d1-a111-111111111111")
+	expectedUpdatedAt := time.Date(2023, 6, 1, 11, 0, 0, 0, time.UTC)
+
+	updatedMedia := domain.Media{
+		ID:          mediaID,
+		Filename:    "world-cup-final.jpg",
+		Description: stringPtr("Winning goal"),
+		Status:      domain.MediaStatusFinalized,
+		UpdatedAt:   expectedUpdatedAt.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name        string
+		description *string
+		setupMocks  func(*mocks.MockMediaRepository, *mocks.MockURLGenerator)
+		validate    func(*testing.T, domain.Media, error)
+	}{
+		{
+			name:        "success",
+			description: stringPtr("Winning goal"),
+			setupMocks: func(repo *mocks.MockMediaRepository, gen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					UpdateMediaDescription(ctx, mediaID, stringPtr("Winning goal"), expectedUpdatedAt).
+					Return(updatedMedia, nil)
+				gen.EXPECT().
+					GenerateDownloadURL(ctx, updatedMedia).
+					Return("http://localhost:8080/download/world-cup-final.jpg", nil)
+			},
+			validate: func(t *testing.T, media domain.Media, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Winning goal", *media.Description)
+				assert.Equal(t, "http://localhost:8080/download/world-cup-final.jpg", media.URL)
+			},
+		},
+		{
+			name:        "validation error - description too long",
+			description: stringPtr(strings.Repeat("a", 1001)),
+			setupMocks:  func(repo *mocks.MockMediaRepository, gen *mocks.MockURLGenerator) {},
+			validate: func(t *testing.T, media domain.Media, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:        "stale version",
+			description: stringPtr("Winning goal"),
+			setupMocks: func(repo *mocks.MockMediaRepository, gen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					UpdateMediaDescription(ctx, mediaID, gomock.Any(), expectedUpdatedAt).
+					Return(domain.Media{}, domain.NewError(domain.PreconditionFailedCode, domain.WithMessage("media was modified")))
+			},
+			validate: func(t *testing.T, media domain.Media, err error) {
+				var domainErr *domain.Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, domain.PreconditionFailedCode, domainErr.Code)
+					assert.Equal(t, "error updating media", domainErr.Details)
+				}
+			},
+		},
+		{
+			name:        "error generating download URL",
+			description: stringPtr("Winning goal"),
+			setupMocks: func(repo *mocks.MockMediaRepository, gen *mocks.MockURLGenerator) {
+				repo.EXPECT().
+					UpdateMediaDescription(ctx, mediaID, gomock.Any(), expectedUpdatedAt).
+					Return(updatedMedia, nil)
+				gen.EXPECT().
+					GenerateDownloadURL(ctx, updatedMedia).
+					Return("", errors.New("presign failed"))
+			},
+			validate: func(t *testing.T, media domain.Media, err error) {
+				assert.True(t, domain.HasCode(err, domain.InternalCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockMediaRepository(ctrl)
+			gen := mocks.NewMockURLGenerator(ctrl)
+			tt.setupMocks(repo, gen)
+
+			uc := New(repo, gen)
+			media, err := uc.Execute(ctx, mediaID, tt.description, expectedUpdatedAt)
+
+			tt.validate(t, media, err)
+		})
+	}
+}
+
+// Helper function
+func stringPtr(s string) *string {
+	return &s
+}
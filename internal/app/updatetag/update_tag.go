@@ -0,0 +1,49 @@
+package updatetag
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/peano88/medias/internal/telemetry"
+)
+
+// TagRepository defines the repository contract for updating tags
+type TagRepository interface {
+	UpdateTagDescription(ctx context.Context, id uuid.UUID, description *string, expectedUpdatedAt time.Time) (domain.Tag, error)
+}
+
+// UseCase handles updating tags
+type UseCase struct {
+	repo TagRepository
+}
+
+// New creates a new UpdateTag use case
+func New(repo TagRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Execute sets the description of a tag, provided the tag was not modified
+// since expectedUpdatedAt. A nil description clears it.
+func (uc *UseCase) Execute(ctx context.Context, id uuid.UUID, description *string, expectedUpdatedAt time.Time) (_ domain.Tag, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "updatetag.Execute")
+	defer telemetry.EndSpan(span, &err)
+
+	if description != nil && len(*description) > 255 {
+		return domain.Tag{}, domain.NewError(domain.InvalidEntityCode,
+			domain.WithMessage("invalid description"),
+			domain.WithDetails("description should be less than 255 characters"),
+			domain.WithTS(time.Now()),
+		)
+	}
+
+	updated, err := uc.repo.UpdateTagDescription(ctx, id, description, expectedUpdatedAt)
+	if err != nil {
+		return domain.Tag{}, domain.NewErrorFrom(err,
+			domain.WithDetails("error updating tag"),
+		)
+	}
+
+	return updated, nil
+}
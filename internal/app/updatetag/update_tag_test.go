@@ -0,0 +1,102 @@
+package updatetag
+
+//go:generate mockgen -destination=mocks/mock_repository.go -package=mocks github.com/peano88/medias/internal/app/updatetag TagRepository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peano88/medias/internal/app/updatetag/mocks"
+	"github.com/peano88/medias/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUseCase_Execute(t *testing.T) {
+	ctx := context.Background()
+	tagID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	expectedUpdatedAt := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		description *string
+		setupMock   func(*mocks.MockTagRepository)
+		validate    func(*testing.T, domain.Tag, error)
+	}{
+		{
+			name:        "success",
+			description: stringPtr("Association football"),
+			setupMock: func(repo *mocks.MockTagRepository) {
+				repo.EXPECT().
+					UpdateTagDescription(ctx, tagID, stringPtr("Association football"), expectedUpdatedAt).
+					Return(domain.Tag{
+						ID:          tagID,
+						Name:        "soccer",
+						Description: stringPtr("Association football"),
+						UpdatedAt:   expectedUpdatedAt.Add(time.Minute),
+					}, nil)
+			},
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "Association football", *tag.Description)
+				assert.Equal(t, expectedUpdatedAt.Add(time.Minute), tag.UpdatedAt)
+			},
+		},
+		{
+			name:        "success - clear description",
+			description: nil,
+			setupMock: func(repo *mocks.MockTagRepository) {
+				repo.EXPECT().
+					UpdateTagDescription(ctx, tagID, nil, expectedUpdatedAt).
+					Return(domain.Tag{ID: tagID, Name: "soccer"}, nil)
+			},
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, tag.Description)
+			},
+		},
+		{
+			name:        "validation error - description too long",
+			description: stringPtr(strings.Repeat("a", 256)),
+			setupMock:   func(repo *mocks.MockTagRepository) {},
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				assert.True(t, domain.HasCode(err, domain.InvalidEntityCode))
+			},
+		},
+		{
+			name:        "stale version",
+			description: stringPtr("Association football"),
+			setupMock: func(repo *mocks.MockTagRepository) {
+				repo.EXPECT().
+					UpdateTagDescription(ctx, tagID, gomock.Any(), expectedUpdatedAt).
+					Return(domain.Tag{}, domain.NewError(domain.PreconditionFailedCode, domain.WithMessage("tag was modified")))
+			},
+			validate: func(t *testing.T, tag domain.Tag, err error) {
+				assert.True(t, domain.HasCode(err, domain.PreconditionFailedCode))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockTagRepository(ctrl)
+			tt.setupMock(repo)
+
+			uc := New(repo)
+			tag, err := uc.Execute(ctx, tagID, tt.description, expectedUpdatedAt)
+
+			tt.validate(t, tag, err)
+		})
+	}
+}
+
+// Helper function
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope is a permission granted to a client
+type Scope string
+
+const (
+	ScopeTagsRead   Scope = "tags:read"
+	ScopeTagsWrite  Scope = "tags:write"
+	ScopeMediaRead  Scope = "media:read"
+	ScopeMediaWrite Scope = "media:write"
+	// ScopeAdmin allows managing the API keys
+	ScopeAdmin Scope = "admin"
+	// ScopeAllTenants allows acting on any tenant, not only the one of the
+	// credentials
+	ScopeAllTenants Scope = "tenants:all"
+)
+
+// Scopes lists every known scope
+var Scopes = []Scope{ScopeTagsRead, ScopeTagsWrite, ScopeMediaRead, ScopeMediaWrite, ScopeAdmin, ScopeAllTenants}
+
+// ValidateScopes checks that scopes is a non empty list of known scopes
+func ValidateScopes(scopes []Scope) error {
+	if len(scopes) == 0 {
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid scopes"),
+			WithDetails("at least one scope is required"),
+		)
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return NewError(InvalidEntityCode,
+				WithMessage("invalid scopes"),
+				WithDetails(fmt.Sprintf("unknown scope: %s", scope)),
+			)
+		}
+	}
+
+	return nil
+}
+
+// APIKeyPrefixLength is the number of leading characters of a key kept in
+// clear to identify it
+const APIKeyPrefixLength = 12
+
+// APIKey is a key authenticating a client, granting it a set of scopes
+type APIKey struct {
+	ID   uuid.UUID
+	Name string
+	// Prefix is the beginning of the key, to identify it without revealing it
+	Prefix string
+	// Hash is the SHA-256 of the key, the key itself is never stored
+	Hash string
+	// Key is the key in clear, only known when it is created
+	Key    string
+	Scopes []Scope
+	// Tenant is the tenant the key acts on, the only one unless the key was
+	// granted ScopeAllTenants
+	Tenant    string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// Revoked reports whether the key can no longer be used
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Principal returns the identity authenticated by the key. A key without
+// tenant acts on DefaultTenant.
+func (k APIKey) Principal() Principal {
+	tenant := k.Tenant
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+	return Principal{
+		Subject: APIKeySubjectPrefix + k.ID.String(),
+		Scopes:  k.Scopes,
+		Tenant:  tenant,
+	}
+}
+
+// HashAPIKey returns the hash under which a key is stored. Keys are random
+// with enough entropy for a plain SHA-256 to be safe.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditOutcome is the outcome of an audited operation
+type AuditOutcome string
+
+const (
+	AuditOutcomeDenied AuditOutcome = "denied"
+)
+
+// AuditEvent is an entry of the audit trail
+type AuditEvent struct {
+	ID      uuid.UUID
+	Tenant  string
+	Subject string
+	Action  Action
+	Outcome AuditOutcome
+	// Details explains the outcome
+	Details    string
+	OccurredAt time.Time
+}
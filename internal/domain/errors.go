@@ -25,6 +25,16 @@ const (
 	InternalCode      = "INTERNAL"
 	ConflictCode      = "CONFLICT"
 	NotFoundCode      = "NOT_FOUND"
+	// PreconditionFailedCode reports an update based on a stale version of the entity
+	PreconditionFailedCode = "PRECONDITION_FAILED"
+	// PreconditionRequiredCode reports an update missing the version of the entity it is based on
+	PreconditionRequiredCode = "PRECONDITION_REQUIRED"
+	// UnauthenticatedCode reports missing or invalid credentials
+	UnauthenticatedCode = "UNAUTHENTICATED"
+	// ForbiddenCode reports credentials lacking the permission for the operation
+	ForbiddenCode = "FORBIDDEN"
+	// RateLimitedCode reports a client exceeding its rate limit
+	RateLimitedCode = "RATE_LIMITED"
 )
 
 type ErrOpts func(*Error) *Error
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// MaxIdempotencyKeyLength is the maximum length of an idempotency key
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyKey is an idempotency key scoped to the client which sent it:
+// the same key sent by clients of different tenants or subjects identifies
+// different requests
+type IdempotencyKey struct {
+	Tenant  string
+	Subject string
+	Key     string
+}
+
+// IdempotencyRecord is a request processed under an idempotency key,
+// with the response to replay when the request is retried
+type IdempotencyRecord struct {
+	IdempotencyKey
+	Fingerprint string
+	// StatusCode is zero while the original request is still being processed
+	StatusCode  int
+	ContentType string
+	// Headers are the response headers replayed along with the body,
+	// such as Location or ETag
+	Headers   map[string]string
+	Body      []byte
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Completed reports whether the response of the original request was stored
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,6 +25,33 @@ const (
 	MediaStatusFailed    MediaStatus = "failed"
 )
 
+// Visibility tells who, besides its owner, can see a media
+type Visibility string
+
+const (
+	// VisibilityPrivate media are only visible to their owner
+	VisibilityPrivate Visibility = "private"
+	// VisibilityShared media are visible to every authenticated client
+	VisibilityShared Visibility = "shared"
+	// VisibilityPublic media are visible to anyone
+	VisibilityPublic Visibility = "public"
+)
+
+// ValidateVisibility checks the visibility, defaulting to private when empty
+func ValidateVisibility(visibility *Visibility) error {
+	switch *visibility {
+	case "":
+		*visibility = VisibilityPrivate
+	case VisibilityPrivate, VisibilityShared, VisibilityPublic:
+	default:
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid visibility"),
+			WithDetails(fmt.Sprintf("unknown visibility: %s", *visibility)),
+		)
+	}
+	return nil
+}
+
 type MediaOperation string
 
 const (
@@ -42,7 +71,102 @@ type Media struct {
 	MimeType    string
 	Size        int64
 	SHA256      string
-	Tags        []Tag
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// Owner is the subject of the principal which created the media, empty
+	// when it was created without authentication
+	Owner      string
+	Visibility Visibility
+	// Tenant is the tenant the media belongs to
+	Tenant    string
+	Tags      []Tag
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// URLIssuedAt and URLExpiresAt bound the validity of URL, when known
+	URLIssuedAt  time.Time
+	URLExpiresAt time.Time
+}
+
+// OwnedBy reports whether the media belongs to the subject
+func (m Media) OwnedBy(subject string) bool {
+	return m.Owner == subject
+}
+
+// MinDownloadURLLifetime is the validity a download URL held by a client must
+// still have for its representation to be considered current: clients need
+// some time to actually use the URL once they got it.
+const MinDownloadURLLifetime = 30 * time.Second
+
+// CachedMedia describes a media representation a client already holds, as
+// told by the validators of a conditional request
+type CachedMedia struct {
+	// UpdatedAt is the latest metadata modification the representation includes
+	UpdatedAt time.Time
+	// URLIssuedAt is when the download URL of the representation was generated
+	URLIssuedAt time.Time
+}
+
+// MediaFilter holds the optional criteria used to narrow down media listings.
+// Zero values mean no filtering on the corresponding field.
+type MediaFilter struct {
+	Status MediaStatus
+	Type   MediaType
+	// Tags lists tag names that must all be associated with the media
+	Tags []string
+	// TagsQuery is a boolean expression over tag names (see ParseTagQuery)
+	TagsQuery TagExpr
+}
+
+// ValidateMediaFilter checks the filter values and normalizes tag names the
+// same way they are normalized on creation
+func ValidateMediaFilter(filter *MediaFilter) error {
+	switch filter.Status {
+	case "", MediaStatusReserved, MediaStatusFinalized, MediaStatusFailed:
+	default:
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid status filter"),
+			WithDetails(fmt.Sprintf("unknown media status: %s", filter.Status)),
+		)
+	}
+
+	switch filter.Type {
+	case "", MediaTypeImage, MediaTypeVideo:
+	default:
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid type filter"),
+			WithDetails(fmt.Sprintf("unknown media type: %s", filter.Type)),
+		)
+	}
+
+	tags := make([]string, 0, len(filter.Tags))
+	seen := make(map[string]bool, len(filter.Tags))
+	for _, tag := range filter.Tags {
+		name := strings.ToLower(strings.TrimSpace(tag))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+	filter.Tags = nil
+	if len(tags) > 0 {
+		filter.Tags = tags
+	}
+
+	return nil
+}
+
+// MaxBatchSize is the maximum number of items accepted by batch operations
+const MaxBatchSize = 1000
+
+// MediaBatchItem is one media to create within a batch, with the names of
+// the tags to associate with it
+type MediaBatchItem struct {
+	Media    Media
+	TagNames []string
+}
+
+// MediaBatchResult is the outcome of one item of a batch operation:
+// either the resulting media or the error that prevented it
+type MediaBatchResult struct {
+	Media Media
+	Err   error
 }
@@ -0,0 +1,23 @@
+package domain
+
+// FinalizeFailureReason is why a media could not be finalized, as reported by
+// the media lifecycle metrics
+type FinalizeFailureReason string
+
+const (
+	// FinalizeFailureNotFound is a media which does not exist or is not visible
+	FinalizeFailureNotFound FinalizeFailureReason = "not_found"
+	// FinalizeFailureForbidden is a media owned by another client
+	FinalizeFailureForbidden FinalizeFailureReason = "forbidden"
+	// FinalizeFailureAlreadyFinalized is a media finalized before
+	FinalizeFailureAlreadyFinalized FinalizeFailureReason = "already_finalized"
+	// FinalizeFailurePreviouslyFailed is a media whose upload failed before
+	FinalizeFailurePreviouslyFailed FinalizeFailureReason = "previously_failed"
+	// FinalizeFailureMissingFile is a media whose file was not uploaded, it is
+	// marked as failed
+	FinalizeFailureMissingFile FinalizeFailureReason = "missing_file"
+	// FinalizeFailureStorage is a file storage which could not be checked
+	FinalizeFailureStorage FinalizeFailureReason = "storage_error"
+	// FinalizeFailureInternal is any other failure
+	FinalizeFailureInternal FinalizeFailureReason = "internal"
+)
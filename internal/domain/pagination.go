@@ -1,5 +1,7 @@
 package domain
 
+import "fmt"
+
 const (
 	DefaultLimit = 50
 	MaxLimit     = 100
@@ -18,3 +20,38 @@ type PaginatedResult[T any] struct {
 	Limit  int
 	Offset int
 }
+
+// ValidatePaginationParams validates the pagination parameters and applies
+// the default limit when none is provided
+func ValidatePaginationParams(params *PaginationParams) error {
+	// Validate offset
+	if params.Offset < 0 {
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid pagination parameters"),
+			WithDetails("offset cannot be negative"),
+		)
+	}
+
+	// Validate limit
+	if params.Limit < 0 {
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid pagination parameters"),
+			WithDetails("limit cannot be negative"),
+		)
+	}
+
+	// Apply default limit if not provided
+	if params.Limit == 0 {
+		params.Limit = DefaultLimit
+	}
+
+	// Validate max limit
+	if params.Limit > MaxLimit {
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid pagination parameters"),
+			WithDetails(fmt.Sprintf("limit cannot exceed %d", MaxLimit)),
+		)
+	}
+
+	return nil
+}
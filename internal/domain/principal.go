@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"slices"
+)
+
+// The subjects of the principals are prefixed by the kind of their
+// credentials, so that a credential cannot name itself after another kind,
+// e.g. a token with the sub claim of an API key
+const (
+	APIKeySubjectPrefix = "api-key:"
+	TokenSubjectPrefix  = "jwt:"
+)
+
+// Principal is the authenticated client of a request
+type Principal struct {
+	// Subject identifies the client, e.g. jwt: followed by the sub claim of
+	// its token
+	Subject string
+	Scopes  []Scope
+	// Tenant is the tenant of the credentials, DefaultTenant when they name
+	// none. It is the only tenant the principal can act on unless it was
+	// granted ScopeAllTenants.
+	Tenant string
+}
+
+// HasScope reports whether the principal was granted the scope
+func (p Principal) HasScope(scope Scope) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// CanActOn reports whether the principal may act on tenant, empty meaning
+// every tenant
+func (p Principal) CanActOn(tenant string) bool {
+	return p.HasScope(ScopeAllTenants) || (tenant != "" && tenant == p.Tenant)
+}
+
+// TokenClaims are the claims of a verified bearer token relevant to the service
+type TokenClaims struct {
+	Subject string
+	// Scopes lists the scopes of the token as issued, known or not
+	Scopes []string
+	// Tenant is the tenant the token is bound to, if any
+	Tenant string
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the authenticated client
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated client carried by ctx, if any
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
+
+// SubjectFromContext returns the subject of the authenticated client carried
+// by ctx, or an empty string for anonymous requests
+func SubjectFromContext(ctx context.Context) string {
+	principal, _ := PrincipalFromContext(ctx)
+	return principal.Subject
+}
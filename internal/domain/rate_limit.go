@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// RateLimit is a token bucket: a client can send Burst requests at once, the
+// bucket being refilled at Rate requests per second
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitDecision is the outcome of taking a token from a bucket
+type RateLimitDecision struct {
+	Allowed bool
+	// Limit is the size of the bucket
+	Limit int
+	// Remaining is the number of requests which would be allowed right away
+	Remaining int
+	// RetryAfter is when the next request will be allowed, zero when allowed
+	RetryAfter time.Duration
+	// Reset is when the bucket will be full again
+	Reset time.Duration
+}
+
+// Decision describes a bucket of this limit holding tokens once the request
+// was, or was not, allowed
+func (l RateLimit) Decision(tokens float64, allowed bool) RateLimitDecision {
+	decision := RateLimitDecision{
+		Allowed:   allowed,
+		Limit:     l.Burst,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     l.refillTime(float64(l.Burst) - tokens),
+	}
+	if !allowed {
+		decision.RetryAfter = l.refillTime(1 - tokens)
+	}
+	return decision
+}
+
+// refillTime is the time the bucket takes to gain tokens
+func (l RateLimit) refillTime(tokens float64) time.Duration {
+	if tokens <= 0 || l.Rate <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / l.Rate * float64(time.Second))
+}
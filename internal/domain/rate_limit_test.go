@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit_Decision(t *testing.T) {
+	limit := RateLimit{Rate: 2, Burst: 10}
+
+	tests := []struct {
+		name     string
+		tokens   float64
+		allowed  bool
+		expected RateLimitDecision
+	}{
+		{
+			name:    "allowed",
+			tokens:  9,
+			allowed: true,
+			expected: RateLimitDecision{
+				Allowed:   true,
+				Limit:     10,
+				Remaining: 9,
+				Reset:     500 * time.Millisecond,
+			},
+		},
+		{
+			name:    "last token",
+			tokens:  0.5,
+			allowed: true,
+			expected: RateLimitDecision{
+				Allowed:   true,
+				Limit:     10,
+				Remaining: 0,
+				Reset:     4750 * time.Millisecond,
+			},
+		},
+		{
+			name:    "denied",
+			tokens:  0.5,
+			allowed: false,
+			expected: RateLimitDecision{
+				Allowed:    false,
+				Limit:      10,
+				Remaining:  0,
+				RetryAfter: 250 * time.Millisecond,
+				Reset:      4750 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, limit.Decision(tt.tokens, tt.allowed))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Role is the set of actions a principal may perform. Roles are ordered, each
+// one allowing the actions of the previous ones.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// Roles lists every known role, from the least to the most privileged
+var Roles = []Role{RoleViewer, RoleEditor, RoleAdmin}
+
+// ValidateRole checks that role is a known role
+func ValidateRole(role Role) error {
+	if !slices.Contains(Roles, role) {
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid role"),
+			WithDetails(fmt.Sprintf("role must be one of %v: %q", Roles, role)),
+		)
+	}
+	return nil
+}
+
+// Action is an operation subject to authorization
+type Action string
+
+const (
+	ActionReadTags      Action = "tags.read"
+	ActionWriteTags     Action = "tags.write"
+	ActionReadMedia     Action = "media.read"
+	ActionWriteMedia    Action = "media.write"
+	ActionManageAPIKeys Action = "api-keys.manage"
+	ActionManageRoles   Action = "roles.manage"
+	// ActionAccessTenant is acting on a tenant, which is decided by the
+	// scopes of the principal rather than by its role: it is only audited
+	ActionAccessTenant Action = "tenant.access"
+)
+
+// Policy maps every action to the least privileged role allowed to perform it.
+// Actions missing from the policy are denied to every role.
+var Policy = map[Action]Role{
+	ActionReadTags:      RoleViewer,
+	ActionWriteTags:     RoleEditor,
+	ActionReadMedia:     RoleViewer,
+	ActionWriteMedia:    RoleEditor,
+	ActionManageAPIKeys: RoleAdmin,
+	ActionManageRoles:   RoleAdmin,
+}
+
+// Allows reports whether the role may perform the action
+func (r Role) Allows(action Action) bool {
+	required, ok := Policy[action]
+	if !ok {
+		return false
+	}
+	rank := slices.Index(Roles, r)
+	return rank >= 0 && rank >= slices.Index(Roles, required)
+}
+
+// RoleFromScopes is the role of a principal without role assignment: the one
+// matching the scopes of its credentials
+func RoleFromScopes(scopes []Scope) Role {
+	switch {
+	case slices.Contains(scopes, ScopeAdmin):
+		return RoleAdmin
+	case slices.Contains(scopes, ScopeTagsWrite), slices.Contains(scopes, ScopeMediaWrite):
+		return RoleEditor
+	default:
+		return RoleViewer
+	}
+}
+
+// RoleAssignment grants a role to a principal
+type RoleAssignment struct {
+	// Subject identifies the principal, as in Principal.Subject
+	Subject string
+	Role    Role
+	// Tenant is the tenant the role is granted in, empty when it is granted in
+	// every tenant. An assignment in the tenant of the request takes
+	// precedence over one in every tenant.
+	Tenant    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Authorizer decides whether the principal carried by a context may perform
+// an action
+type Authorizer interface {
+	// Authorize returns an error with ForbiddenCode when the action is denied
+	Authorize(ctx context.Context, action Action) error
+}
+
+type authorizerContextKey struct{}
+
+// ContextWithAuthorizer returns a copy of ctx carrying the authorizer of the
+// request
+func ContextWithAuthorizer(ctx context.Context, authorizer Authorizer) context.Context {
+	return context.WithValue(ctx, authorizerContextKey{}, authorizer)
+}
+
+// Authorize checks that the principal carried by ctx may perform the action,
+// with the authorizer carried by ctx. Every action is allowed when there is
+// no authorizer, i.e. when authorization is disabled.
+func Authorize(ctx context.Context, action Action) error {
+	authorizer, ok := ctx.Value(authorizerContextKey{}).(Authorizer)
+	if !ok {
+		return nil
+	}
+	return authorizer.Authorize(ctx, action)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role    Role
+		action  Action
+		allowed bool
+	}{
+		{RoleViewer, ActionReadMedia, true},
+		{RoleViewer, ActionWriteMedia, false},
+		{RoleEditor, ActionWriteTags, true},
+		{RoleEditor, ActionManageAPIKeys, false},
+		{RoleAdmin, ActionManageRoles, true},
+		{RoleAdmin, "media.purge", false},
+		{"owner", ActionReadTags, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role)+" "+string(tt.action), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.role.Allows(tt.action))
+		})
+	}
+}
+
+func TestRoleFromScopes(t *testing.T) {
+	assert.Equal(t, RoleViewer, RoleFromScopes(nil))
+	assert.Equal(t, RoleViewer, RoleFromScopes([]Scope{ScopeMediaRead}))
+	assert.Equal(t, RoleEditor, RoleFromScopes([]Scope{ScopeTagsRead, ScopeTagsWrite}))
+	assert.Equal(t, RoleAdmin, RoleFromScopes([]Scope{ScopeAdmin}))
+}
+
+type denyAll struct{}
+
+func (denyAll) Authorize(context.Context, Action) error {
+	return NewError(ForbiddenCode)
+}
+
+func TestAuthorize(t *testing.T) {
+	assert.NoError(t, Authorize(context.Background(), ActionManageRoles))
+
+	ctx := ContextWithAuthorizer(context.Background(), denyAll{})
+	assert.True(t, HasCode(Authorize(ctx, ActionReadTags), ForbiddenCode))
+}
@@ -24,3 +24,15 @@ type Tag struct {
 	// UpdatedAt is the timestamp when the tag was last updated
 	UpdatedAt time.Time
 }
+
+// RelatedTag is a tag appearing on the same media as another tag
+type RelatedTag struct {
+	Tag Tag
+
+	// Cooccurrences is the number of media carrying both tags
+	Cooccurrences int
+
+	// Lift measures how much more often both tags appear together than
+	// if they were independent (> 1 means positively correlated)
+	Lift float64
+}
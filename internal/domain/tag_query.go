@@ -0,0 +1,282 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxTagQueryLength = 1000
+	maxTagQueryTerms  = 32
+)
+
+// TagExpr is a node of a boolean tag query such as `(beach OR sea) AND NOT night`
+type TagExpr interface {
+	String() string
+	tagExpr()
+}
+
+// TagExprName matches media associated with the named tag
+type TagExprName struct {
+	Name string
+}
+
+// TagExprNot matches media not matching the inner expression
+type TagExprNot struct {
+	Expr TagExpr
+}
+
+// TagExprAnd matches media matching both expressions
+type TagExprAnd struct {
+	Left  TagExpr
+	Right TagExpr
+}
+
+// TagExprOr matches media matching at least one of the expressions
+type TagExprOr struct {
+	Left  TagExpr
+	Right TagExpr
+}
+
+func (TagExprName) tagExpr() {}
+func (TagExprNot) tagExpr()  {}
+func (TagExprAnd) tagExpr()  {}
+func (TagExprOr) tagExpr()   {}
+
+func (e TagExprName) String() string { return fmt.Sprintf("%q", e.Name) }
+func (e TagExprNot) String() string  { return "NOT " + e.Expr.String() }
+func (e TagExprAnd) String() string  { return "(" + e.Left.String() + " AND " + e.Right.String() + ")" }
+func (e TagExprOr) String() string   { return "(" + e.Left.String() + " OR " + e.Right.String() + ")" }
+
+// ParseTagQuery parses a boolean tag query.
+//
+// The grammar, by increasing precedence, is:
+//
+//	expr    := and ( OR and )*
+//	and     := not ( AND not )*
+//	not     := NOT not | primary
+//	primary := '(' expr ')' | tag
+//
+// Keywords are case insensitive. Tag names are normalized like on creation and
+// can be double quoted when they contain spaces, parentheses or keywords.
+// Syntax errors are returned as InvalidEntityCode errors with the (1-based)
+// position of the offending token in the details.
+func ParseTagQuery(query string) (TagExpr, error) {
+	if len(query) > maxTagQueryLength {
+		return nil, tagQueryError(maxTagQueryLength+1, fmt.Sprintf("query cannot exceed %d characters", maxTagQueryLength))
+	}
+
+	tokens, err := tokenizeTagQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &tagQueryParser{tokens: tokens, end: len([]rune(query)) + 1}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, ok := p.peek(); ok {
+		return nil, tagQueryError(tok.pos, fmt.Sprintf("unexpected %s", tok))
+	}
+
+	return expr, nil
+}
+
+type tagTokenKind int
+
+const (
+	tagTokenName tagTokenKind = iota
+	tagTokenAnd
+	tagTokenOr
+	tagTokenNot
+	tagTokenOpen
+	tagTokenClose
+)
+
+type tagToken struct {
+	kind  tagTokenKind
+	value string
+	pos   int
+}
+
+func (t tagToken) String() string {
+	switch t.kind {
+	case tagTokenName:
+		return fmt.Sprintf("tag %q", t.value)
+	case tagTokenOpen, tagTokenClose:
+		return fmt.Sprintf("'%s'", t.value)
+	default:
+		return t.value
+	}
+}
+
+func tokenizeTagQuery(query string) ([]tagToken, error) {
+	var tokens []tagToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, tagToken{kind: tagTokenOpen, value: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, tagToken{kind: tagTokenClose, value: ")", pos: pos})
+			i++
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			if j == len(runes) {
+				return nil, tagQueryError(pos, "unterminated quoted tag name")
+			}
+			name := normalizeTagQueryName(string(runes[i+1 : j]))
+			if name == "" {
+				return nil, tagQueryError(pos, "empty tag name")
+			}
+			tokens = append(tokens, tagToken{kind: tagTokenName, value: name, pos: pos})
+			i = j + 1
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && runes[j] != '(' && runes[j] != ')' && runes[j] != '"' {
+				j++
+			}
+			word := string(runes[i:j])
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, tagToken{kind: tagTokenAnd, value: "AND", pos: pos})
+			case "OR":
+				tokens = append(tokens, tagToken{kind: tagTokenOr, value: "OR", pos: pos})
+			case "NOT":
+				tokens = append(tokens, tagToken{kind: tagTokenNot, value: "NOT", pos: pos})
+			default:
+				tokens = append(tokens, tagToken{kind: tagTokenName, value: normalizeTagQueryName(word), pos: pos})
+			}
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+// normalizeTagQueryName normalizes tag names the same way they are stored
+func normalizeTagQueryName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+type tagQueryParser struct {
+	tokens []tagToken
+	pos    int
+	end    int
+	terms  int
+}
+
+func (p *tagQueryParser) peek() (tagToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return tagToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *tagQueryParser) accept(kind tagTokenKind) bool {
+	if tok, ok := p.peek(); ok && tok.kind == kind {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *tagQueryParser) parseOr() (TagExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept(tagTokenOr) {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = TagExprOr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *tagQueryParser) parseAnd() (TagExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept(tagTokenAnd) {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = TagExprAnd{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *tagQueryParser) parseNot() (TagExpr, error) {
+	if p.accept(tagTokenNot) {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return TagExprNot{Expr: expr}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *tagQueryParser) parsePrimary() (TagExpr, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, tagQueryError(p.end, "unexpected end of query, expected tag name or '('")
+	}
+
+	switch tok.kind {
+	case tagTokenOpen:
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(tagTokenClose) {
+			if next, ok := p.peek(); ok {
+				return nil, tagQueryError(next.pos, fmt.Sprintf("expected ')', got %s", next))
+			}
+			return nil, tagQueryError(p.end, "unexpected end of query, expected ')'")
+		}
+		return expr, nil
+
+	case tagTokenName:
+		p.pos++
+		p.terms++
+		if p.terms > maxTagQueryTerms {
+			return nil, tagQueryError(tok.pos, fmt.Sprintf("query cannot contain more than %d tags", maxTagQueryTerms))
+		}
+		return TagExprName{Name: tok.value}, nil
+
+	default:
+		return nil, tagQueryError(tok.pos, fmt.Sprintf("expected tag name or '(', got %s", tok))
+	}
+}
+
+func tagQueryError(position int, reason string) *Error {
+	return NewError(InvalidEntityCode,
+		WithMessage("invalid tags query"),
+		WithDetails(fmt.Sprintf("syntax error at position %d: %s", position, reason)),
+	)
+}
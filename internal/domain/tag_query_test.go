@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagQuery(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		expected        TagExpr
+		expectedDetails string
+	}{
+		{
+			name:     "single tag",
+			query:    "beach",
+			expected: TagExprName{Name: "beach"},
+		},
+		{
+			name:  "precedence of NOT over AND over OR",
+			query: "beach OR sea AND NOT night",
+			expected: TagExprOr{
+				Left: TagExprName{Name: "beach"},
+				Right: TagExprAnd{
+					Left:  TagExprName{Name: "sea"},
+					Right: TagExprNot{Expr: TagExprName{Name: "night"}},
+				},
+			},
+		},
+		{
+			name:  "parentheses and case insensitive keywords",
+			query: "(Beach or sea) and not NIGHT",
+			expected: TagExprAnd{
+				Left: TagExprOr{
+					Left:  TagExprName{Name: "beach"},
+					Right: TagExprName{Name: "sea"},
+				},
+				Right: TagExprNot{Expr: TagExprName{Name: "night"}},
+			},
+		},
+		{
+			name:  "quoted tag names",
+			query: `"world cup" AND "or"`,
+			expected: TagExprAnd{
+				Left:  TagExprName{Name: "world cup"},
+				Right: TagExprName{Name: "or"},
+			},
+		},
+		{
+			name:            "empty query",
+			query:           "   ",
+			expectedDetails: "syntax error at position 4: unexpected end of query, expected tag name or '('",
+		},
+		{
+			name:            "missing operand",
+			query:           "beach AND",
+			expectedDetails: "syntax error at position 10: unexpected end of query, expected tag name or '('",
+		},
+		{
+			name:            "missing operator",
+			query:           "beach sea",
+			expectedDetails: `syntax error at position 7: unexpected tag "sea"`,
+		},
+		{
+			name:            "unbalanced parenthesis",
+			query:           "(beach OR sea",
+			expectedDetails: "syntax error at position 14: unexpected end of query, expected ')'",
+		},
+		{
+			name:            "unexpected closing parenthesis",
+			query:           "beach)",
+			expectedDetails: "syntax error at position 6: unexpected ')'",
+		},
+		{
+			name:            "operator in operand position",
+			query:           "(OR sea)",
+			expectedDetails: "syntax error at position 2: expected tag name or '(', got OR",
+		},
+		{
+			name:            "unterminated quote",
+			query:           `beach AND "sea`,
+			expectedDetails: "syntax error at position 11: unterminated quoted tag name",
+		},
+		{
+			name:            "too many tags",
+			query:           strings.TrimSuffix(strings.Repeat("t OR ", maxTagQueryTerms+1), " OR "),
+			expectedDetails: "syntax error at position 161: query cannot contain more than 32 tags",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseTagQuery(tt.query)
+			if tt.expectedDetails != "" {
+				assert.Nil(t, expr)
+				var domainErr *Error
+				if assert.ErrorAs(t, err, &domainErr) {
+					assert.Equal(t, InvalidEntityCode, domainErr.Code)
+					assert.Equal(t, tt.expectedDetails, domainErr.Details)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expr)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// DefaultTenant is the tenant of the requests which do not name one, and of
+// the data which predates multi-tenancy
+const DefaultTenant = "default"
+
+// tenantPattern restricts tenant identifiers to values safe to use in storage
+// keys and database settings
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// ValidateTenant checks the format of a tenant identifier: up to 63 lowercase
+// letters, digits and dashes, not starting with a dash
+func ValidateTenant(tenant string) error {
+	if !tenantPattern.MatchString(tenant) {
+		return NewError(InvalidEntityCode,
+			WithMessage("invalid tenant"),
+			WithDetails(fmt.Sprintf("tenant must be up to 63 lowercase letters, digits and dashes: %q", tenant)),
+		)
+	}
+	return nil
+}
+
+type tenantContextKey struct{}
+
+// ContextWithTenant returns a copy of ctx carrying the tenant of the request
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant carried by ctx, DefaultTenant when
+// there is none
+func TenantFromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(tenantContextKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
+
+// BindTenant returns the tenant to bind a resource to when it is requested for
+// tenant, empty meaning every tenant, on behalf of the principal carried by
+// ctx. A principal can only bind resources to its own tenant, which is the
+// default, unless it was granted ScopeAllTenants. Without principal, i.e. when
+// authentication is disabled, any tenant is allowed.
+func BindTenant(ctx context.Context, tenant string) (string, error) {
+	if tenant != "" {
+		if err := ValidateTenant(tenant); err != nil {
+			return "", err
+		}
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.HasScope(ScopeAllTenants) {
+		return tenant, nil
+	}
+	if tenant != "" && tenant != principal.Tenant {
+		return "", NewError(ForbiddenCode,
+			WithMessage("tenant not allowed"),
+			WithDetails(fmt.Sprintf("the principal is bound to the tenant %s", principal.Tenant)),
+		)
+	}
+	return principal.Tenant, nil
+}
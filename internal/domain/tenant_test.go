@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindTenant(t *testing.T) {
+	acme := Principal{Subject: "user-42", Tenant: "acme"}
+	operator := Principal{Subject: "operator", Scopes: []Scope{ScopeAllTenants}, Tenant: DefaultTenant}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		tenant    string
+		expected  string
+		wantCode  string
+	}{
+		{name: "no principal - any tenant", tenant: "acme", expected: "acme"},
+		{name: "no principal - every tenant", tenant: "", expected: ""},
+		{name: "own tenant by default", principal: &acme, tenant: "", expected: "acme"},
+		{name: "own tenant", principal: &acme, tenant: "acme", expected: "acme"},
+		{name: "another tenant", principal: &acme, tenant: "globex", wantCode: ForbiddenCode},
+		{name: "granted every tenant - another tenant", principal: &operator, tenant: "globex", expected: "globex"},
+		{name: "granted every tenant - every tenant", principal: &operator, tenant: "", expected: ""},
+		{name: "invalid tenant", principal: &operator, tenant: "Acme Corp", wantCode: InvalidEntityCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = ContextWithPrincipal(ctx, *tt.principal)
+			}
+
+			tenant, err := BindTenant(ctx, tt.tenant)
+			if tt.wantCode != "" {
+				assert.True(t, HasCode(err, tt.wantCode))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tenant)
+		})
+	}
+}
+
+func TestPrincipal_CanActOn(t *testing.T) {
+	acme := Principal{Subject: "user-42", Tenant: "acme"}
+	operator := Principal{Subject: "operator", Scopes: []Scope{ScopeAllTenants}, Tenant: DefaultTenant}
+
+	assert.True(t, acme.CanActOn("acme"))
+	assert.False(t, acme.CanActOn("globex"))
+	assert.False(t, acme.CanActOn(""))
+	assert.False(t, Principal{Subject: "user-42"}.CanActOn(""))
+	assert.True(t, operator.CanActOn("globex"))
+	assert.True(t, operator.CanActOn(""))
+}
@@ -0,0 +1,34 @@
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/peano88/medias"
+
+// StartSpan starts a span named after the operation, e.g. "createtag.Execute",
+// as a child of the span of ctx. Spans are recorded by the global tracer
+// provider, a no-op one unless tracing is enabled. A span which is not
+// recorded is not worth carrying: ctx is then returned as is.
+func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	spanCtx, span := otel.Tracer(instrumentationName).Start(ctx, name)
+	if !span.IsRecording() {
+		return ctx, span
+	}
+	return spanCtx, span
+}
+
+// EndSpan ends the span, recording *err as its failure when not nil. It is
+// meant to be deferred with a pointer to the named error result of the
+// operation.
+func EndSpan(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartSpan_NotRecording(t *testing.T) {
+	ctx := context.Background()
+
+	spanCtx, span := StartSpan(ctx, "test.Execute")
+	defer span.End()
+
+	assert.Equal(t, ctx, spanCtx)
+}
+
+func TestSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	operation := func(ctx context.Context, fail bool) (err error) {
+		_, span := StartSpan(ctx, "test.Execute")
+		defer EndSpan(span, &err)
+
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	assert.NoError(t, operation(context.Background(), false))
+	assert.Error(t, operation(context.Background(), true))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "test.Execute", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "boom", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, "exception", spans[1].Events()[0].Name)
+}
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE media ADD COLUMN search_vector TSVECTOR;
+
+-- The text search configuration is taken from default_text_search_config,
+-- which the service sets on each connection (database.text-search-config).
+-- Filenames are split on non alphanumeric characters so that
+-- "world-cup-final.jpg" is indexed as separate words.
+CREATE OR REPLACE FUNCTION media_search_vector(filename TEXT, description TEXT)
+RETURNS TSVECTOR AS $$
+BEGIN
+    RETURN setweight(to_tsvector(regexp_replace(coalesce(filename, ''), '[^[:alnum:]]+', ' ', 'g')), 'A')
+        || setweight(to_tsvector(coalesce(description, '')), 'B');
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+CREATE OR REPLACE FUNCTION update_media_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = media_search_vector(NEW.filename, NEW.description);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER update_media_search_vector
+    BEFORE INSERT OR UPDATE OF filename, description ON media
+    FOR EACH ROW
+    EXECUTE FUNCTION update_media_search_vector();
+
+-- Backfill existing rows without touching updated_at
+ALTER TABLE media DISABLE TRIGGER update_media_updated_at;
+UPDATE media SET search_vector = media_search_vector(filename, description);
+ALTER TABLE media ENABLE TRIGGER update_media_updated_at;
+
+CREATE INDEX idx_media_search_vector ON media USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_media_search_vector;
+DROP TRIGGER IF EXISTS update_media_search_vector ON media;
+DROP FUNCTION IF EXISTS update_media_search_vector();
+DROP FUNCTION IF EXISTS media_search_vector(TEXT, TEXT);
+ALTER TABLE media DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
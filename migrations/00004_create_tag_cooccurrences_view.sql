@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Co-occurrence of tags on the same media, refreshed periodically by the service.
+-- lift = P(tag AND related) / (P(tag) * P(related)), computed over tagged media.
+CREATE MATERIALIZED VIEW IF NOT EXISTS tag_cooccurrences AS
+WITH tag_counts AS (
+    SELECT tag_id, COUNT(*) AS media_count
+    FROM media_tags
+    GROUP BY tag_id
+), tagged_media AS (
+    SELECT COUNT(DISTINCT media_id) AS media_count
+    FROM media_tags
+)
+SELECT
+    a.tag_id,
+    b.tag_id AS related_tag_id,
+    COUNT(*) AS cooccurrences,
+    COUNT(*)::DOUBLE PRECISION * tm.media_count / (ca.media_count * cb.media_count) AS lift
+FROM media_tags a
+INNER JOIN media_tags b ON b.media_id = a.media_id AND b.tag_id <> a.tag_id
+INNER JOIN tag_counts ca ON ca.tag_id = a.tag_id
+INNER JOIN tag_counts cb ON cb.tag_id = b.tag_id
+CROSS JOIN tagged_media tm
+GROUP BY a.tag_id, b.tag_id, ca.media_count, cb.media_count, tm.media_count;
+
+-- Unique index required by REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX idx_tag_cooccurrences_pair ON tag_cooccurrences(tag_id, related_tag_id);
+CREATE INDEX idx_tag_cooccurrences_ranking ON tag_cooccurrences(tag_id, cooccurrences DESC, lift DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW IF EXISTS tag_cooccurrences;
+-- +goose StatementEnd
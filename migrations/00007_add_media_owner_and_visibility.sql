@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The owner is the subject of the principal which created the media, empty
+-- for media created without authentication. Existing media were visible to
+-- every client: they are shared, while new media default to private.
+ALTER TABLE media ADD COLUMN owner VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE media ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'shared';
+ALTER TABLE media ALTER COLUMN visibility SET DEFAULT 'private';
+ALTER TABLE media ADD CONSTRAINT chk_visibility CHECK (visibility IN ('private', 'shared', 'public'));
+
+-- The same file can be uploaded by several owners
+ALTER TABLE media DROP CONSTRAINT unq_filename_sha256;
+ALTER TABLE media ADD CONSTRAINT unq_owner_filename_sha256 UNIQUE (owner, filename, sha256);
+
+CREATE INDEX idx_media_visibility ON media(visibility);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_media_visibility;
+ALTER TABLE media DROP CONSTRAINT unq_owner_filename_sha256;
+ALTER TABLE media ADD CONSTRAINT unq_filename_sha256 UNIQUE (filename, sha256);
+ALTER TABLE media DROP CONSTRAINT chk_visibility;
+ALTER TABLE media DROP COLUMN visibility;
+ALTER TABLE media DROP COLUMN owner;
+-- +goose StatementEnd
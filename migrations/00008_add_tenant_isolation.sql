@@ -0,0 +1,130 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The tenant of a session is set by the service on each connection it hands
+-- out (app.tenant_id). Sessions which did not set it act on the default
+-- tenant, which also owns the data predating multi-tenancy.
+CREATE OR REPLACE FUNCTION current_tenant()
+RETURNS TEXT AS $$
+    SELECT COALESCE(NULLIF(current_setting('app.tenant_id', true), ''), 'default');
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE tags ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT current_tenant();
+ALTER TABLE media ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT current_tenant();
+ALTER TABLE media_tags ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT current_tenant();
+
+-- Tag names and files are unique within a tenant
+ALTER TABLE tags DROP CONSTRAINT tags_name_key;
+ALTER TABLE tags ADD CONSTRAINT unq_tags_tenant_name UNIQUE (tenant_id, name);
+ALTER TABLE media DROP CONSTRAINT unq_owner_filename_sha256;
+ALTER TABLE media ADD CONSTRAINT unq_tenant_owner_filename_sha256 UNIQUE (tenant_id, owner, filename, sha256);
+
+CREATE INDEX idx_media_tenant_id ON media(tenant_id);
+CREATE INDEX idx_media_tags_tenant_id ON media_tags(tenant_id);
+
+-- Rows are only visible to, and can only be written by, sessions of their
+-- tenant. FORCE applies the policies to the owner of the tables as well, only
+-- superusers and roles with BYPASSRLS are exempted. Maintenance statements
+-- spanning every tenant set app.all_tenants for their transaction.
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE tags FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON tags
+    USING (tenant_id = current_tenant() OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_tenant());
+
+ALTER TABLE media ENABLE ROW LEVEL SECURITY;
+ALTER TABLE media FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON media
+    USING (tenant_id = current_tenant() OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_tenant());
+
+ALTER TABLE media_tags ENABLE ROW LEVEL SECURITY;
+ALTER TABLE media_tags FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON media_tags
+    USING (tenant_id = current_tenant() OR current_setting('app.all_tenants', true) = 'on')
+    WITH CHECK (tenant_id = current_tenant());
+
+-- API keys are not tenant data: they are looked up before the tenant of the
+-- request is known. A key bound to a tenant can only act on it, a key without
+-- tenant can act on any.
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(63);
+
+-- Co-occurrences are computed within each tenant
+DROP MATERIALIZED VIEW IF EXISTS tag_cooccurrences;
+CREATE MATERIALIZED VIEW tag_cooccurrences AS
+WITH tag_counts AS (
+    SELECT tenant_id, tag_id, COUNT(*) AS media_count
+    FROM media_tags
+    GROUP BY tenant_id, tag_id
+), tagged_media AS (
+    SELECT tenant_id, COUNT(DISTINCT media_id) AS media_count
+    FROM media_tags
+    GROUP BY tenant_id
+)
+SELECT
+    a.tenant_id,
+    a.tag_id,
+    b.tag_id AS related_tag_id,
+    COUNT(*) AS cooccurrences,
+    COUNT(*)::DOUBLE PRECISION * tm.media_count / (ca.media_count * cb.media_count) AS lift
+FROM media_tags a
+INNER JOIN media_tags b ON b.media_id = a.media_id AND b.tag_id <> a.tag_id
+INNER JOIN tag_counts ca ON ca.tag_id = a.tag_id
+INNER JOIN tag_counts cb ON cb.tag_id = b.tag_id
+INNER JOIN tagged_media tm ON tm.tenant_id = a.tenant_id
+GROUP BY a.tenant_id, a.tag_id, b.tag_id, ca.media_count, cb.media_count, tm.media_count;
+
+CREATE UNIQUE INDEX idx_tag_cooccurrences_pair ON tag_cooccurrences(tag_id, related_tag_id);
+CREATE INDEX idx_tag_cooccurrences_ranking ON tag_cooccurrences(tag_id, cooccurrences DESC, lift DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW IF EXISTS tag_cooccurrences;
+CREATE MATERIALIZED VIEW tag_cooccurrences AS
+WITH tag_counts AS (
+    SELECT tag_id, COUNT(*) AS media_count
+    FROM media_tags
+    GROUP BY tag_id
+), tagged_media AS (
+    SELECT COUNT(DISTINCT media_id) AS media_count
+    FROM media_tags
+)
+SELECT
+    a.tag_id,
+    b.tag_id AS related_tag_id,
+    COUNT(*) AS cooccurrences,
+    COUNT(*)::DOUBLE PRECISION * tm.media_count / (ca.media_count * cb.media_count) AS lift
+FROM media_tags a
+INNER JOIN media_tags b ON b.media_id = a.media_id AND b.tag_id <> a.tag_id
+INNER JOIN tag_counts ca ON ca.tag_id = a.tag_id
+INNER JOIN tag_counts cb ON cb.tag_id = b.tag_id
+CROSS JOIN tagged_media tm
+GROUP BY a.tag_id, b.tag_id, ca.media_count, cb.media_count, tm.media_count;
+
+CREATE UNIQUE INDEX idx_tag_cooccurrences_pair ON tag_cooccurrences(tag_id, related_tag_id);
+CREATE INDEX idx_tag_cooccurrences_ranking ON tag_cooccurrences(tag_id, cooccurrences DESC, lift DESC);
+
+DROP POLICY IF EXISTS tenant_isolation ON media_tags;
+ALTER TABLE media_tags NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE media_tags DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON media;
+ALTER TABLE media NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE media DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON tags;
+ALTER TABLE tags NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE tags DISABLE ROW LEVEL SECURITY;
+
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+
+DROP INDEX IF EXISTS idx_media_tags_tenant_id;
+DROP INDEX IF EXISTS idx_media_tenant_id;
+ALTER TABLE media DROP CONSTRAINT unq_tenant_owner_filename_sha256;
+ALTER TABLE media ADD CONSTRAINT unq_owner_filename_sha256 UNIQUE (owner, filename, sha256);
+ALTER TABLE tags DROP CONSTRAINT unq_tags_tenant_name;
+ALTER TABLE tags ADD CONSTRAINT tags_name_key UNIQUE (name);
+
+ALTER TABLE media_tags DROP COLUMN tenant_id;
+ALTER TABLE media DROP COLUMN tenant_id;
+ALTER TABLE tags DROP COLUMN tenant_id;
+DROP FUNCTION IF EXISTS current_tenant();
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Roles granted to the principals. Like the API keys, assignments are looked
+-- up for the principal of a request, within the tenant of the request or in
+-- every tenant when tenant_id is NULL.
+CREATE TABLE IF NOT EXISTS role_assignments (
+    subject VARCHAR(255) NOT NULL,
+    tenant_id VARCHAR(63),
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_role CHECK (role IN ('viewer', 'editor', 'admin'))
+);
+
+-- A principal holds a single role per tenant
+CREATE UNIQUE INDEX unq_role_assignments_subject_tenant ON role_assignments (subject, COALESCE(tenant_id, ''));
+
+-- Audit trail, append only
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(63) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_occurred_at ON audit_events (occurred_at);
+CREATE INDEX idx_audit_events_subject ON audit_events (subject);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_events;
+DROP TABLE IF EXISTS role_assignments;
+-- +goose StatementEnd
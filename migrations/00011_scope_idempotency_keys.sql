@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Idempotency keys are chosen by the clients: a key is scoped to the tenant
+-- and the subject which sent it, so that clients cannot collide on, or block,
+-- the keys of one another. Existing keys are kept in the default tenant.
+ALTER TABLE idempotency_keys ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+ALTER TABLE idempotency_keys ADD COLUMN subject VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE idempotency_keys ALTER COLUMN subject DROP DEFAULT;
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (tenant_id, subject, key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Keys used by several clients cannot be told apart anymore, only the most
+-- recent one is kept
+DELETE FROM idempotency_keys a
+USING idempotency_keys b
+WHERE a.key = b.key AND (a.created_at, a.ctid) < (b.created_at, b.ctid);
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
+ALTER TABLE idempotency_keys DROP COLUMN subject;
+ALTER TABLE idempotency_keys DROP COLUMN tenant_id;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every API key belongs to a tenant, the keys created without one belong to
+-- the default tenant. Acting on other tenants is granted by the tenants:all
+-- scope, not by the absence of a tenant: the bootstrap key, created by the
+-- service as the operator's credentials, is the only key granted it here.
+UPDATE api_keys SET tenant_id = 'default' WHERE tenant_id IS NULL;
+ALTER TABLE api_keys ALTER COLUMN tenant_id SET NOT NULL;
+
+UPDATE api_keys
+SET scopes = array_append(scopes, 'tenants:all')
+WHERE name = 'bootstrap' AND 'admin' = ANY(scopes) AND NOT 'tenants:all' = ANY(scopes);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE api_keys SET scopes = array_remove(scopes, 'tenants:all');
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Only the owner of a materialized view can refresh it. The service connects
+-- as a role owning nothing, the function refreshes the view on behalf of the
+-- owner of the schema. The caller lifts the tenant isolation for the
+-- refresh to span every tenant.
+CREATE FUNCTION refresh_tag_cooccurrences()
+RETURNS VOID AS $$
+    REFRESH MATERIALIZED VIEW CONCURRENTLY tag_cooccurrences;
+$$ LANGUAGE sql SECURITY DEFINER SET search_path = public, pg_temp;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS refresh_tag_cooccurrences();
+-- +goose StatementEnd
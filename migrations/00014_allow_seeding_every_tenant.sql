@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Maintenance statements spanning every tenant, such as the seed of the
+-- development data, write the rows of every tenant as well as read them.
+ALTER POLICY tenant_isolation ON tags
+    WITH CHECK (tenant_id = current_tenant() OR current_setting('app.all_tenants', true) = 'on');
+ALTER POLICY tenant_isolation ON media
+    WITH CHECK (tenant_id = current_tenant() OR current_setting('app.all_tenants', true) = 'on');
+ALTER POLICY tenant_isolation ON media_tags
+    WITH CHECK (tenant_id = current_tenant() OR current_setting('app.all_tenants', true) = 'on');
+
+-- The seed loads the tables in any order, deferring the foreign keys to the
+-- end of its transaction. Declaring them deferrable spares it altering them,
+-- which only the owner of the tables can do. They are still checked on each
+-- statement unless deferred.
+ALTER TABLE media_tags ALTER CONSTRAINT media_tags_media_id_fkey DEFERRABLE INITIALLY IMMEDIATE;
+ALTER TABLE media_tags ALTER CONSTRAINT media_tags_tag_id_fkey DEFERRABLE INITIALLY IMMEDIATE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE media_tags ALTER CONSTRAINT media_tags_tag_id_fkey NOT DEFERRABLE;
+ALTER TABLE media_tags ALTER CONSTRAINT media_tags_media_id_fkey NOT DEFERRABLE;
+
+ALTER POLICY tenant_isolation ON media_tags WITH CHECK (tenant_id = current_tenant());
+ALTER POLICY tenant_isolation ON media WITH CHECK (tenant_id = current_tenant());
+ALTER POLICY tenant_isolation ON tags WITH CHECK (tenant_id = current_tenant());
+-- +goose StatementEnd
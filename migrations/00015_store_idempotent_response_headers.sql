@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Headers of the stored responses, such as Location or ETag, replayed along
+-- with the body. Responses stored before keep replaying without them.
+ALTER TABLE idempotency_keys ADD COLUMN headers JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys DROP COLUMN headers;
+-- +goose StatementEnd